@@ -5,8 +5,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	duoapi "github.com/duosecurity/duo_api_golang"
 )
@@ -14,7 +15,130 @@ import (
 // Client provides access to Duo's admin API.
 type Client struct {
 	duoapi.DuoApi
-}
+	pageConcurrency int
+}
+
+// API is the set of admin API methods provided by Client.  Depend on API
+// rather than *Client to substitute a fake, such as fakes.Admin, in tests.
+type API interface {
+	GetUsers(options ...func(*url.Values)) (*GetUsersResult, error)
+	GetUser(userID string) (*GetUserResult, error)
+	CreateUser(user UserUpdate) (*GetUserResult, error)
+	BulkCreateUsers(users []User) (*BulkCreateUsersResult, error)
+	ModifyUser(userID string, user UserUpdate) (*GetUserResult, error)
+	DeleteUser(userID string) (*duoapi.StatResult, error)
+	GetUserGroups(userID string, options ...func(*url.Values)) (*GetGroupsResult, error)
+	AssociateGroupWithUser(userID string, groupID string) (*duoapi.StatResult, error)
+	DisassociateGroupFromUser(userID string, groupID string) (*duoapi.StatResult, error)
+	GetUserPhones(userID string, options ...func(*url.Values)) (*GetPhonesResult, error)
+	AssociatePhoneWithUser(userID, phoneID string) (*duoapi.StatResult, error)
+	DisassociatePhoneFromUser(userID, phoneID string) (*duoapi.StatResult, error)
+	GetUserTokens(userID string, options ...func(*url.Values)) (*GetTokensResult, error)
+	AssociateUserToken(userID, tokenID string) (*StringResult, error)
+	DisassociateUserToken(userID, tokenID string) (*StringResult, error)
+	GetUserU2FTokens(userID string, options ...func(*url.Values)) (*GetU2FTokensResult, error)
+	GetUserWebAuthnCredentials(userID string, options ...func(*url.Values)) (*GetWebAuthnCredentialsResult, error)
+	GetUserBypassCodes(userID string, options ...func(*url.Values)) (*StringArrayResult, error)
+	CreateUserBypassCodes(userID string, create BypassCodesCreate) (*StringArrayResult, error)
+	ListUserBypassCodes(userID string, options ...func(*url.Values)) (*GetBypassCodesResult, error)
+	EnrollUser(username, email string, validSecs uint64) (*StringResult, error)
+	SendVerificationPush(userID, phoneID string) (*VerificationPushResult, error)
+	GetVerificationPushResponse(userID, pushID string) (*VerificationPushResponseResult, error)
+	WaitForVerificationPush(userID, pushID string, timeout time.Duration) (*VerificationPushResponseResult, error)
+	GetUsersByUsernames(usernames []string) (*UserLookupResult, error)
+	GetUsersByIDs(userIDs []string) (*UserLookupResult, error)
+	GetUsersByEmails(emails []string) (*UserLookupResult, error)
+	EnsureUser(desired DesiredUser) (*EnsureUserResult, error)
+	Users(options ...func(*url.Values)) *UserIterator
+
+	GetGroups(options ...func(*url.Values)) (*GetGroupsResult, error)
+	GetGroup(groupID string) (*GetGroupResult, error)
+	CreateGroup(group GroupUpdate) (*GetGroupResult, error)
+	ModifyGroup(groupID string, group GroupUpdate) (*GetGroupResult, error)
+	DeleteGroup(groupID string) (*duoapi.StatResult, error)
+	GetGroupUsers(groupID string, options ...func(*url.Values)) (*GetUsersResult, error)
+	EnsureGroup(desired GroupUpdate) (*EnsureGroupResult, error)
+	Groups(options ...func(*url.Values)) *GroupIterator
+
+	GetPhones(options ...func(*url.Values)) (*GetPhonesResult, error)
+	GetPhone(phoneID string) (*GetPhoneResult, error)
+	CreatePhone(phone PhoneUpdate) (*GetPhoneResult, error)
+	ModifyPhone(phoneID string, phone PhoneUpdate) (*GetPhoneResult, error)
+	DeletePhone(phoneID string) (*duoapi.StatResult, error)
+	CreateActivationURL(phoneID string, options ...func(*url.Values)) (*ActivationURLResult, error)
+	SendSMSActivation(phoneID string, options ...func(*url.Values)) (*SMSActivationResult, error)
+	SendSMSPasscodes(phoneID string) (*duoapi.StatResult, error)
+	Phones(options ...func(*url.Values)) *PhoneIterator
+
+	GetTokens(options ...func(*url.Values)) (*GetTokensResult, error)
+	GetToken(tokenID string) (*GetTokenResult, error)
+	CreateToken(token TokenCreate) (*GetTokenResult, error)
+	ResyncToken(tokenID, code1, code2, code3 string) (*StringResult, error)
+	DeleteToken(tokenID string) (*duoapi.StatResult, error)
+	Tokens(options ...func(*url.Values)) *TokenIterator
+
+	ListBypassCodes(options ...func(*url.Values)) (*GetBypassCodesResult, error)
+	GetBypassCode(bypassCodeID string) (*GetBypassCodeResult, error)
+	DeleteBypassCode(bypassCodeID string) (*duoapi.StatResult, error)
+
+	GetU2FTokens(options ...func(*url.Values)) (*GetU2FTokensResult, error)
+	GetU2FToken(registrationID string) (*GetU2FTokenResult, error)
+	DeleteU2FToken(registrationID string) (*duoapi.StatResult, error)
+	U2FTokens(options ...func(*url.Values)) *U2FTokenIterator
+
+	GetWebAuthnCredentials(options ...func(*url.Values)) (*GetWebAuthnCredentialsResult, error)
+	GetWebAuthnCredential(webAuthnKey string) (*GetWebAuthnCredentialResult, error)
+	DeleteWebAuthnCredential(webAuthnKey string) (*duoapi.StatResult, error)
+
+	GetAdmins(options ...func(*url.Values)) (*GetAdminsResult, error)
+	GetAdmin(adminID string) (*GetAdminResult, error)
+	CreateAdmin(admin AdminUpdate) (*GetAdminResult, error)
+	ModifyAdmin(adminID string, admin AdminUpdate) (*GetAdminResult, error)
+	DeleteAdmin(adminID string) (*duoapi.StatResult, error)
+	ResetAdminAuthAttempts(adminID string) (*duoapi.StatResult, error)
+	ClearAdminExpiration(adminID string) (*duoapi.StatResult, error)
+	CreateAdminActivationLink(adminID string) (*AdminActivationLinkResult, error)
+	SendAdminActivationEmail(adminID string) (*AdminActivationLinkResult, error)
+	DeleteAdminActivationLink(adminID string) (*duoapi.StatResult, error)
+	GetAdminActivations(options ...func(*url.Values)) (*GetAdminActivationsResult, error)
+	CreateAdminActivation(activation AdminActivationCreate) (*AdminActivationResult, error)
+	DeleteAdminActivation(activationID string) (*duoapi.StatResult, error)
+
+	GetAdminUnits(options ...func(*url.Values)) (*GetAdminUnitsResult, error)
+	GetAdminUnit(adminUnitID string) (*GetAdminUnitResult, error)
+	CreateAdminUnit(unit AdminUnitUpdate) (*GetAdminUnitResult, error)
+	ModifyAdminUnit(adminUnitID string, unit AdminUnitUpdate) (*GetAdminUnitResult, error)
+	DeleteAdminUnit(adminUnitID string) (*duoapi.StatResult, error)
+	AssignAdminToUnit(adminUnitID, adminID string) (*duoapi.StatResult, error)
+	UnassignAdminFromUnit(adminUnitID, adminID string) (*duoapi.StatResult, error)
+	AssignGroupToUnit(adminUnitID, groupID string) (*duoapi.StatResult, error)
+	UnassignGroupFromUnit(adminUnitID, groupID string) (*duoapi.StatResult, error)
+	AssignIntegrationToUnit(adminUnitID, integrationKey string) (*duoapi.StatResult, error)
+	UnassignIntegrationFromUnit(adminUnitID, integrationKey string) (*duoapi.StatResult, error)
+
+	GetIntegrations(options ...func(*url.Values)) (*GetIntegrationsResult, error)
+	GetIntegration(integrationKey string) (*GetIntegrationResult, error)
+	CreateIntegration(integration IntegrationUpdate) (*GetIntegrationResult, error)
+	ModifyIntegration(integrationKey string, integration IntegrationUpdate) (*GetIntegrationResult, error)
+	DeleteIntegration(integrationKey string) (*duoapi.StatResult, error)
+	GetIntegrationSecretKey(integrationKey string) (*IntegrationSecretKeyResult, error)
+	RotateIntegrationSecretKey(integrationKey string) (string, error)
+
+	GetPolicies(options ...func(*url.Values)) (*GetPoliciesResult, error)
+	GetPolicy(policyKey string) (*GetPolicyResult, error)
+	GetGlobalPolicy() (*GetPolicyResult, error)
+	CreatePolicy(policy PolicyUpdate) (*GetPolicyResult, error)
+	UpdatePolicy(policyKey string, policy PolicyUpdate) (*GetPolicyResult, error)
+	CopyPolicy(policyKey string, newNames []string) (*CopyPolicyResult, error)
+	DeletePolicy(policyKey string) (*duoapi.StatResult, error)
+	CalculatePolicy(integrationKey, userID string) (*EffectivePolicyResult, error)
+
+	GetAuthLogs(mintime time.Time, window time.Duration, options ...func(*url.Values)) (*AuthLogResult, error)
+	GetAdminLogs(mintime time.Time, options ...func(*url.Values)) (*AdminLogResult, error)
+	GetTelephonyLogs(mintime time.Time, options ...func(*url.Values)) (*TelephonyLogResult, error)
+}
+
+var _ API = (*Client)(nil)
 
 type ListResultMetadata struct {
 	NextOffset   json.Number `json:"next_offset"`
@@ -30,9 +154,13 @@ func (l *ListResult) metadata() ListResultMetadata {
 	return l.Metadata
 }
 
+func (l *ListResult) setMetadata(metadata ListResultMetadata) {
+	l.Metadata = metadata
+}
+
 // New initializes an admin API Client struct.
 func New(base duoapi.DuoApi) *Client {
-	return &Client{base}
+	return &Client{DuoApi: base}
 }
 
 // User models a single user.
@@ -57,33 +185,34 @@ type User struct {
 	Username          string `url:"username"`
 }
 
+func (u User) id() string {
+	return u.UserID
+}
+
 // URLValues transforms a User into url.Values using the 'url' struct tag to
 // define the key of the map. Fields are skiped if the value is empty.
 func (u *User) URLValues() url.Values {
-	params := url.Values{}
+	// User holds only strings and string pointers, which always encode.
+	params, _ := EncodeValues(u)
+	return params
+}
 
-	t := reflect.TypeOf(u).Elem()
-	v := reflect.ValueOf(u).Elem()
+// UserUpdate holds the fields of a user to create or modify.  Nil fields
+// are left unchanged; name fields in NullFields to clear them.
+type UserUpdate struct {
+	Username  *string     `url:"username"`
+	Alias1    *string     `url:"alias1"`
+	Alias2    *string     `url:"alias2"`
+	Alias3    *string     `url:"alias3"`
+	Alias4    *string     `url:"alias4"`
+	RealName  *string     `url:"realname"`
+	Email     *string     `url:"email"`
+	Status    *UserStatus `url:"status"`
+	Notes     *string     `url:"notes"`
+	FirstName *string     `url:"firstname"`
+	LastName  *string     `url:"lastname"`
 
-	// Iterate over all available struct fields
-	for i := 0; i < t.NumField(); i++ {
-		tag := t.Field(i).Tag.Get("url")
-		if tag == "" {
-			continue
-		}
-		// Skip fields have a zero value.
-		if v.Field(i).Interface() == reflect.Zero(v.Field(i).Type()).Interface() {
-			continue
-		}
-		var val string
-		if t.Field(i).Type.Kind() == reflect.Ptr {
-			val = fmt.Sprintf("%v", v.Field(i).Elem())
-		} else {
-			val = fmt.Sprintf("%v", v.Field(i))
-		}
-		params[tag] = []string{val}
-	}
-	return params
+	NullFields []string
 }
 
 // Group models a group to which users may belong.
@@ -98,6 +227,26 @@ type Group struct {
 	VoiceEnabled     bool `json:"voice_enabled"`
 }
 
+func (g Group) id() string {
+	return g.GroupID
+}
+
+// GroupUpdate holds the fields of a group to create or modify.  Nil fields
+// are left unchanged; name fields in NullFields to clear them.
+type GroupUpdate struct {
+	Name   *string      `url:"name"`
+	Desc   *string      `url:"desc"`
+	Status *GroupStatus `url:"status"`
+
+	// Deprecated by Duo; authentication methods are set in policies.
+	PushEnabled      *bool `url:"push_enabled"`
+	SMSEnabled       *bool `url:"sms_enabled"`
+	VoiceEnabled     *bool `url:"voice_enabled"`
+	MobileOTPEnabled *bool `url:"mobile_otp_enabled"`
+
+	NullFields []string
+}
+
 // Phone models a user's phone.
 type Phone struct {
 	Activated        bool
@@ -119,6 +268,24 @@ type Phone struct {
 	Users            []User
 }
 
+func (p Phone) id() string {
+	return p.PhoneID
+}
+
+// PhoneUpdate holds the fields of a phone to create or modify.  Nil fields
+// are left unchanged; name fields in NullFields to clear them.
+type PhoneUpdate struct {
+	Number    *string        `url:"number"`
+	Name      *string        `url:"name"`
+	Extension *string        `url:"extension"`
+	Type      *PhoneType     `url:"type"`
+	Platform  *PhonePlatform `url:"platform"`
+	Predelay  *string        `url:"predelay"`
+	Postdelay *string        `url:"postdelay"`
+
+	NullFields []string
+}
+
 // Token models a hardware security token.
 type Token struct {
 	TokenID  string `json:"token_id"`
@@ -128,6 +295,22 @@ type Token struct {
 	Users    []User
 }
 
+func (t Token) id() string {
+	return t.TokenID
+}
+
+// TokenCreate holds the fields of a hardware token to create.  Type and
+// Serial are required; the others depend on the type of token.
+type TokenCreate struct {
+	Type      TokenType `url:"type"`
+	Serial    string    `url:"serial"`
+	Secret    string    `url:"secret"`
+	Counter   *int      `url:"counter"`
+	TOTPStep  *int      `url:"totp_step"`
+	PrivateID string    `url:"private_id"`
+	AESKey    string    `url:"aes_key"`
+}
+
 // U2FToken models a U2F security token.
 type U2FToken struct {
 	DateAdded      uint64 `json:"date_added"`
@@ -135,6 +318,33 @@ type U2FToken struct {
 	User           *User
 }
 
+func (t U2FToken) id() string {
+	return t.RegistrationID
+}
+
+// WebAuthnCredential models a WebAuthn credential, such as a security key or
+// platform authenticator.
+type WebAuthnCredential struct {
+	// Admin is set instead of User for an administrator's credential.
+	Admin          *WebAuthnAdmin
+	CredentialName string `json:"credential_name"`
+	DateAdded      uint64 `json:"date_added"`
+	Label          string
+	User           *User
+	WebAuthnKey    string `json:"webauthnkey"`
+}
+
+func (c WebAuthnCredential) id() string {
+	return c.WebAuthnKey
+}
+
+// WebAuthnAdmin identifies the administrator owning a WebAuthn credential.
+type WebAuthnAdmin struct {
+	AdminID string `json:"admin_id"`
+	Email   string
+	Name    string
+}
+
 // Common URL options
 
 // Limit sets the optional limit parameter for an API request.
@@ -203,6 +413,7 @@ func (c *Client) GetUsers(options ...func(*url.Values)) (*GetUsersResult, error)
 
 type responsePage interface {
 	metadata() ListResultMetadata
+	setMetadata(ListResultMetadata)
 	getResponse() interface{}
 	appendResponse(interface{})
 }
@@ -225,14 +436,22 @@ func (c *Client) retrieveItems(
 			return nil, firstErr
 		}
 
+		if c.pageConcurrency > 1 {
+			return c.prefetchItems(params, fetcher, accumulator)
+		}
+
 		params.Set("offset", accumulator.metadata().NextOffset.String())
 		for params.Get("offset") != "" {
 			nextResult, err := fetcher(params)
+			if err == nil {
+				err = pageStat(nextResult)
+			}
 			if err != nil {
 				return nil, err
 			}
-			nextResult.appendResponse(accumulator.getResponse())
-			accumulator = nextResult
+			// Keep the items in API order, and the metadata of the last page.
+			accumulator.appendResponse(nextResult.getResponse())
+			accumulator.setMetadata(nextResult.metadata())
 			params.Set("offset", accumulator.metadata().NextOffset.String())
 		}
 		return accumulator, nil
@@ -275,10 +494,25 @@ func (c *Client) GetUser(userID string) (*GetUserResult, error) {
 
 // CreateUser calls POST /admin/v1/users
 // See https://duo.com/docs/adminapi#create-user
-func (c *Client) CreateUser(params url.Values) (*GetUserResult, error) {
-	path := "/admin/v1/users"
+func (c *Client) CreateUser(user UserUpdate) (*GetUserResult, error) {
+	v := &validator{}
+	if user.Username == nil {
+		v.required("username", "")
+	}
+	user.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
 
-	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	params, err := EncodeValues(user)
+	if err != nil {
+		return nil, err
+	}
+	return c.createUser(params)
+}
+
+func (c *Client) createUser(params url.Values) (*GetUserResult, error) {
+	_, body, err := c.SignedCall(http.MethodPost, "/admin/v1/users", params, duoapi.UseTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -293,9 +527,18 @@ func (c *Client) CreateUser(params url.Values) (*GetUserResult, error) {
 
 // ModifyUser calls POST /admin/v1/users/:user_id
 // See https://duo.com/docs/adminapi#modify-user
-func (c *Client) ModifyUser(userID string, params url.Values) (*GetUserResult, error) {
+func (c *Client) ModifyUser(userID string, user UserUpdate) (*GetUserResult, error) {
 	path := fmt.Sprintf("/admin/v1/users/%s", userID)
 
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(user)
+	if err != nil {
+		return nil, err
+	}
+
 	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
 	if err != nil {
 		return nil, err
@@ -436,6 +679,45 @@ func (c *Client) retrieveUserPhones(userID string, params url.Values) (*GetPhone
 	return result, nil
 }
 
+// AssociatePhoneWithUser calls POST /admin/v1/users/:user_id/phones
+// See https://duo.com/docs/adminapi#associate-phone-with-user
+func (c *Client) AssociatePhoneWithUser(userID, phoneID string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/users/%s/phones", userID)
+
+	params := url.Values{}
+	params.Set("phone_id", phoneID)
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DisassociatePhoneFromUser calls DELETE /admin/v1/users/:user_id/phones/:phone_id
+// See https://duo.com/docs/adminapi#disassociate-phone-from-user
+func (c *Client) DisassociatePhoneFromUser(userID, phoneID string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/users/%s/phones/%s", userID, phoneID)
+
+	_, body, err := c.SignedCall(http.MethodDelete, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // GetUserTokens calls GET /admin/v1/users/:user_id/tokens
 // See https://duo.com/docs/adminapi#retrieve-hardware-tokens-by-user-id
 func (c *Client) GetUserTokens(userID string, options ...func(*url.Values)) (*GetTokensResult, error) {
@@ -498,6 +780,24 @@ func (c *Client) AssociateUserToken(userID, tokenID string) (*StringResult, erro
 	return result, nil
 }
 
+// DisassociateUserToken calls DELETE /admin/v1/users/:user_id/tokens/:token_id
+// See https://duo.com/docs/adminapi#disassociate-hardware-token-from-user
+func (c *Client) DisassociateUserToken(userID, tokenID string) (*StringResult, error) {
+	path := fmt.Sprintf("/admin/v1/users/%s/tokens/%s", userID, tokenID)
+
+	_, body, err := c.SignedCall(http.MethodDelete, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StringResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // GetUserU2FTokens calls GET /admin/v1/users/:user_id/u2ftokens
 // See https://duo.com/docs/adminapi#retrieve-u2f-tokens-by-user-id
 func (c *Client) GetUserU2FTokens(userID string, options ...func(*url.Values)) (*GetU2FTokensResult, error) {
@@ -533,14 +833,82 @@ func (c *Client) retrieveUserU2FTokens(userID string, params url.Values) (*GetU2
 	return result, nil
 }
 
+// GetUserWebAuthnCredentials calls GET /admin/v1/users/:user_id/webauthncredentials
+// See https://duo.com/docs/adminapi#retrieve-webauthn-credentials-by-user-id
+func (c *Client) GetUserWebAuthnCredentials(userID string, options ...func(*url.Values)) (*GetWebAuthnCredentialsResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveWebAuthnCredentials(fmt.Sprintf("/admin/v1/users/%s/webauthncredentials", userID), params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetWebAuthnCredentialsResult), nil
+}
+
 // StringArrayResult models response containing an array of strings.
 type StringArrayResult struct {
 	duoapi.StatResult
 	Response []string
 }
 
+// BypassCodesCreate holds the options of a request to create bypass codes.
+// Nil and empty fields are left to Duo's defaults.
+type BypassCodesCreate struct {
+	// Count is the number of codes to generate, from 1 to 10.  It cannot be
+	// combined with Codes.
+	Count *int `url:"count"`
+	// Codes holds up to 10 codes to use instead of generated ones.
+	Codes []string `url:"codes,comma"`
+	// ValidSecs is how long the codes are valid for, in seconds, or 0 for
+	// codes which never expire.
+	ValidSecs *int `url:"valid_secs"`
+	// ReuseCount is the number of times each code may be used, or 0 for
+	// unlimited use.
+	ReuseCount *int `url:"reuse_count"`
+}
+
+// CreateUserBypassCodes calls POST /admin/v1/users/:user_id/bypass_codes
+// See https://duo.com/docs/adminapi#create-bypass-codes-for-user
+// New codes replace the user's existing codes.  The response holds the
+// codes themselves, which cannot be retrieved again.
+func (c *Client) CreateUserBypassCodes(userID string, create BypassCodesCreate) (*StringArrayResult, error) {
+	path := fmt.Sprintf("/admin/v1/users/%s/bypass_codes", userID)
+
+	if err := create.Validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(create)
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StringArrayResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // GetUserBypassCodes calls POST /admin/v1/users/:user_id/bypass_codes
 // see https://duo.com/docs/adminapi#create-bypass-codes-for-user
+//
+// Deprecated: GetUserBypassCodes creates codes; use CreateUserBypassCodes,
+// or ListUserBypassCodes to list existing codes.
 func (c *Client) GetUserBypassCodes(userID string, options ...func(*url.Values)) (*StringArrayResult, error) {
 	path := fmt.Sprintf("/admin/v1/users/%s/bypass_codes", userID)
 
@@ -563,6 +931,146 @@ func (c *Client) GetUserBypassCodes(userID string, options ...func(*url.Values))
 	return result, nil
 }
 
+// ListUserBypassCodes calls GET /admin/v1/users/:user_id/bypass_codes
+// See https://duo.com/docs/adminapi#retrieve-bypass-codes-by-user-id
+func (c *Client) ListUserBypassCodes(userID string, options ...func(*url.Values)) (*GetBypassCodesResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveBypassCodes(fmt.Sprintf("/admin/v1/users/%s/bypass_codes", userID), params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetBypassCodesResult), nil
+}
+
+// EnrollUser calls POST /admin/v1/users/enroll
+// See https://duo.com/docs/adminapi#enroll-user
+//
+// Duo emails an enrollment link to email and creates the user with username
+// once the link is used.  A validSecs of 0 uses Duo's default lifetime for
+// the link, 30 days.  The response is the enrollment code.
+func (c *Client) EnrollUser(username, email string, validSecs uint64) (*StringResult, error) {
+	v := &validator{}
+	v.required("username", username)
+	v.required("email", email)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("username", username)
+	params.Set("email", email)
+	if validSecs > 0 {
+		params.Set("valid_secs", strconv.FormatUint(validSecs, 10))
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, "/admin/v1/users/enroll", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StringResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// VerificationPush models a verification push sent to a user's phone.
+type VerificationPush struct {
+	// ConfirmationCode is shown to the user in the push, so that they can
+	// read it back to the help desk.
+	ConfirmationCode string `json:"confirmation_code"`
+	PushID           string `json:"push_id"`
+}
+
+// VerificationPushResult models responses containing a sent verification
+// push.
+type VerificationPushResult struct {
+	duoapi.StatResult
+	Response VerificationPush
+}
+
+// SendVerificationPush calls POST /admin/v1/users/:user_id/send_verification_push
+// See https://duo.com/docs/adminapi#send-verification-push
+func (c *Client) SendVerificationPush(userID, phoneID string) (*VerificationPushResult, error) {
+	path := fmt.Sprintf("/admin/v1/users/%s/send_verification_push", userID)
+
+	v := &validator{}
+	v.required("phone_id", phoneID)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("phone_id", phoneID)
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerificationPushResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// VerificationPushStatus is the user's answer to a verification push.
+type VerificationPushStatus string
+
+// Verification push answers.
+const (
+	VerificationPushApprove VerificationPushStatus = "approve"
+	VerificationPushDeny    VerificationPushStatus = "deny"
+	VerificationPushFraud   VerificationPushStatus = "fraud"
+	VerificationPushWaiting VerificationPushStatus = "waiting"
+)
+
+// VerificationPushResponse models the state of a verification push.
+type VerificationPushResponse struct {
+	PushID string `json:"push_id"`
+	Result VerificationPushStatus
+}
+
+// VerificationPushResponseResult models responses containing the state of a
+// verification push.
+type VerificationPushResponseResult struct {
+	duoapi.StatResult
+	Response VerificationPushResponse
+}
+
+// GetVerificationPushResponse calls GET /admin/v1/users/:user_id/verification_push_response
+// See https://duo.com/docs/adminapi#retrieve-verification-push-response
+func (c *Client) GetVerificationPushResponse(userID, pushID string) (*VerificationPushResponseResult, error) {
+	path := fmt.Sprintf("/admin/v1/users/%s/verification_push_response", userID)
+
+	params := url.Values{}
+	params.Set("push_id", pushID)
+
+	_, body, err := c.SignedCall(http.MethodGet, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerificationPushResponseResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Group methods
 
 // GetGroupsResult models responses containing a list of groups.
@@ -638,37 +1146,148 @@ func (c *Client) GetGroup(groupID string) (*GetGroupResult, error) {
 	return result, nil
 }
 
-// Phone methods
+// CreateGroup calls POST /admin/v1/groups
+// See https://duo.com/docs/adminapi#create-group
+func (c *Client) CreateGroup(group GroupUpdate) (*GetGroupResult, error) {
+	v := &validator{}
+	if group.Name == nil {
+		v.required("name", "")
+	}
+	group.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
 
-// GetPhonesNumber sets the optional number parameter for a GetPhones request.
-func GetPhonesNumber(number string) func(*url.Values) {
-	return func(opts *url.Values) {
-		opts.Set("number", number)
+	params, err := EncodeValues(group)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// GetPhonesExtension sets the optional extension parameter for a GetPhones request.
-func GetPhonesExtension(ext string) func(*url.Values) {
-	return func(opts *url.Values) {
-		opts.Set("extension", ext)
+	_, body, err := c.SignedCall(http.MethodPost, "/admin/v1/groups", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// GetPhonesResult models responses containing a list of phones.
-type GetPhonesResult struct {
-	duoapi.StatResult
-	ListResult
-	Response []Phone
+	result := &GetGroupResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (result *GetPhonesResult) getResponse() interface{} {
-	return result.Response
-}
+// ModifyGroup calls POST /admin/v1/groups/:group_id
+// See https://duo.com/docs/adminapi#update-group
+func (c *Client) ModifyGroup(groupID string, group GroupUpdate) (*GetGroupResult, error) {
+	path := fmt.Sprintf("/admin/v1/groups/%s", groupID)
 
-func (result *GetPhonesResult) appendResponse(phones interface{}) {
-	asserted_phones := phones.([]Phone)
-	result.Response = append(result.Response, asserted_phones...)
-}
+	if err := group.Validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(group)
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetGroupResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteGroup calls DELETE /admin/v1/groups/:group_id
+// See https://duo.com/docs/adminapi#delete-group
+func (c *Client) DeleteGroup(groupID string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/groups/%s", groupID)
+
+	_, body, err := c.SignedCall(http.MethodDelete, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetGroupUsers calls GET /admin/v2/groups/:group_id/users
+// See https://duo.com/docs/adminapi#v2-groups-get-users
+// Only the UserID and Username of each user are returned.
+func (c *Client) GetGroupUsers(groupID string, options ...func(*url.Values)) (*GetUsersResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveGroupUsers(groupID, params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetUsersResult), nil
+}
+
+func (c *Client) retrieveGroupUsers(groupID string, params url.Values) (*GetUsersResult, error) {
+	path := fmt.Sprintf("/admin/v2/groups/%s/users", groupID)
+
+	_, body, err := c.SignedCall(http.MethodGet, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetUsersResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Phone methods
+
+// GetPhonesNumber sets the optional number parameter for a GetPhones request.
+func GetPhonesNumber(number string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("number", number)
+	}
+}
+
+// GetPhonesExtension sets the optional extension parameter for a GetPhones request.
+func GetPhonesExtension(ext string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("extension", ext)
+	}
+}
+
+// GetPhonesResult models responses containing a list of phones.
+type GetPhonesResult struct {
+	duoapi.StatResult
+	ListResult
+	Response []Phone
+}
+
+func (result *GetPhonesResult) getResponse() interface{} {
+	return result.Response
+}
+
+func (result *GetPhonesResult) appendResponse(phones interface{}) {
+	asserted_phones := phones.([]Phone)
+	result.Response = append(result.Response, asserted_phones...)
+}
 
 // GetPhones calls GET /admin/v1/phones
 // See https://duo.com/docs/adminapi#phones
@@ -745,6 +1364,188 @@ func (c *Client) DeletePhone(phoneID string) (*duoapi.StatResult, error) {
 	return result, nil
 }
 
+// CreatePhone calls POST /admin/v1/phones
+// See https://duo.com/docs/adminapi#create-phone
+func (c *Client) CreatePhone(phone PhoneUpdate) (*GetPhoneResult, error) {
+	if err := phone.Validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, "/admin/v1/phones", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetPhoneResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ModifyPhone calls POST /admin/v1/phones/:phone_id
+// See https://duo.com/docs/adminapi#modify-phone
+func (c *Client) ModifyPhone(phoneID string, phone PhoneUpdate) (*GetPhoneResult, error) {
+	path := fmt.Sprintf("/admin/v1/phones/%s", phoneID)
+
+	if err := phone.Validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(phone)
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetPhoneResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ActivationValidSecs sets the optional valid_secs parameter for a
+// CreateActivationURL or SendSMSActivation request: how long the activation
+// code is valid for, in seconds.
+func ActivationValidSecs(secs uint64) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("valid_secs", strconv.FormatUint(secs, 10))
+	}
+}
+
+// ActivationInstall sets the optional install parameter for a
+// CreateActivationURL or SendSMSActivation request.  When install is true,
+// an installation URL for Duo Mobile is also returned or sent.
+func ActivationInstall(install bool) func(*url.Values) {
+	return func(opts *url.Values) {
+		if install {
+			opts.Set("install", "1")
+		} else {
+			opts.Set("install", "0")
+		}
+	}
+}
+
+// SMSInstallationMsg sets the optional installation_msg parameter for a
+// SendSMSActivation request.  It must contain "<insturl>".
+func SMSInstallationMsg(msg string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("installation_msg", msg)
+	}
+}
+
+// SMSActivationMsg sets the optional activation_msg parameter for a
+// SendSMSActivation request.  It must contain "<acturl>".
+func SMSActivationMsg(msg string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("activation_msg", msg)
+	}
+}
+
+// ActivationURL models the Duo Mobile activation details of a phone.
+type ActivationURL struct {
+	ActivationBarcode string `json:"activation_barcode"`
+	ActivationURL     string `json:"activation_url"`
+	InstallationURL   string `json:"installation_url"`
+	ValidSecs         uint64 `json:"valid_secs"`
+}
+
+// ActivationURLResult models responses containing activation details.
+type ActivationURLResult struct {
+	duoapi.StatResult
+	Response ActivationURL
+}
+
+// CreateActivationURL calls POST /admin/v1/phones/:phone_id/activation_url
+// See https://duo.com/docs/adminapi#create-activation-url
+func (c *Client) CreateActivationURL(phoneID string, options ...func(*url.Values)) (*ActivationURLResult, error) {
+	path := fmt.Sprintf("/admin/v1/phones/%s/activation_url", phoneID)
+
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ActivationURLResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SMSActivation models the activation SMS sent to a phone.
+type SMSActivation struct {
+	ActivationBarcode string `json:"activation_barcode"`
+	ActivationMsg     string `json:"activation_msg"`
+	InstallationMsg   string `json:"installation_msg"`
+	ValidSecs         uint64 `json:"valid_secs"`
+}
+
+// SMSActivationResult models responses to an activation SMS request.
+type SMSActivationResult struct {
+	duoapi.StatResult
+	Response SMSActivation
+}
+
+// SendSMSActivation calls POST /admin/v1/phones/:phone_id/send_sms_activation
+// See https://duo.com/docs/adminapi#send-activation-sms
+func (c *Client) SendSMSActivation(phoneID string, options ...func(*url.Values)) (*SMSActivationResult, error) {
+	path := fmt.Sprintf("/admin/v1/phones/%s/send_sms_activation", phoneID)
+
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SMSActivationResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SendSMSPasscodes calls POST /admin/v1/phones/:phone_id/send_sms_passcodes
+// See https://duo.com/docs/adminapi#send-passcodes-via-sms
+func (c *Client) SendSMSPasscodes(phoneID string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/phones/%s/send_sms_passcodes", phoneID)
+
+	_, body, err := c.SignedCall(http.MethodPost, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Token methods
 
 // GetTokensTypeAndSerial sets the optional type and serial parameters for a GetTokens request.
@@ -779,6 +1580,14 @@ func (c *Client) GetTokens(options ...func(*url.Values)) (*GetTokensResult, erro
 		o(&params)
 	}
 
+	v := &validator{}
+	if values, ok := params["type"]; ok {
+		validateTokenType(v, TokenType(values[0]))
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
 	cb := func(params url.Values) (responsePage, error) {
 		return c.retrieveTokens(params)
 	}
@@ -828,6 +1637,204 @@ func (c *Client) GetToken(tokenID string) (*GetTokenResult, error) {
 	return result, nil
 }
 
+// CreateToken calls POST /admin/v1/tokens
+// See https://duo.com/docs/adminapi#create-hardware-token
+func (c *Client) CreateToken(token TokenCreate) (*GetTokenResult, error) {
+	if err := token.Validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(token)
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, "/admin/v1/tokens", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetTokenResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ResyncToken calls POST /admin/v1/tokens/:token_id/resync
+// See https://duo.com/docs/adminapi#resync-hardware-token
+// The codes must be three consecutive codes generated by the token.
+func (c *Client) ResyncToken(tokenID, code1, code2, code3 string) (*StringResult, error) {
+	path := fmt.Sprintf("/admin/v1/tokens/%s/resync", tokenID)
+
+	v := &validator{}
+	for i, code := range []string{code1, code2, code3} {
+		param := fmt.Sprintf("code%d", i+1)
+		v.required(param, code)
+		if strings.Trim(code, "0123456789") != "" {
+			v.add(param, code, "must contain only digits")
+		}
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("code1", code1)
+	params.Set("code2", code2)
+	params.Set("code3", code3)
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StringResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteToken calls DELETE /admin/v1/tokens/:token_id
+// See https://duo.com/docs/adminapi#delete-hardware-token
+func (c *Client) DeleteToken(tokenID string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/tokens/%s", tokenID)
+
+	_, body, err := c.SignedCall(http.MethodDelete, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Bypass code methods
+
+// BypassCode models the metadata of a bypass code.  The code itself is only
+// returned when it is created.
+type BypassCode struct {
+	AdminEmail   string `json:"admin_email"`
+	BypassCodeID string `json:"bypass_code_id"`
+	Created      uint64
+	// Expiration is nil for a code which never expires.
+	Expiration *uint64
+	// ReuseCount is the number of uses left, or 0 for unlimited use.
+	ReuseCount int `json:"reuse_count"`
+	User       *User
+}
+
+func (b BypassCode) id() string {
+	return b.BypassCodeID
+}
+
+// Expires returns the time at which the code expires, and false if it never
+// expires.
+func (b *BypassCode) Expires() (time.Time, bool) {
+	if b.Expiration == nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(*b.Expiration), 0), true
+}
+
+// GetBypassCodesResult models responses containing a list of bypass codes.
+type GetBypassCodesResult struct {
+	duoapi.StatResult
+	ListResult
+	Response []BypassCode
+}
+
+func (result *GetBypassCodesResult) getResponse() interface{} {
+	return result.Response
+}
+
+func (result *GetBypassCodesResult) appendResponse(codes interface{}) {
+	asserted_codes := codes.([]BypassCode)
+	result.Response = append(result.Response, asserted_codes...)
+}
+
+// ListBypassCodes calls GET /admin/v1/bypass_codes
+// See https://duo.com/docs/adminapi#retrieve-bypass-codes
+func (c *Client) ListBypassCodes(options ...func(*url.Values)) (*GetBypassCodesResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveBypassCodes("/admin/v1/bypass_codes", params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetBypassCodesResult), nil
+}
+
+func (c *Client) retrieveBypassCodes(path string, params url.Values) (*GetBypassCodesResult, error) {
+	_, body, err := c.SignedCall(http.MethodGet, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetBypassCodesResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetBypassCodeResult models responses containing a single bypass code.
+type GetBypassCodeResult struct {
+	duoapi.StatResult
+	Response BypassCode
+}
+
+// GetBypassCode calls GET /admin/v1/bypass_codes/:bypass_code_id
+// See https://duo.com/docs/adminapi#retrieve-bypass-code-by-id
+func (c *Client) GetBypassCode(bypassCodeID string) (*GetBypassCodeResult, error) {
+	path := fmt.Sprintf("/admin/v1/bypass_codes/%s", bypassCodeID)
+
+	_, body, err := c.SignedCall(http.MethodGet, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetBypassCodeResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteBypassCode calls DELETE /admin/v1/bypass_codes/:bypass_code_id
+// See https://duo.com/docs/adminapi#delete-bypass-code
+func (c *Client) DeleteBypassCode(bypassCodeID string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/bypass_codes/%s", bypassCodeID)
+
+	_, body, err := c.SignedCall(http.MethodDelete, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // U2F token methods
 
 // GetU2FTokensResult models responses containing a list of U2F tokens.
@@ -879,9 +1886,15 @@ func (c *Client) retrieveU2FTokens(params url.Values) (*GetU2FTokensResult, erro
 	return result, nil
 }
 
+// GetU2FTokenResult models responses containing a single U2F token.
+type GetU2FTokenResult struct {
+	duoapi.StatResult
+	Response U2FToken
+}
+
 // GetU2FToken calls GET /admin/v1/u2ftokens/:registration_id
 // See https://duo.com/docs/adminapi#retrieve-u2f-token-by-id
-func (c *Client) GetU2FToken(registrationID string) (*GetU2FTokensResult, error) {
+func (c *Client) GetU2FToken(registrationID string) (*GetU2FTokenResult, error) {
 	path := fmt.Sprintf("/admin/v1/u2ftokens/%s", registrationID)
 
 	_, body, err := c.SignedCall(http.MethodGet, path, nil, duoapi.UseTimeout)
@@ -889,7 +1902,120 @@ func (c *Client) GetU2FToken(registrationID string) (*GetU2FTokensResult, error)
 		return nil, err
 	}
 
-	result := &GetU2FTokensResult{}
+	result := &GetU2FTokenResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteU2FToken calls DELETE /admin/v1/u2ftokens/:registration_id
+// See https://duo.com/docs/adminapi#delete-u2f-token
+func (c *Client) DeleteU2FToken(registrationID string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/u2ftokens/%s", registrationID)
+
+	_, body, err := c.SignedCall(http.MethodDelete, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// WebAuthn credential methods
+
+// GetWebAuthnCredentialsResult models responses containing a list of WebAuthn
+// credentials.
+type GetWebAuthnCredentialsResult struct {
+	duoapi.StatResult
+	ListResult
+	Response []WebAuthnCredential
+}
+
+func (result *GetWebAuthnCredentialsResult) getResponse() interface{} {
+	return result.Response
+}
+
+func (result *GetWebAuthnCredentialsResult) appendResponse(credentials interface{}) {
+	asserted_credentials := credentials.([]WebAuthnCredential)
+	result.Response = append(result.Response, asserted_credentials...)
+}
+
+// GetWebAuthnCredentials calls GET /admin/v1/webauthncredentials
+// See https://duo.com/docs/adminapi#retrieve-webauthn-credentials
+func (c *Client) GetWebAuthnCredentials(options ...func(*url.Values)) (*GetWebAuthnCredentialsResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveWebAuthnCredentials("/admin/v1/webauthncredentials", params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetWebAuthnCredentialsResult), nil
+}
+
+func (c *Client) retrieveWebAuthnCredentials(path string, params url.Values) (*GetWebAuthnCredentialsResult, error) {
+	_, body, err := c.SignedCall(http.MethodGet, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetWebAuthnCredentialsResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetWebAuthnCredentialResult models responses containing a single WebAuthn
+// credential.
+type GetWebAuthnCredentialResult struct {
+	duoapi.StatResult
+	Response WebAuthnCredential
+}
+
+// GetWebAuthnCredential calls GET /admin/v1/webauthncredentials/:webauthnkey
+// See https://duo.com/docs/adminapi#retrieve-webauthn-credentials-by-key
+func (c *Client) GetWebAuthnCredential(webAuthnKey string) (*GetWebAuthnCredentialResult, error) {
+	path := fmt.Sprintf("/admin/v1/webauthncredentials/%s", webAuthnKey)
+
+	_, body, err := c.SignedCall(http.MethodGet, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetWebAuthnCredentialResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteWebAuthnCredential calls DELETE /admin/v1/webauthncredentials/:webauthnkey
+// See https://duo.com/docs/adminapi#delete-webauthn-credential
+func (c *Client) DeleteWebAuthnCredential(webAuthnKey string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/webauthncredentials/%s", webAuthnKey)
+
+	_, body, err := c.SignedCall(http.MethodDelete, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
 	err = json.Unmarshal(body, result)
 	if err != nil {
 		return nil, err
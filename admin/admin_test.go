@@ -23,6 +23,14 @@ func buildAdminClient(url string, proxy func(*http.Request) (*url.URL, error)) *
 	return New(*base)
 }
 
+// newTestClient starts a TLS server which answers requests with handler until
+// the test ends, and returns a Client for it.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	ts := httptest.NewTLSServer(handler)
+	t.Cleanup(ts.Close)
+	return buildAdminClient(ts.URL, nil)
+}
+
 func getBodyParams(r *http.Request) (url.Values, error) {
 	body, err := ioutil.ReadAll(r.Body)
 	r.Body.Close()
@@ -309,7 +317,11 @@ func TestCreateUser(t *testing.T) {
 		Status:   "active",
 	}
 
-	result, err := duo.CreateUser(userToCreate.URLValues())
+	result, err := duo.CreateUser(UserUpdate{
+		Username: String(userToCreate.Username),
+		Email:    String(userToCreate.Email),
+		Status:   NewUserStatus(UserStatus(userToCreate.Status)),
+	})
 	if err != nil {
 		t.Errorf("Unexpected error from CreateUser call %v", err.Error())
 	}
@@ -386,7 +398,7 @@ func TestModifyUser(t *testing.T) {
 		Email:  "jsmith-new@example.com",
 	}
 
-	result, err := duo.ModifyUser(userToModify.UserID, userToModify.URLValues())
+	result, err := duo.ModifyUser(userToModify.UserID, UserUpdate{Email: String(userToModify.Email)})
 	if err != nil {
 		t.Errorf("Unexpected error from ModifyUser call %v", err.Error())
 	}
@@ -636,6 +648,66 @@ func TestGetUser(t *testing.T) {
 	}
 }
 
+func TestEnrollUser(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		if r.URL.Path != "/admin/v1/users/enroll" || params.Encode() != "email=jsmith%40example.com&username=jsmith&valid_secs=3600" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": "0"}`)
+	})
+
+	result, err := duo.EnrollUser("jsmith", "jsmith@example.com", 3600)
+	if err != nil {
+		t.Errorf("Unexpected error from EnrollUser call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+
+	if _, err := duo.EnrollUser("jsmith", "", 0); err == nil {
+		t.Errorf("Expected a validation error for a missing email")
+	}
+}
+
+func TestSendVerificationPush(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		if r.URL.Path != "/admin/v1/users/DU1/send_verification_push" || params.Get("phone_id") != "DP1" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"confirmation_code": "123456", "push_id": "de4d1b7a-9e1c-4ac5-ad8e-3b8f1f3b0e5f"}}`)
+	})
+
+	result, err := duo.SendVerificationPush("DU1", "DP1")
+	if err != nil {
+		t.Errorf("Unexpected error from SendVerificationPush call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.ConfirmationCode != "123456" || result.Response.PushID == "" {
+		t.Errorf("Unexpected verification push %+v", result.Response)
+	}
+}
+
+func TestGetVerificationPushResponse(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v1/users/DU1/verification_push_response" || r.URL.Query().Get("push_id") != "PUSH1" {
+			t.Errorf("Unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"push_id": "PUSH1", "result": "fraud"}}`)
+	})
+
+	result, err := duo.GetVerificationPushResponse("DU1", "PUSH1")
+	if err != nil {
+		t.Errorf("Unexpected error from GetVerificationPushResponse call %v", err.Error())
+	}
+	if result.Response.Result != VerificationPushFraud {
+		t.Errorf("Expected fraud, but got %s", result.Response.Result)
+	}
+}
+
 const getGroupsResponse = `{
 	"response": [{
 		"desc": "This is group A",
@@ -873,6 +945,47 @@ func TestDisassociateGroupFromUser(t *testing.T) {
 	}
 }
 
+const associatePhoneWithUserResponse = `{
+	"stat": "OK",
+	"response": ""
+}`
+
+func TestAssociatePhoneWithUser(t *testing.T) {
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, associatePhoneWithUserResponse)
+	})
+
+	result, err := duo.AssociatePhoneWithUser("DU3RP9I2WOC59VZX672N", "DPFZRS9FB0D46QFTM899")
+	if err != nil {
+		t.Errorf("Unexpected error from AssociatePhoneWithUser call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if params.Get("phone_id") != "DPFZRS9FB0D46QFTM899" {
+		t.Errorf("Expected phone_id DPFZRS9FB0D46QFTM899, but got %s", params.Get("phone_id"))
+	}
+}
+
+func TestDisassociatePhoneFromUser(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v1/users/DU3RP9I2WOC59VZX672N/phones/DPFZRS9FB0D46QFTM899" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprintln(w, associatePhoneWithUserResponse)
+	})
+
+	result, err := duo.DisassociatePhoneFromUser("DU3RP9I2WOC59VZX672N", "DPFZRS9FB0D46QFTM899")
+	if err != nil {
+		t.Errorf("Unexpected error from DisassociatePhoneFromUser call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
 const getUserPhonesResponse = `{
 	"stat": "OK",
 	"response": [{
@@ -1299,6 +1412,23 @@ func TestAssociateUserToken(t *testing.T) {
 	}
 }
 
+func TestDisassociateUserToken(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/admin/v1/users/DU3RP9I2WOC59VZX672N/tokens/DHEKH0JJIYC1LX3AZWO4" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprintln(w, associateUserTokenResponse)
+	})
+
+	result, err := duo.DisassociateUserToken("DU3RP9I2WOC59VZX672N", "DHEKH0JJIYC1LX3AZWO4")
+	if err != nil {
+		t.Errorf("Unexpected error from DisassociateUserToken call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
 const getUserU2FTokensResponse = `{
 	"stat": "OK",
 	"response": [{
@@ -1581,6 +1711,163 @@ func TestGetGroup(t *testing.T) {
 	}
 }
 
+const createGroupResponse = `{
+	"response": {
+		"desc": "People with hardware tokens",
+		"group_id": "DGXXXXXXXXXXXXXXXXXX",
+		"name": "token_users",
+		"push_enabled": false,
+		"sms_enabled": true,
+		"status": "active",
+		"voice_enabled": true,
+		"mobile_otp_enabled": true
+	},
+	"stat": "OK"
+}`
+
+func TestCreateGroup(t *testing.T) {
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, createGroupResponse)
+	})
+
+	result, err := duo.CreateGroup(GroupUpdate{
+		Name:        String("token_users"),
+		Desc:        String("People with hardware tokens"),
+		PushEnabled: Bool(false),
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from CreateGroup call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.GroupID != "DGXXXXXXXXXXXXXXXXXX" {
+		t.Errorf("Expected group ID DGXXXXXXXXXXXXXXXXXX, but got %s", result.Response.GroupID)
+	}
+	if params.Encode() != "desc=People+with+hardware+tokens&name=token_users&push_enabled=false" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+
+	if _, err = duo.CreateGroup(GroupUpdate{Desc: String("No name")}); err == nil {
+		t.Error("Expected a group without a name to be rejected")
+	}
+}
+
+func TestModifyGroup(t *testing.T) {
+	var path string
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, getGroupResponse)
+	})
+
+	result, err := duo.ModifyGroup("DGXXXXXXXXXXXXXXXXXX", GroupUpdate{
+		Status:     NewGroupStatus(GroupStatusBypass),
+		NullFields: []string{"Desc"},
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from ModifyGroup call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if path != "/admin/v1/groups/DGXXXXXXXXXXXXXXXXXX" {
+		t.Errorf("Unexpected request path %s", path)
+	}
+	if params.Encode() != "desc=&status=bypass" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+}
+
+const deleteGroupResponse = `{
+	"stat": "OK",
+	"response": ""
+}`
+
+func TestDeleteGroup(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected a DELETE request, but got %s", r.Method)
+		}
+		fmt.Fprintln(w, deleteGroupResponse)
+	})
+
+	result, err := duo.DeleteGroup("DGXXXXXXXXXXXXXXXXXX")
+	if err != nil {
+		t.Errorf("Unexpected error from DeleteGroup call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
+const getGroupUsersPage1Response = `{
+	"stat": "OK",
+	"metadata": {
+		"prev_offset": null,
+		"next_offset": 2,
+		"total_objects": 3
+	},
+	"response": [{
+		"user_id": "DUXXXXXXXXXXXXXXXXX1",
+		"username": "jsmith"
+	},
+	{
+		"user_id": "DUXXXXXXXXXXXXXXXXX2",
+		"username": "jdoe"
+	}]
+}`
+
+const getGroupUsersPage2Response = `{
+	"stat": "OK",
+	"metadata": {
+		"prev_offset": 0,
+		"total_objects": 3
+	},
+	"response": [{
+		"user_id": "DUXXXXXXXXXXXXXXXXX3",
+		"username": "asmith"
+	}]
+}`
+
+func TestGetGroupUsersMultiple(t *testing.T) {
+	requests := []*http.Request{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if len(requests) == 0 {
+			fmt.Fprintln(w, getGroupUsersPage1Response)
+		} else {
+			fmt.Fprintln(w, getGroupUsersPage2Response)
+		}
+		requests = append(requests, r)
+	})
+
+	result, err := duo.GetGroupUsers("DGXXXXXXXXXXXXXXXXXX")
+
+	if len(requests) != 2 {
+		t.Errorf("Expected two requets, found %d", len(requests))
+	}
+	if requests[0].URL.Path != "/admin/v2/groups/DGXXXXXXXXXXXXXXXXXX/users" {
+		t.Errorf("Unexpected request path %s", requests[0].URL.Path)
+	}
+	if requests[1].URL.Query().Get("offset") != "2" {
+		t.Errorf("Expected the second request at offset 2, but got %s", requests[1].URL.Query().Get("offset"))
+	}
+
+	if len(result.Response) != 3 {
+		t.Errorf("Expected three users in the response, found %d", len(result.Response))
+	}
+	if result.Response[2].Username != "asmith" {
+		t.Errorf("Expected the last user to be asmith, but got %s", result.Response[2].Username)
+	}
+
+	if err != nil {
+		t.Errorf("Expected err to be nil, found %s", err)
+	}
+}
+
 const getPhonesResponse = `{
 	"stat": "OK",
 	"response": [{
@@ -1907,6 +2194,147 @@ func TestDeletePhone(t *testing.T) {
 	}
 }
 
+func TestCreatePhone(t *testing.T) {
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, getPhoneResponse)
+	})
+
+	result, err := duo.CreatePhone(PhoneUpdate{
+		Number:   String("+15555550100"),
+		Type:     NewPhoneType(PhoneTypeMobile),
+		Platform: NewPhonePlatform(PhonePlatformAppleIOS),
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from CreatePhone call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.PhoneID != "DPFZRS9FB0D46QFTM899" {
+		t.Errorf("Expected phone ID DPFZRS9FB0D46QFTM899, but got %s", result.Response.PhoneID)
+	}
+	if params.Encode() != "number=%2B15555550100&platform=apple+ios&type=mobile" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+
+	if _, err = duo.CreatePhone(PhoneUpdate{Type: NewPhoneType("cell")}); err == nil {
+		t.Error("Expected an invalid phone type to be rejected")
+	}
+}
+
+func TestModifyPhone(t *testing.T) {
+	var path string
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, getPhoneResponse)
+	})
+
+	result, err := duo.ModifyPhone("DPFZRS9FB0D46QFTM899", PhoneUpdate{Name: String("Work phone"), NullFields: []string{"Extension"}})
+	if err != nil {
+		t.Errorf("Unexpected error from ModifyPhone call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if path != "/admin/v1/phones/DPFZRS9FB0D46QFTM899" {
+		t.Errorf("Unexpected request path %s", path)
+	}
+	if params.Encode() != "extension=&name=Work+phone" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+}
+
+const createActivationURLResponse = `{
+	"stat": "OK",
+	"response": {
+		"activation_barcode": "https://api-abcdef.duosecurity.com/frame/qr?value=8LIRa5danrICkhHtkLxi-cKLu2DWzDYCmBwBHY2YzW5ZYnYaRxA",
+		"activation_url": "https://m-abcdef.duosecurity.com/iphone/7dhHzFMUWSqSMVBE5ynp",
+		"installation_url": "https://m-abcdef.duosecurity.com/install/mobile",
+		"valid_secs": 3600
+	}
+}`
+
+func TestCreateActivationURL(t *testing.T) {
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, createActivationURLResponse)
+	})
+
+	result, err := duo.CreateActivationURL("DPFZRS9FB0D46QFTM899", ActivationValidSecs(3600), ActivationInstall(true))
+	if err != nil {
+		t.Errorf("Unexpected error from CreateActivationURL call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.ActivationURL != "https://m-abcdef.duosecurity.com/iphone/7dhHzFMUWSqSMVBE5ynp" {
+		t.Errorf("Unexpected activation URL %s", result.Response.ActivationURL)
+	}
+	if result.Response.ValidSecs != 3600 {
+		t.Errorf("Expected valid_secs 3600, but got %d", result.Response.ValidSecs)
+	}
+	if params.Encode() != "install=1&valid_secs=3600" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+}
+
+const sendSMSActivationResponse = `{
+	"stat": "OK",
+	"response": {
+		"activation_barcode": "https://api-abcdef.duosecurity.com/frame/qr?value=8LIRa5danrICkhHtkLxi-cKLu2DWzDYCmBwBHY2YzW5ZYnYaRxA",
+		"activation_msg": "To activate the app, tap and open this link: https://m-abcdef.duosecurity.com/iphone/7dhHzFMUWSqSMVBE5ynp",
+		"installation_msg": "Welcome to Duo! Tap the link to install the app: https://m-abcdef.duosecurity.com/iphone",
+		"valid_secs": 3600
+	}
+}`
+
+func TestSendSMSActivation(t *testing.T) {
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, sendSMSActivationResponse)
+	})
+
+	result, err := duo.SendSMSActivation("DPFZRS9FB0D46QFTM899",
+		ActivationInstall(false),
+		SMSActivationMsg("Activate: <acturl>"),
+	)
+	if err != nil {
+		t.Errorf("Unexpected error from SendSMSActivation call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.ValidSecs != 3600 || result.Response.ActivationMsg == "" {
+		t.Errorf("Unexpected SMS activation %+v", result.Response)
+	}
+	if params.Encode() != "activation_msg=Activate%3A+%3Cacturl%3E&install=0" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+}
+
+func TestSendSMSPasscodes(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v1/phones/DPFZRS9FB0D46QFTM899/send_sms_passcodes" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprintln(w, deletePhoneResponse)
+	})
+
+	result, err := duo.SendSMSPasscodes("DPFZRS9FB0D46QFTM899")
+	if err != nil {
+		t.Errorf("Unexpected error from SendSMSPasscodes call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
 const getTokensResponse = `{
 	"stat": "OK",
 	"response": [{
@@ -2130,6 +2558,96 @@ func TestGetToken(t *testing.T) {
 	}
 }
 
+const createTokenResponse = `{
+	"stat": "OK",
+	"response": {
+		"serial": "123456",
+		"token_id": "DHEKH0JJIYC1LX3AZWO4",
+		"type": "h6",
+		"totp_step": null,
+		"users": []
+	}
+}`
+
+func TestCreateToken(t *testing.T) {
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, createTokenResponse)
+	})
+
+	result, err := duo.CreateToken(TokenCreate{
+		Type:    TokenTypeHOTP6,
+		Serial:  "123456",
+		Secret:  "3132333435363738393031323334353637383930",
+		Counter: Int(0),
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from CreateToken call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.TokenID != "DHEKH0JJIYC1LX3AZWO4" || result.Response.Type != string(TokenTypeHOTP6) {
+		t.Errorf("Unexpected token %+v", result.Response)
+	}
+	if params.Encode() != "counter=0&secret=3132333435363738393031323334353637383930&serial=123456&type=h6" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+
+	if _, err = duo.CreateToken(TokenCreate{Type: TokenTypeYubiKey, Serial: "1", PrivateID: "abc"}); err == nil {
+		t.Error("Expected a YubiKey without an aes_key to be rejected")
+	}
+	if _, err = duo.CreateToken(TokenCreate{Type: TokenTypeDuoD100, Serial: "1"}); err == nil {
+		t.Error("Expected a d1 token to be rejected")
+	}
+}
+
+func TestResyncToken(t *testing.T) {
+	requests := 0
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, associateUserTokenResponse)
+	})
+
+	result, err := duo.ResyncToken("DHEKH0JJIYC1LX3AZWO4", "755224", "287082", "359152")
+	if err != nil {
+		t.Errorf("Unexpected error from ResyncToken call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if params.Encode() != "code1=755224&code2=287082&code3=359152" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+
+	if _, err = duo.ResyncToken("DHEKH0JJIYC1LX3AZWO4", "755224", "", "35915x"); err == nil {
+		t.Error("Expected missing and non-numeric codes to be rejected")
+	}
+	if requests != 1 {
+		t.Errorf("Expected one request, but got %d", requests)
+	}
+}
+
+func TestDeleteToken(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected a DELETE request, but got %s", r.Method)
+		}
+		fmt.Fprintln(w, deletePhoneResponse)
+	})
+
+	result, err := duo.DeleteToken("DHEKH0JJIYC1LX3AZWO4")
+	if err != nil {
+		t.Errorf("Unexpected error from DeleteToken call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
 const getU2FTokensResponse = `{
 	"stat": "OK",
 	"response": [{
@@ -2322,10 +2840,25 @@ func TestGetU2FTokensPageArgs(t *testing.T) {
 	}
 }
 
+const getU2FTokenResponse = `{
+	"stat": "OK",
+	"response": {
+		"date_added": 1444678994,
+		"registration_id": "D21RU6X1B1DF5P54B6PV",
+		"user": {
+			"email": "jsmith@example.com",
+			"realname": "Joe Smith",
+			"status": "active",
+			"user_id": "DU3RP9I2WOC59VZX672N",
+			"username": "jsmith"
+		}
+	}
+}`
+
 func TestGetU2FToken(t *testing.T) {
 	ts := httptest.NewTLSServer(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			fmt.Fprintln(w, getU2FTokensResponse)
+			fmt.Fprintln(w, getU2FTokenResponse)
 		}),
 	)
 	defer ts.Close()
@@ -2339,11 +2872,157 @@ func TestGetU2FToken(t *testing.T) {
 	if result.Stat != "OK" {
 		t.Errorf("Expected OK, but got %s", result.Stat)
 	}
-	if len(result.Response) != 1 {
-		t.Errorf("Expected 1 token, but got %d", len(result.Response))
+	if result.Response.RegistrationID != "D21RU6X1B1DF5P54B6PV" {
+		t.Errorf("Expected registration ID D21RU6X1B1DF5P54B6PV, but got %s", result.Response.RegistrationID)
 	}
-	if result.Response[0].RegistrationID != "D21RU6X1B1DF5P54B6PV" {
-		t.Errorf("Expected registration ID D21RU6X1B1DF5P54B6PV, but got %s", result.Response[0].RegistrationID)
+	if result.Response.User == nil || result.Response.User.Username != "jsmith" {
+		t.Errorf("Expected user jsmith, but got %+v", result.Response.User)
+	}
+}
+
+func TestDeleteU2FToken(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/admin/v1/u2ftokens/D21RU6X1B1DF5P54B6PV" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": ""}`)
+	})
+
+	result, err := duo.DeleteU2FToken("D21RU6X1B1DF5P54B6PV")
+	if err != nil {
+		t.Errorf("Unexpected error from DeleteU2FToken call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
+const getWebAuthnCredentialsPage1Response = `{
+	"stat": "OK",
+	"response": [{
+		"credential_name": "Touch ID",
+		"date_added": 1550674764,
+		"label": "Touch ID",
+		"user": {
+			"email": "jsmith@example.com",
+			"realname": "Joe Smith",
+			"user_id": "DU3RP9I2WOC59VZX672N",
+			"username": "jsmith"
+		},
+		"webauthnkey": "WABFEOE007ZMV1QAZTRB"
+	}],
+	"metadata": {
+		"prev_offset": null,
+		"next_offset": 1,
+		"total_objects": 2
+	}
+}`
+
+const getWebAuthnCredentialsPage2Response = `{
+	"stat": "OK",
+	"response": [{
+		"admin": {
+			"admin_id": "DEFMS1M2IJ2GECDG6N6V",
+			"email": "ljones@example.com",
+			"name": "Lisa Jones"
+		},
+		"credential_name": "YubiKey",
+		"date_added": 1550674765,
+		"label": "Security Key",
+		"webauthnkey": "WAU9D7PGR2E1MGYMVDXE"
+	}],
+	"metadata": {
+		"prev_offset": 0,
+		"next_offset": null,
+		"total_objects": 2
+	}
+}`
+
+func TestGetWebAuthnCredentialsMultiple(t *testing.T) {
+	requests := []*http.Request{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if len(requests) == 0 {
+			fmt.Fprintln(w, getWebAuthnCredentialsPage1Response)
+		} else {
+			fmt.Fprintln(w, getWebAuthnCredentialsPage2Response)
+		}
+		requests = append(requests, r)
+	})
+
+	result, err := duo.GetWebAuthnCredentials()
+	if err != nil {
+		t.Errorf("Unexpected error from GetWebAuthnCredentials call %v", err.Error())
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected two requests, found %d", len(requests))
+	}
+	if requests[0].URL.Path != "/admin/v1/webauthncredentials" {
+		t.Errorf("Unexpected request path %s", requests[0].URL.Path)
+	}
+	if len(result.Response) != 2 {
+		t.Fatalf("Expected two credentials in the response, found %d", len(result.Response))
+	}
+	if result.Response[0].User == nil || result.Response[0].User.UserID != "DU3RP9I2WOC59VZX672N" {
+		t.Errorf("Expected a user credential, but got %+v", result.Response[0])
+	}
+	if result.Response[1].Admin == nil || result.Response[1].Admin.AdminID != "DEFMS1M2IJ2GECDG6N6V" {
+		t.Errorf("Expected an admin credential, but got %+v", result.Response[1])
+	}
+}
+
+func TestGetUserWebAuthnCredentials(t *testing.T) {
+	var last *http.Request
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		last = r
+		fmt.Fprintln(w, strings.Replace(getWebAuthnCredentialsPage1Response, `"next_offset": 1`, `"next_offset": null`, 1))
+	})
+
+	result, err := duo.GetUserWebAuthnCredentials("DU3RP9I2WOC59VZX672N")
+	if err != nil {
+		t.Errorf("Unexpected error from GetUserWebAuthnCredentials call %v", err.Error())
+	}
+	if last.URL.Path != "/admin/v1/users/DU3RP9I2WOC59VZX672N/webauthncredentials" {
+		t.Errorf("Unexpected request path %s", last.URL.Path)
+	}
+	if len(result.Response) != 1 || result.Response[0].WebAuthnKey != "WABFEOE007ZMV1QAZTRB" {
+		t.Errorf("Unexpected credentials %+v", result.Response)
+	}
+}
+
+func TestGetWebAuthnCredential(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v1/webauthncredentials/WABFEOE007ZMV1QAZTRB" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"credential_name": "Touch ID", "date_added": 1550674764, "label": "Touch ID", "webauthnkey": "WABFEOE007ZMV1QAZTRB"}}`)
+	})
+
+	result, err := duo.GetWebAuthnCredential("WABFEOE007ZMV1QAZTRB")
+	if err != nil {
+		t.Errorf("Unexpected error from GetWebAuthnCredential call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.CredentialName != "Touch ID" || result.Response.DateAdded != 1550674764 {
+		t.Errorf("Unexpected credential %+v", result.Response)
+	}
+}
+
+func TestDeleteWebAuthnCredential(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/admin/v1/webauthncredentials/WABFEOE007ZMV1QAZTRB" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": ""}`)
+	})
+
+	result, err := duo.DeleteWebAuthnCredential("WABFEOE007ZMV1QAZTRB")
+	if err != nil {
+		t.Errorf("Unexpected error from DeleteWebAuthnCredential call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
 	}
 }
 
@@ -2385,3 +3064,194 @@ func TestGetBypassCodes(t *testing.T) {
 		t.Errorf("Expected 10 codes, but got %d", len(result.Response))
 	}
 }
+
+func TestCreateUserBypassCodes(t *testing.T) {
+	requests := 0
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		params, _ = getBodyParams(r)
+		fmt.Fprintln(w, getBypassCodesResponse)
+	})
+
+	result, err := duo.CreateUserBypassCodes("D21RU6X1B1DF5P54B6PV", BypassCodesCreate{
+		Count:      Int(10),
+		ReuseCount: Int(0),
+		ValidSecs:  Int(3600),
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from CreateUserBypassCodes call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if params.Encode() != "count=10&reuse_count=0&valid_secs=3600" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+
+	_, err = duo.CreateUserBypassCodes("D21RU6X1B1DF5P54B6PV", BypassCodesCreate{Count: Int(2), Codes: []string{"123456", "234567"}})
+	if verr, ok := err.(*ValidationError); !ok || verr.Fields[0].Param != "count" {
+		t.Errorf("Expected count and codes to be rejected together, got %v", err)
+	}
+	if _, err = duo.CreateUserBypassCodes("D21RU6X1B1DF5P54B6PV", BypassCodesCreate{Count: Int(11)}); err == nil {
+		t.Error("Expected a count of 11 to be rejected")
+	}
+	if requests != 1 {
+		t.Errorf("Expected one request, but got %d", requests)
+	}
+
+	_, err = duo.CreateUserBypassCodes("D21RU6X1B1DF5P54B6PV", BypassCodesCreate{Codes: []string{"123456", "234567"}})
+	if err != nil {
+		t.Errorf("Unexpected error from CreateUserBypassCodes call %v", err.Error())
+	}
+	if params.Encode() != "codes=123456%2C234567" {
+		t.Errorf("Unexpected request parameters %s", params.Encode())
+	}
+
+	// The deprecated wrapper passes its options through unchecked
+	count := func(opts *url.Values) { opts.Set("count", "11") }
+	if _, err = duo.GetUserBypassCodes("D21RU6X1B1DF5P54B6PV", count); err != nil {
+		t.Errorf("Unexpected error from GetUserBypassCodes call %v", err.Error())
+	}
+	if requests != 3 || params.Get("count") != "11" {
+		t.Errorf("Expected GetUserBypassCodes to send count=11, got %s", params.Encode())
+	}
+}
+
+const listBypassCodesPage1Response = `{
+	"stat": "OK",
+	"metadata": {
+		"next_offset": 1,
+		"total_objects": 2
+	},
+	"response": [{
+		"admin_email": "admin@example.com",
+		"bypass_code_id": "DBXXXXXXXXXXXXXXXXX1",
+		"created": 1605751637,
+		"expiration": 1605755237,
+		"reuse_count": 1,
+		"user": {
+			"user_id": "D21RU6X1B1DF5P54B6PV",
+			"username": "jsmith"
+		}
+	}]
+}`
+
+const listBypassCodesPage2Response = `{
+	"stat": "OK",
+	"metadata": {
+		"prev_offset": 0,
+		"total_objects": 2
+	},
+	"response": [{
+		"admin_email": "",
+		"bypass_code_id": "DBXXXXXXXXXXXXXXXXX2",
+		"created": 1605751637,
+		"expiration": null,
+		"reuse_count": 0,
+		"user": {
+			"user_id": "D21RU6X1B1DF5P54B6PV",
+			"username": "jsmith"
+		}
+	}]
+}`
+
+func TestListBypassCodesMultiple(t *testing.T) {
+	requests := []*http.Request{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if len(requests) == 0 {
+			fmt.Fprintln(w, listBypassCodesPage1Response)
+		} else {
+			fmt.Fprintln(w, listBypassCodesPage2Response)
+		}
+		requests = append(requests, r)
+	})
+
+	result, err := duo.ListBypassCodes()
+	if err != nil {
+		t.Errorf("Expected err to be nil, found %s", err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected two requets, found %d", len(requests))
+	}
+	if len(result.Response) != 2 {
+		t.Fatalf("Expected two bypass codes in the response, found %d", len(result.Response))
+	}
+	if expires, ok := result.Response[0].Expires(); !ok || expires.Unix() != 1605755237 {
+		t.Errorf("Unexpected expiration %v, %v", expires, ok)
+	}
+	if _, ok := result.Response[1].Expires(); ok {
+		t.Error("Expected the second code to never expire")
+	}
+	if result.Response[0].User.Username != "jsmith" || result.Response[0].ReuseCount != 1 {
+		t.Errorf("Unexpected bypass code %+v", result.Response[0])
+	}
+}
+
+func TestListUserBypassCodes(t *testing.T) {
+	var path string
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprintln(w, listBypassCodesPage2Response)
+	})
+
+	result, err := duo.ListUserBypassCodes("D21RU6X1B1DF5P54B6PV")
+	if err != nil {
+		t.Errorf("Unexpected error from ListUserBypassCodes call %v", err.Error())
+	}
+	if path != "/admin/v1/users/D21RU6X1B1DF5P54B6PV/bypass_codes" {
+		t.Errorf("Unexpected request path %s", path)
+	}
+	if len(result.Response) != 1 || result.Response[0].BypassCodeID != "DBXXXXXXXXXXXXXXXXX2" {
+		t.Errorf("Unexpected bypass codes %+v", result.Response)
+	}
+}
+
+const getBypassCodeResponse = `{
+	"stat": "OK",
+	"response": {
+		"admin_email": "admin@example.com",
+		"bypass_code_id": "DBXXXXXXXXXXXXXXXXX1",
+		"created": 1605751637,
+		"expiration": 1605755237,
+		"reuse_count": 1,
+		"user": {
+			"user_id": "D21RU6X1B1DF5P54B6PV",
+			"username": "jsmith"
+		}
+	}
+}`
+
+func TestGetBypassCode(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, getBypassCodeResponse)
+	})
+
+	result, err := duo.GetBypassCode("DBXXXXXXXXXXXXXXXXX1")
+	if err != nil {
+		t.Errorf("Unexpected error from GetBypassCode call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.AdminEmail != "admin@example.com" {
+		t.Errorf("Expected admin email admin@example.com, but got %s", result.Response.AdminEmail)
+	}
+}
+
+func TestDeleteBypassCode(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/admin/v1/bypass_codes/DBXXXXXXXXXXXXXXXXX1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprintln(w, deleteUserResponse)
+	})
+
+	result, err := duo.DeleteBypassCode("DBXXXXXXXXXXXXXXXXX1")
+	if err != nil {
+		t.Errorf("Unexpected error from DeleteBypassCode call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
@@ -0,0 +1,428 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// Administrator models a Duo administrator.
+type Administrator struct {
+	AdminID string `json:"admin_id"`
+	// AdminUnits holds the IDs of the administrative units the
+	// administrator is assigned to.
+	AdminUnits             []string `json:"admin_units"`
+	Created                uint64
+	Email                  string
+	HardToken              *Token  `json:"hardtoken"`
+	LastLogin              *uint64 `json:"last_login"`
+	Name                   string
+	PasswordChangeRequired bool `json:"password_change_required"`
+	Phone                  string
+	// RestrictedByAdminUnits limits the administrator to the users and
+	// integrations of its administrative units.
+	RestrictedByAdminUnits bool `json:"restricted_by_admin_units"`
+	Role                   AdminRole
+	Status                 AdminStatus
+	WebAuthnCredentials    []WebAuthnCredential `json:"webauthncredentials"`
+}
+
+func (a Administrator) id() string {
+	return a.AdminID
+}
+
+// AdminUpdate holds the fields of an administrator to create or modify.  Nil
+// fields are left unchanged; name fields in NullFields to clear them.
+type AdminUpdate struct {
+	// Email may only be set by CreateAdmin.
+	Email                  *string      `url:"email"`
+	Name                   *string      `url:"name"`
+	Phone                  *string      `url:"phone"`
+	Role                   *AdminRole   `url:"role"`
+	RestrictedByAdminUnits *bool        `url:"restricted_by_admin_units"`
+	PasswordChangeRequired *bool        `url:"password_change_required"`
+	Status                 *AdminStatus `url:"status"`
+	TokenID                *string      `url:"token_id"`
+
+	// SendEmail and ValidDays apply only to CreateAdmin.  SendEmail emails
+	// the new administrator an activation link, which is valid for ValidDays
+	// days, 7 by default.
+	SendEmail bool `url:"-"`
+	ValidDays *int `url:"valid_days"`
+
+	NullFields []string
+}
+
+// GetAdminsResult models responses containing a list of administrators.
+type GetAdminsResult struct {
+	duoapi.StatResult
+	ListResult
+	Response []Administrator
+}
+
+func (result *GetAdminsResult) getResponse() interface{} {
+	return result.Response
+}
+
+func (result *GetAdminsResult) appendResponse(admins interface{}) {
+	asserted_admins := admins.([]Administrator)
+	result.Response = append(result.Response, asserted_admins...)
+}
+
+// GetAdmins calls GET /admin/v1/admins
+// See https://duo.com/docs/adminapi#retrieve-administrators
+func (c *Client) GetAdmins(options ...func(*url.Values)) (*GetAdminsResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveAdmins(params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetAdminsResult), nil
+}
+
+func (c *Client) retrieveAdmins(params url.Values) (*GetAdminsResult, error) {
+	_, body, err := c.SignedCall(http.MethodGet, "/admin/v1/admins", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetAdminsResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAdminResult models responses containing a single administrator.
+type GetAdminResult struct {
+	duoapi.StatResult
+	Response Administrator
+}
+
+// GetAdmin calls GET /admin/v1/admins/:admin_id
+// See https://duo.com/docs/adminapi#retrieve-administrator-by-id
+func (c *Client) GetAdmin(adminID string) (*GetAdminResult, error) {
+	path := fmt.Sprintf("/admin/v1/admins/%s", adminID)
+
+	_, body, err := c.SignedCall(http.MethodGet, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetAdminResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateAdmin calls POST /admin/v1/admins
+// See https://duo.com/docs/adminapi#create-administrator
+//
+// The new administrator is pending activation until it follows its
+// activation link.  Status cannot be set on creation.
+func (c *Client) CreateAdmin(admin AdminUpdate) (*GetAdminResult, error) {
+	v := &validator{}
+	if admin.Email == nil {
+		v.required("email", "")
+	}
+	if admin.Name == nil {
+		v.required("name", "")
+	}
+	if admin.Status != nil {
+		v.add("status", string(*admin.Status), "cannot be set on creation")
+	}
+	admin.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(admin)
+	if err != nil {
+		return nil, err
+	}
+	if admin.SendEmail {
+		params.Set("send_email", "1")
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, "/admin/v1/admins", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetAdminResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ModifyAdmin calls POST /admin/v1/admins/:admin_id
+// See https://duo.com/docs/adminapi#modify-administrator
+func (c *Client) ModifyAdmin(adminID string, admin AdminUpdate) (*GetAdminResult, error) {
+	path := fmt.Sprintf("/admin/v1/admins/%s", adminID)
+
+	v := &validator{}
+	if admin.Email != nil {
+		v.add("email", *admin.Email, "cannot be modified")
+	}
+	if admin.SendEmail {
+		v.add("send_email", "", "applies only to new administrators")
+	}
+	if admin.ValidDays != nil {
+		v.add("valid_days", strconv.Itoa(*admin.ValidDays), "applies only to new administrators")
+	}
+	admin.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(admin)
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetAdminResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteAdmin calls DELETE /admin/v1/admins/:admin_id
+// See https://duo.com/docs/adminapi#delete-administrator
+func (c *Client) DeleteAdmin(adminID string) (*duoapi.StatResult, error) {
+	return c.adminAction(http.MethodDelete, fmt.Sprintf("/admin/v1/admins/%s", adminID))
+}
+
+// ResetAdminAuthAttempts calls POST /admin/v1/admins/:admin_id/reset
+// See https://duo.com/docs/adminapi#reset-administrator-authentication-attempts
+//
+// It clears the administrator's failed login attempts, unlocking an
+// administrator locked out by too many of them.
+func (c *Client) ResetAdminAuthAttempts(adminID string) (*duoapi.StatResult, error) {
+	return c.adminAction(http.MethodPost, fmt.Sprintf("/admin/v1/admins/%s/reset", adminID))
+}
+
+// ClearAdminExpiration calls POST /admin/v1/admins/:admin_id/clear_inactivity
+// See https://duo.com/docs/adminapi#clear-administrator-expiration
+//
+// It unlocks an administrator expired for inactivity.
+func (c *Client) ClearAdminExpiration(adminID string) (*duoapi.StatResult, error) {
+	return c.adminAction(http.MethodPost, fmt.Sprintf("/admin/v1/admins/%s/clear_inactivity", adminID))
+}
+
+// adminAction calls an administrator endpoint which takes no parameters and
+// whose response is ignored.
+func (c *Client) adminAction(method, path string) (*duoapi.StatResult, error) {
+	_, body, err := c.SignedCall(method, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Activation links
+
+// AdminActivationLink models the activation link of an administrator
+// pending activation.
+type AdminActivationLink struct {
+	Code      string
+	Email     string
+	Link      string
+	ValidDays int `json:"valid_days"`
+}
+
+// AdminActivationLinkResult models responses containing an administrator's
+// activation link.
+type AdminActivationLinkResult struct {
+	duoapi.StatResult
+	Response AdminActivationLink
+}
+
+// CreateAdminActivationLink calls POST /admin/v1/admins/:admin_id/activation_link
+// See https://duo.com/docs/adminapi#create-activation-link-for-administrator-pending-activation
+func (c *Client) CreateAdminActivationLink(adminID string) (*AdminActivationLinkResult, error) {
+	return c.adminActivationLink(fmt.Sprintf("/admin/v1/admins/%s/activation_link", adminID))
+}
+
+// SendAdminActivationEmail calls POST /admin/v1/admins/:admin_id/activation_link/email
+// See https://duo.com/docs/adminapi#email-activation-link-to-administrator-pending-activation
+//
+// The administrator must already have an activation link.
+func (c *Client) SendAdminActivationEmail(adminID string) (*AdminActivationLinkResult, error) {
+	return c.adminActivationLink(fmt.Sprintf("/admin/v1/admins/%s/activation_link/email", adminID))
+}
+
+func (c *Client) adminActivationLink(path string) (*AdminActivationLinkResult, error) {
+	_, body, err := c.SignedCall(http.MethodPost, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AdminActivationLinkResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteAdminActivationLink calls DELETE /admin/v1/admins/:admin_id/activation_link
+// See https://duo.com/docs/adminapi#delete-activation-link-from-administrator-pending-activation
+func (c *Client) DeleteAdminActivationLink(adminID string) (*duoapi.StatResult, error) {
+	return c.adminAction(http.MethodDelete, fmt.Sprintf("/admin/v1/admins/%s/activation_link", adminID))
+}
+
+// Pending activations
+
+// AdminActivation models a pending administrator activation.
+type AdminActivation struct {
+	AdminActivationID string `json:"admin_activation_id"`
+	Email             string
+	// Expiration is the Unix time at which the activation link expires.
+	Expiration uint64
+	Link       string
+	Role       AdminRole
+	ValidDays  int `json:"valid_days"`
+}
+
+func (a AdminActivation) id() string {
+	return a.AdminActivationID
+}
+
+// GetAdminActivationsResult models responses containing a list of pending
+// administrator activations.
+type GetAdminActivationsResult struct {
+	duoapi.StatResult
+	ListResult
+	Response []AdminActivation
+}
+
+func (result *GetAdminActivationsResult) getResponse() interface{} {
+	return result.Response
+}
+
+func (result *GetAdminActivationsResult) appendResponse(activations interface{}) {
+	asserted_activations := activations.([]AdminActivation)
+	result.Response = append(result.Response, asserted_activations...)
+}
+
+// GetAdminActivations calls GET /admin/v1/admins/activations
+// See https://duo.com/docs/adminapi#retrieve-pending-administrator-activations
+func (c *Client) GetAdminActivations(options ...func(*url.Values)) (*GetAdminActivationsResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveAdminActivations(params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetAdminActivationsResult), nil
+}
+
+func (c *Client) retrieveAdminActivations(params url.Values) (*GetAdminActivationsResult, error) {
+	_, body, err := c.SignedCall(http.MethodGet, "/admin/v1/admins/activations", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetAdminActivationsResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AdminActivationCreate holds the parameters of a new pending administrator
+// activation.
+type AdminActivationCreate struct {
+	// Email is the address of the new administrator, and is required.
+	Email string `url:"email"`
+	// Role is the role the administrator is given on activation, Owner by
+	// default.
+	Role *AdminRole `url:"admin_role"`
+	// ValidDays is how long the activation link is valid for, 7 days by
+	// default.
+	ValidDays *int `url:"valid_days"`
+	// SendEmail emails the activation link to Email.
+	SendEmail bool `url:"-"`
+}
+
+// AdminActivationResult models responses containing a pending administrator
+// activation.
+type AdminActivationResult struct {
+	duoapi.StatResult
+	Response AdminActivation
+}
+
+// CreateAdminActivation calls POST /admin/v1/admins/activations
+// See https://duo.com/docs/adminapi#create-administrator-activation-link
+//
+// Unlike CreateAdmin, it creates no administrator until the link is
+// followed.
+func (c *Client) CreateAdminActivation(activation AdminActivationCreate) (*AdminActivationResult, error) {
+	if err := activation.Validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(activation)
+	if err != nil {
+		return nil, err
+	}
+	if activation.SendEmail {
+		params.Set("send_email", "1")
+	}
+
+	_, body, err := c.SignedCall(http.MethodPost, "/admin/v1/admins/activations", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AdminActivationResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteAdminActivation calls DELETE /admin/v1/admins/activations/:admin_activation_id
+// See https://duo.com/docs/adminapi#delete-pending-administrator-activation
+func (c *Client) DeleteAdminActivation(activationID string) (*duoapi.StatResult, error) {
+	return c.adminAction(http.MethodDelete, fmt.Sprintf("/admin/v1/admins/activations/%s", activationID))
+}
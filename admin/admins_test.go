@@ -0,0 +1,306 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+const getAdminsPage1Response = `{
+	"stat": "OK",
+	"response": [{
+		"admin_id": "DEFMS1M2IJ2GECDG6N6V",
+		"admin_units": [],
+		"created": 1489612785,
+		"email": "ljones@example.com",
+		"hardtoken": null,
+		"last_login": 1547580405,
+		"name": "Lisa Jones",
+		"password_change_required": false,
+		"phone": "+15555550100",
+		"restricted_by_admin_units": false,
+		"role": "Owner",
+		"status": "Active",
+		"webauthncredentials": []
+	}],
+	"metadata": {
+		"prev_offset": null,
+		"next_offset": 1,
+		"total_objects": 2
+	}
+}`
+
+const getAdminsPage2Response = `{
+	"stat": "OK",
+	"response": [{
+		"admin_id": "DE0UI5GA5Z6EAQY7K1P2",
+		"admin_units": ["DHEE7IC1E7JFXMAB1T5R"],
+		"created": 1489612786,
+		"email": "rsmith@example.com",
+		"hardtoken": {
+			"serial": "0",
+			"token_id": "DHEKH0JJIYC1LX3AZWO4",
+			"type": "d1"
+		},
+		"last_login": null,
+		"name": "Robert Smith",
+		"password_change_required": true,
+		"phone": "",
+		"restricted_by_admin_units": true,
+		"role": "Help Desk",
+		"status": "Pending Activation"
+	}],
+	"metadata": {
+		"prev_offset": 0,
+		"next_offset": null,
+		"total_objects": 2
+	}
+}`
+
+func TestGetAdminsMultiple(t *testing.T) {
+	requests := []*http.Request{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if len(requests) == 0 {
+			fmt.Fprintln(w, getAdminsPage1Response)
+		} else {
+			fmt.Fprintln(w, getAdminsPage2Response)
+		}
+		requests = append(requests, r)
+	})
+
+	result, err := duo.GetAdmins()
+	if err != nil {
+		t.Errorf("Unexpected error from GetAdmins call %v", err.Error())
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected two requests, found %d", len(requests))
+	}
+	if len(result.Response) != 2 {
+		t.Fatalf("Expected two admins in the response, found %d", len(result.Response))
+	}
+	owner, helpDesk := result.Response[0], result.Response[1]
+	if owner.Role != AdminRoleOwner || owner.Status != AdminStatusActive || owner.LastLogin == nil {
+		t.Errorf("Unexpected admin %+v", owner)
+	}
+	if helpDesk.Role != AdminRoleHelpDesk || helpDesk.Status != AdminStatusPendingActivation ||
+		!helpDesk.RestrictedByAdminUnits || helpDesk.HardToken == nil || len(helpDesk.AdminUnits) != 1 {
+		t.Errorf("Unexpected admin %+v", helpDesk)
+	}
+}
+
+func TestGetAdmin(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v1/admins/DEFMS1M2IJ2GECDG6N6V" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"admin_id": "DEFMS1M2IJ2GECDG6N6V", "email": "ljones@example.com", "name": "Lisa Jones", "role": "Owner", "status": "Active"}}`)
+	})
+
+	result, err := duo.GetAdmin("DEFMS1M2IJ2GECDG6N6V")
+	if err != nil {
+		t.Errorf("Unexpected error from GetAdmin call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.Email != "ljones@example.com" {
+		t.Errorf("Expected email ljones@example.com, but got %s", result.Response.Email)
+	}
+}
+
+func TestCreateAdmin(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		want := "email=rsmith%40example.com&name=Robert+Smith&role=Help+Desk&send_email=1&valid_days=3"
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/v1/admins" || params.Encode() != want {
+			t.Errorf("Unexpected request %s %s %s", r.Method, r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"admin_id": "DE0UI5GA5Z6EAQY7K1P2", "email": "rsmith@example.com", "name": "Robert Smith", "role": "Help Desk", "status": "Pending Activation"}}`)
+	})
+
+	result, err := duo.CreateAdmin(AdminUpdate{
+		Email:     String("rsmith@example.com"),
+		Name:      String("Robert Smith"),
+		Role:      NewAdminRole(AdminRoleHelpDesk),
+		SendEmail: true,
+		ValidDays: Int(3),
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from CreateAdmin call %v", err.Error())
+	}
+	if result.Response.Status != AdminStatusPendingActivation {
+		t.Errorf("Expected a pending admin, but got %+v", result.Response)
+	}
+
+	_, err = duo.CreateAdmin(AdminUpdate{Name: String("Nobody"), Role: NewAdminRole("Janitor"), Status: NewAdminStatus(AdminStatusActive), ValidDays: Int(60)})
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Fields) != 4 {
+		t.Errorf("Expected four invalid fields, but got %v", err)
+	}
+}
+
+func TestModifyAdmin(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		if r.URL.Path != "/admin/v1/admins/DE0UI5GA5Z6EAQY7K1P2" || params.Encode() != "phone=&role=Read-only&status=Disabled" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"admin_id": "DE0UI5GA5Z6EAQY7K1P2", "role": "Read-only", "status": "Disabled"}}`)
+	})
+
+	result, err := duo.ModifyAdmin("DE0UI5GA5Z6EAQY7K1P2", AdminUpdate{
+		Role:       NewAdminRole(AdminRoleReadOnly),
+		Status:     NewAdminStatus(AdminStatusDisabled),
+		NullFields: []string{"Phone"},
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from ModifyAdmin call %v", err.Error())
+	}
+	if result.Response.Status != AdminStatusDisabled {
+		t.Errorf("Expected a disabled admin, but got %+v", result.Response)
+	}
+
+	if _, err := duo.ModifyAdmin("DE0UI5GA5Z6EAQY7K1P2", AdminUpdate{Email: String("new@example.com")}); err == nil {
+		t.Errorf("Expected a validation error for modifying the email")
+	}
+
+	_, err = duo.ModifyAdmin("DE0UI5GA5Z6EAQY7K1P2", AdminUpdate{SendEmail: true})
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Fields) != 1 || verr.Fields[0].Param != "send_email" {
+		t.Errorf("Expected a send_email error, but got %v", err)
+	}
+	_, err = duo.ModifyAdmin("DE0UI5GA5Z6EAQY7K1P2", AdminUpdate{ValidDays: Int(3)})
+	verr, ok = err.(*ValidationError)
+	if !ok || len(verr.Fields) != 1 || verr.Fields[0].Param != "valid_days" || verr.Fields[0].Value != "3" {
+		t.Errorf("Expected a valid_days error, but got %v", err)
+	}
+}
+
+func TestAdminActions(t *testing.T) {
+	var calls []string
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		fmt.Fprintln(w, `{"stat": "OK", "response": ""}`)
+	})
+
+	for _, call := range []func(string) (*duoapi.StatResult, error){
+		duo.DeleteAdmin,
+		duo.ResetAdminAuthAttempts,
+		duo.ClearAdminExpiration,
+		duo.DeleteAdminActivationLink,
+		duo.DeleteAdminActivation,
+	} {
+		result, err := call("DE0UI5GA5Z6EAQY7K1P2")
+		if err != nil {
+			t.Errorf("Unexpected error %v", err.Error())
+		} else if result.Stat != "OK" {
+			t.Errorf("Expected OK, but got %s", result.Stat)
+		}
+	}
+	want := []string{
+		"DELETE /admin/v1/admins/DE0UI5GA5Z6EAQY7K1P2",
+		"POST /admin/v1/admins/DE0UI5GA5Z6EAQY7K1P2/reset",
+		"POST /admin/v1/admins/DE0UI5GA5Z6EAQY7K1P2/clear_inactivity",
+		"DELETE /admin/v1/admins/DE0UI5GA5Z6EAQY7K1P2/activation_link",
+		"DELETE /admin/v1/admins/activations/DE0UI5GA5Z6EAQY7K1P2",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
+
+const adminActivationLinkResponse = `{
+	"stat": "OK",
+	"response": {
+		"code": "jsRjvGqbxjMGMwnKnwtX",
+		"email": "rsmith@example.com",
+		"link": "https://admin-abcd1234.duosecurity.com/admins/activate?code=jsRjvGqbxjMGMwnKnwtX",
+		"valid_days": 7
+	}
+}`
+
+func TestAdminActivationLink(t *testing.T) {
+	var paths []string
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprintln(w, adminActivationLinkResponse)
+	})
+
+	result, err := duo.CreateAdminActivationLink("DE0UI5GA5Z6EAQY7K1P2")
+	if err != nil {
+		t.Errorf("Unexpected error from CreateAdminActivationLink call %v", err.Error())
+	}
+	if result.Response.Code != "jsRjvGqbxjMGMwnKnwtX" || result.Response.ValidDays != 7 {
+		t.Errorf("Unexpected activation link %+v", result.Response)
+	}
+	if _, err := duo.SendAdminActivationEmail("DE0UI5GA5Z6EAQY7K1P2"); err != nil {
+		t.Errorf("Unexpected error from SendAdminActivationEmail call %v", err.Error())
+	}
+	want := []string{
+		"/admin/v1/admins/DE0UI5GA5Z6EAQY7K1P2/activation_link",
+		"/admin/v1/admins/DE0UI5GA5Z6EAQY7K1P2/activation_link/email",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Expected paths %v, got %v", want, paths)
+	}
+}
+
+func TestCreateAdminActivation(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		want := "admin_role=Help+Desk&email=rsmith%40example.com&send_email=1&valid_days=3"
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/v1/admins/activations" || params.Encode() != want {
+			t.Errorf("Unexpected request %s %s %s", r.Method, r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"admin_activation_id": "DEB6H5X0ZNOCKSTOLVJ2", "email": "rsmith@example.com", "expiration": 1491935563, "link": "https://admin-abcd1234.duosecurity.com/admins/activate?code=jsRjvGqbxjMGMwnKnwtX", "role": "Help Desk", "valid_days": 3}}`)
+	})
+
+	result, err := duo.CreateAdminActivation(AdminActivationCreate{
+		Email:     "rsmith@example.com",
+		Role:      NewAdminRole(AdminRoleHelpDesk),
+		ValidDays: Int(3),
+		SendEmail: true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error from CreateAdminActivation call %v", err.Error())
+	}
+	if result.Response.AdminActivationID != "DEB6H5X0ZNOCKSTOLVJ2" || result.Response.Role != AdminRoleHelpDesk || result.Response.ValidDays != 3 {
+		t.Errorf("Unexpected activation %+v", result.Response)
+	}
+
+	_, err = duo.CreateAdminActivation(AdminActivationCreate{Role: NewAdminRole("Janitor"), ValidDays: Int(60)})
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Fields) != 3 {
+		t.Errorf("Expected three invalid fields, but got %v", err)
+	}
+}
+
+func TestGetAdminActivations(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v1/admins/activations" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprintln(w, `{
+			"stat": "OK",
+			"response": [{
+				"admin_activation_id": "DEB6H5X0ZNOCKSTOLVJ2",
+				"email": "rsmith@example.com",
+				"expiration": 1491935563,
+				"role": "Help Desk",
+				"valid_days": 7
+			}],
+			"metadata": {"total_objects": 1}
+		}`)
+	})
+
+	result, err := duo.GetAdminActivations()
+	if err != nil {
+		t.Errorf("Unexpected error from GetAdminActivations call %v", err.Error())
+	}
+	if len(result.Response) != 1 || result.Response[0].AdminActivationID != "DEB6H5X0ZNOCKSTOLVJ2" || result.Response[0].Role != AdminRoleHelpDesk {
+		t.Errorf("Unexpected activations %+v", result.Response)
+	}
+}
@@ -0,0 +1,241 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// AdminUnit models an administrative unit, which delegates the management
+// of some users and integrations to some administrators.
+type AdminUnit struct {
+	AdminUnitID string `json:"admin_unit_id"`
+	Name        string
+	Description string
+	// RestrictByGroups limits the unit's administrators to the users of
+	// its groups.
+	RestrictByGroups bool `json:"restrict_by_groups"`
+	// RestrictByIntegrations limits the unit's administrators to its
+	// integrations.
+	RestrictByIntegrations bool `json:"restrict_by_integrations"`
+	// Admins, Groups and Integrations hold the admin IDs, group IDs and
+	// integration keys assigned to the unit.
+	Admins       []string
+	Groups       []string
+	Integrations []string
+}
+
+func (u AdminUnit) id() string {
+	return u.AdminUnitID
+}
+
+// AdminUnitUpdate holds the fields of an administrative unit to create or
+// modify.  Nil fields are left unchanged.  Admins, Groups and Integrations
+// are assigned in addition to those the unit already has.
+type AdminUnitUpdate struct {
+	Name                   *string  `url:"name"`
+	Description            *string  `url:"description"`
+	RestrictByGroups       *bool    `url:"restrict_by_groups"`
+	RestrictByIntegrations *bool    `url:"restrict_by_integrations"`
+	Admins                 []string `url:"admins"`
+	Groups                 []string `url:"groups"`
+	Integrations           []string `url:"integrations"`
+}
+
+// AdminUnitsByAdmin limits GetAdminUnits to the units of the administrator
+// with adminID.
+func AdminUnitsByAdmin(adminID string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("admin_id", adminID)
+	}
+}
+
+// AdminUnitsByGroup limits GetAdminUnits to the units of the group with
+// groupID.
+func AdminUnitsByGroup(groupID string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("group_id", groupID)
+	}
+}
+
+// AdminUnitsByIntegration limits GetAdminUnits to the units of the
+// integration with integrationKey.
+func AdminUnitsByIntegration(integrationKey string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("integration_key", integrationKey)
+	}
+}
+
+// GetAdminUnitsResult models responses containing a list of administrative
+// units.
+type GetAdminUnitsResult struct {
+	duoapi.StatResult
+	ListResult
+	Response []AdminUnit
+}
+
+func (result *GetAdminUnitsResult) getResponse() interface{} {
+	return result.Response
+}
+
+func (result *GetAdminUnitsResult) appendResponse(units interface{}) {
+	asserted_units := units.([]AdminUnit)
+	result.Response = append(result.Response, asserted_units...)
+}
+
+// GetAdminUnits calls GET /admin/v1/administrative_units
+// See https://duo.com/docs/adminapi#retrieve-administrative-units
+func (c *Client) GetAdminUnits(options ...func(*url.Values)) (*GetAdminUnitsResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveAdminUnits(params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetAdminUnitsResult), nil
+}
+
+func (c *Client) retrieveAdminUnits(params url.Values) (*GetAdminUnitsResult, error) {
+	_, body, err := c.SignedCall(http.MethodGet, "/admin/v1/administrative_units", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetAdminUnitsResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAdminUnitResult models responses containing a single administrative
+// unit.
+type GetAdminUnitResult struct {
+	duoapi.StatResult
+	Response AdminUnit
+}
+
+// GetAdminUnit calls GET /admin/v1/administrative_units/:admin_unit_id
+// See https://duo.com/docs/adminapi#retrieve-administrative-unit-details
+func (c *Client) GetAdminUnit(adminUnitID string) (*GetAdminUnitResult, error) {
+	path := fmt.Sprintf("/admin/v1/administrative_units/%s", adminUnitID)
+	return c.adminUnitCall(http.MethodGet, path, nil)
+}
+
+// CreateAdminUnit calls POST /admin/v1/administrative_units
+// See https://duo.com/docs/adminapi#add-administrative-unit
+//
+// Name, Description and RestrictByGroups are required.
+func (c *Client) CreateAdminUnit(unit AdminUnitUpdate) (*GetAdminUnitResult, error) {
+	v := &validator{}
+	if unit.Name == nil {
+		v.required("name", "")
+	}
+	if unit.Description == nil {
+		v.required("description", "")
+	}
+	if unit.RestrictByGroups == nil {
+		v.required("restrict_by_groups", "")
+	}
+	unit.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(unit)
+	if err != nil {
+		return nil, err
+	}
+	return c.adminUnitCall(http.MethodPost, "/admin/v1/administrative_units", params)
+}
+
+// ModifyAdminUnit calls POST /admin/v1/administrative_units/:admin_unit_id
+// See https://duo.com/docs/adminapi#modify-administrative-unit
+func (c *Client) ModifyAdminUnit(adminUnitID string, unit AdminUnitUpdate) (*GetAdminUnitResult, error) {
+	path := fmt.Sprintf("/admin/v1/administrative_units/%s", adminUnitID)
+
+	if err := unit.Validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(unit)
+	if err != nil {
+		return nil, err
+	}
+	return c.adminUnitCall(http.MethodPost, path, params)
+}
+
+func (c *Client) adminUnitCall(method, path string, params url.Values) (*GetAdminUnitResult, error) {
+	_, body, err := c.SignedCall(method, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetAdminUnitResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteAdminUnit calls DELETE /admin/v1/administrative_units/:admin_unit_id
+// See https://duo.com/docs/adminapi#delete-administrative-unit
+func (c *Client) DeleteAdminUnit(adminUnitID string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/administrative_units/%s", adminUnitID)
+	return c.adminAction(http.MethodDelete, path)
+}
+
+// Assignments
+
+// AssignAdminToUnit calls POST /admin/v1/administrative_units/:admin_unit_id/admin/:admin_id
+// See https://duo.com/docs/adminapi#assign-administrator-to-administrative-unit
+func (c *Client) AssignAdminToUnit(adminUnitID, adminID string) (*duoapi.StatResult, error) {
+	return c.adminUnitAssignment(http.MethodPost, adminUnitID, "admin", adminID)
+}
+
+// UnassignAdminFromUnit calls DELETE /admin/v1/administrative_units/:admin_unit_id/admin/:admin_id
+// See https://duo.com/docs/adminapi#remove-administrator-from-administrative-unit
+func (c *Client) UnassignAdminFromUnit(adminUnitID, adminID string) (*duoapi.StatResult, error) {
+	return c.adminUnitAssignment(http.MethodDelete, adminUnitID, "admin", adminID)
+}
+
+// AssignGroupToUnit calls POST /admin/v1/administrative_units/:admin_unit_id/group/:group_id
+// See https://duo.com/docs/adminapi#assign-group-to-administrative-unit
+func (c *Client) AssignGroupToUnit(adminUnitID, groupID string) (*duoapi.StatResult, error) {
+	return c.adminUnitAssignment(http.MethodPost, adminUnitID, "group", groupID)
+}
+
+// UnassignGroupFromUnit calls DELETE /admin/v1/administrative_units/:admin_unit_id/group/:group_id
+// See https://duo.com/docs/adminapi#remove-group-from-administrative-unit
+func (c *Client) UnassignGroupFromUnit(adminUnitID, groupID string) (*duoapi.StatResult, error) {
+	return c.adminUnitAssignment(http.MethodDelete, adminUnitID, "group", groupID)
+}
+
+// AssignIntegrationToUnit calls POST /admin/v1/administrative_units/:admin_unit_id/integration/:integration_key
+// See https://duo.com/docs/adminapi#assign-integration-to-administrative-unit
+func (c *Client) AssignIntegrationToUnit(adminUnitID, integrationKey string) (*duoapi.StatResult, error) {
+	return c.adminUnitAssignment(http.MethodPost, adminUnitID, "integration", integrationKey)
+}
+
+// UnassignIntegrationFromUnit calls DELETE /admin/v1/administrative_units/:admin_unit_id/integration/:integration_key
+// See https://duo.com/docs/adminapi#remove-integration-from-administrative-unit
+func (c *Client) UnassignIntegrationFromUnit(adminUnitID, integrationKey string) (*duoapi.StatResult, error) {
+	return c.adminUnitAssignment(http.MethodDelete, adminUnitID, "integration", integrationKey)
+}
+
+func (c *Client) adminUnitAssignment(method, adminUnitID, kind, id string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/administrative_units/%s/%s/%s", adminUnitID, kind, id)
+	return c.adminAction(method, path)
+}
@@ -0,0 +1,187 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+const getAdminUnitsPage1Response = `{
+	"stat": "OK",
+	"response": [{
+		"admin_unit_id": "DHEE7IC1E7JFXMAB1T5R",
+		"admins": ["DEFMS1M2IJ2GECDG6N6V"],
+		"description": "Administrators of the EMEA region",
+		"groups": ["DGAJ2U6NTO0ZX2W4SB4O"],
+		"integrations": ["DIRPNK5JHAV69IOFQ9BA"],
+		"name": "EMEA",
+		"restrict_by_groups": true,
+		"restrict_by_integrations": true
+	}],
+	"metadata": {
+		"prev_offset": null,
+		"next_offset": 1,
+		"total_objects": 2
+	}
+}`
+
+const getAdminUnitsPage2Response = `{
+	"stat": "OK",
+	"response": [{
+		"admin_unit_id": "DHEE7IC1E7JFXMAB1T5S",
+		"admins": [],
+		"description": "Administrators of the APAC region",
+		"groups": [],
+		"integrations": [],
+		"name": "APAC",
+		"restrict_by_groups": false,
+		"restrict_by_integrations": false
+	}],
+	"metadata": {
+		"prev_offset": 0,
+		"next_offset": null,
+		"total_objects": 2
+	}
+}`
+
+func TestGetAdminUnitsMultiple(t *testing.T) {
+	requests := []*http.Request{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if len(requests) == 0 {
+			fmt.Fprintln(w, getAdminUnitsPage1Response)
+		} else {
+			fmt.Fprintln(w, getAdminUnitsPage2Response)
+		}
+		requests = append(requests, r)
+	})
+
+	result, err := duo.GetAdminUnits(AdminUnitsByAdmin("DEFMS1M2IJ2GECDG6N6V"))
+	if err != nil {
+		t.Errorf("Unexpected error from GetAdminUnits call %v", err.Error())
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected two requests, found %d", len(requests))
+	}
+	for _, r := range requests {
+		if r.URL.Query().Get("admin_id") != "DEFMS1M2IJ2GECDG6N6V" {
+			t.Errorf("Expected the admin_id filter on every page, got %s", r.URL.RawQuery)
+		}
+	}
+	if len(result.Response) != 2 {
+		t.Fatalf("Expected two units in the response, found %d", len(result.Response))
+	}
+	emea := result.Response[0]
+	if !emea.RestrictByGroups || !reflect.DeepEqual(emea.Integrations, []string{"DIRPNK5JHAV69IOFQ9BA"}) {
+		t.Errorf("Unexpected unit %+v", emea)
+	}
+}
+
+func TestGetAdminUnit(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v1/administrative_units/DHEE7IC1E7JFXMAB1T5R" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"admin_unit_id": "DHEE7IC1E7JFXMAB1T5R", "name": "EMEA"}}`)
+	})
+
+	result, err := duo.GetAdminUnit("DHEE7IC1E7JFXMAB1T5R")
+	if err != nil {
+		t.Errorf("Unexpected error from GetAdminUnit call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.Name != "EMEA" {
+		t.Errorf("Expected name EMEA, but got %s", result.Response.Name)
+	}
+}
+
+func TestCreateAdminUnit(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		want := "admins=DE1&admins=DE2&description=EMEA+admins&name=EMEA&restrict_by_groups=true"
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/v1/administrative_units" || params.Encode() != want {
+			t.Errorf("Unexpected request %s %s %s", r.Method, r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"admin_unit_id": "DHEE7IC1E7JFXMAB1T5R", "admins": ["DE1", "DE2"], "name": "EMEA", "restrict_by_groups": true}}`)
+	})
+
+	result, err := duo.CreateAdminUnit(AdminUnitUpdate{
+		Name:             String("EMEA"),
+		Description:      String("EMEA admins"),
+		RestrictByGroups: Bool(true),
+		Admins:           []string{"DE1", "DE2"},
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from CreateAdminUnit call %v", err.Error())
+	}
+	if result.Response.AdminUnitID != "DHEE7IC1E7JFXMAB1T5R" || len(result.Response.Admins) != 2 {
+		t.Errorf("Unexpected unit %+v", result.Response)
+	}
+
+	_, err = duo.CreateAdminUnit(AdminUnitUpdate{Name: String("")})
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Fields) != 3 {
+		t.Errorf("Expected three invalid fields, but got %v", err)
+	}
+}
+
+func TestModifyAdminUnit(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		if r.URL.Path != "/admin/v1/administrative_units/DHEE7IC1E7JFXMAB1T5R" || params.Encode() != "restrict_by_integrations=false" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"admin_unit_id": "DHEE7IC1E7JFXMAB1T5R", "restrict_by_integrations": false}}`)
+	})
+
+	result, err := duo.ModifyAdminUnit("DHEE7IC1E7JFXMAB1T5R", AdminUnitUpdate{RestrictByIntegrations: Bool(false)})
+	if err != nil {
+		t.Errorf("Unexpected error from ModifyAdminUnit call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
+func TestAdminUnitAssignments(t *testing.T) {
+	var calls []string
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		fmt.Fprintln(w, `{"stat": "OK", "response": ""}`)
+	})
+
+	for _, call := range []func(string, string) (*duoapi.StatResult, error){
+		duo.AssignAdminToUnit,
+		duo.UnassignAdminFromUnit,
+		duo.AssignGroupToUnit,
+		duo.UnassignGroupFromUnit,
+		duo.AssignIntegrationToUnit,
+		duo.UnassignIntegrationFromUnit,
+	} {
+		result, err := call("DHU1", "ID1")
+		if err != nil {
+			t.Errorf("Unexpected error %v", err.Error())
+		} else if result.Stat != "OK" {
+			t.Errorf("Expected OK, but got %s", result.Stat)
+		}
+	}
+	if _, err := duo.DeleteAdminUnit("DHU1"); err != nil {
+		t.Errorf("Unexpected error from DeleteAdminUnit call %v", err.Error())
+	}
+	want := []string{
+		"POST /admin/v1/administrative_units/DHU1/admin/ID1",
+		"DELETE /admin/v1/administrative_units/DHU1/admin/ID1",
+		"POST /admin/v1/administrative_units/DHU1/group/ID1",
+		"DELETE /admin/v1/administrative_units/DHU1/group/ID1",
+		"POST /admin/v1/administrative_units/DHU1/integration/ID1",
+		"DELETE /admin/v1/administrative_units/DHU1/integration/ID1",
+		"DELETE /admin/v1/administrative_units/DHU1",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
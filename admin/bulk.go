@@ -0,0 +1,379 @@
+package admin
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+const (
+	defaultBulkWorkers = 4
+	defaultBulkRetries = 3
+	defaultBulkBackoff = time.Second
+
+	// codeRateLimited is the code Duo returns once a request has been rate
+	// limited for longer than DuoApi's own retries wait.
+	codeRateLimited = 42901
+)
+
+// BulkOperation is one item of a bulk job.
+type BulkOperation struct {
+	// Key identifies the operation in the report, such as
+	// "DU123 modify user".  Keys must be unique within a job, since Retry
+	// matches failures to operations by key; give operations which would
+	// share a key, such as two modifications of one user, keys of their own.
+	Key string
+	// Action describes the operation in the report, such as "modify user".
+	Action string
+	// Do performs the operation, returning a *StatError for a FAIL
+	// response.
+	Do func(api API) error
+}
+
+// bulkKey returns the key of the operation doing action to the user with
+// userID, such as "DU123 delete user".
+func bulkKey(userID, action string) string {
+	return userID + " " + action
+}
+
+// BulkModifyUser returns an operation which calls ModifyUser.
+func BulkModifyUser(userID string, user UserUpdate) BulkOperation {
+	return BulkOperation{
+		Key:    bulkKey(userID, "modify user"),
+		Action: "modify user",
+		Do: func(api API) error {
+			result, err := api.ModifyUser(userID, user)
+			if err != nil {
+				return err
+			}
+			return checkStat(result.StatResult)
+		},
+	}
+}
+
+// BulkDeleteUser returns an operation which calls DeleteUser.
+func BulkDeleteUser(userID string) BulkOperation {
+	return BulkOperation{
+		Key:    bulkKey(userID, "delete user"),
+		Action: "delete user",
+		Do: func(api API) error {
+			result, err := api.DeleteUser(userID)
+			if err != nil {
+				return err
+			}
+			return checkStat(*result)
+		},
+	}
+}
+
+// BulkAssociateGroupWithUser returns an operation which calls
+// AssociateGroupWithUser.
+func BulkAssociateGroupWithUser(userID, groupID string) BulkOperation {
+	return BulkOperation{
+		Key:    bulkKey(userID, "associate group "+groupID),
+		Action: "associate group " + groupID,
+		Do: func(api API) error {
+			result, err := api.AssociateGroupWithUser(userID, groupID)
+			if err != nil {
+				return err
+			}
+			return checkStat(*result)
+		},
+	}
+}
+
+// BulkDisassociateGroupFromUser returns an operation which calls
+// DisassociateGroupFromUser.
+func BulkDisassociateGroupFromUser(userID, groupID string) BulkOperation {
+	return BulkOperation{
+		Key:    bulkKey(userID, "disassociate group "+groupID),
+		Action: "disassociate group " + groupID,
+		Do: func(api API) error {
+			result, err := api.DisassociateGroupFromUser(userID, groupID)
+			if err != nil {
+				return err
+			}
+			return checkStat(*result)
+		},
+	}
+}
+
+// BulkStatus is the outcome of one bulk operation.
+type BulkStatus string
+
+// Bulk operation outcomes.
+const (
+	BulkSucceeded BulkStatus = "succeeded"
+	BulkFailed    BulkStatus = "failed"
+)
+
+// BulkResult reports the outcome of one bulk operation.
+type BulkResult struct {
+	Key      string     `json:"key"`
+	Action   string     `json:"action,omitempty"`
+	Status   BulkStatus `json:"status"`
+	Attempts int        `json:"attempts"`
+	// Code is the Duo error code of the last failure, if there was one.
+	Code int `json:"code,omitempty"`
+	// Error describes the last failure.
+	Error string `json:"error,omitempty"`
+}
+
+// BulkReport holds the result of every operation of a bulk job, in the
+// order the operations were given.
+type BulkReport struct {
+	Results []BulkResult `json:"results"`
+}
+
+// Succeeded returns the number of operations which succeeded.
+func (r *BulkReport) Succeeded() int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == BulkSucceeded {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed returns the results of the operations which failed.
+func (r *BulkReport) Failed() []BulkResult {
+	var failed []BulkResult
+	for _, result := range r.Results {
+		if result.Status != BulkSucceeded {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// String returns a one-line summary of r.
+func (r *BulkReport) String() string {
+	return fmt.Sprintf("%d succeeded, %d failed", r.Succeeded(), len(r.Failed()))
+}
+
+// Retry returns the operations of ops whose keys failed in r, to run again.
+// Operations which succeeded are left out, even if others on the same user
+// failed.  r may have been read back with ReadBulkReport.
+func (r *BulkReport) Retry(ops []BulkOperation) []BulkOperation {
+	failed := make(map[string]bool)
+	for _, result := range r.Failed() {
+		failed[result.Key] = true
+	}
+	var retry []BulkOperation
+	for _, op := range ops {
+		if failed[op.Key] {
+			retry = append(retry, op)
+		}
+	}
+	return retry
+}
+
+// WriteJSON writes r as JSON, which ReadBulkReport reads back.
+func (r *BulkReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes r as CSV with a header row, one row per operation.
+func (r *BulkReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"key", "action", "status", "attempts", "code", "error"})
+	for _, result := range r.Results {
+		code := ""
+		if result.Code != 0 {
+			code = strconv.Itoa(result.Code)
+		}
+		cw.Write([]string{
+			result.Key,
+			result.Action,
+			string(result.Status),
+			strconv.Itoa(result.Attempts),
+			code,
+			result.Error,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadBulkReport reads a report written by WriteJSON.
+func ReadBulkReport(r io.Reader) (*BulkReport, error) {
+	report := &BulkReport{}
+	if err := json.NewDecoder(r).Decode(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// BulkExecutor runs many independent operations through a bounded pool of
+// workers.  A failed operation is reported and the others continue.
+// Transient failures, which are network errors, rate limiting and Duo
+// server errors, are retried with exponential backoff.  When Duo still
+// rate limits an operation after DuoApi's own retries, every worker pauses
+// before starting its next operation.
+type BulkExecutor struct {
+	// Workers is the number of operations run at once.  The default is 4.
+	Workers int
+	// Retries is the number of times an operation is retried after a
+	// transient failure.  The default is 3; a negative value disables
+	// retries.
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each further
+	// retry.  The default is one second.
+	Backoff time.Duration
+	// Clock is used to wait between retries.  It defaults to the clock of
+	// the *Client given to NewBulkExecutor, or the system clock.
+	Clock duoapi.Clock
+	// OnResult, if set, is called with each result as operations finish.
+	// Calls are never concurrent.
+	OnResult func(BulkResult)
+
+	api        API
+	mu         sync.Mutex
+	pauseUntil time.Time
+}
+
+// NewBulkExecutor returns a BulkExecutor with the default settings which
+// runs operations against api.
+func NewBulkExecutor(api API) *BulkExecutor {
+	b := &BulkExecutor{
+		Workers: defaultBulkWorkers,
+		Retries: defaultBulkRetries,
+		Backoff: defaultBulkBackoff,
+		api:     api,
+	}
+	if c, ok := api.(*Client); ok {
+		b.Clock = c.Clock()
+	} else {
+		b.Clock = duoapi.SystemClock()
+	}
+	return b
+}
+
+// Run runs ops and reports the outcome of each.  It returns once every
+// operation has succeeded or failed.  If two operations share a key, or
+// one has none, Run returns an error without running any of them.
+func (b *BulkExecutor) Run(ops []BulkOperation) (*BulkReport, error) {
+	keys := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		if op.Key == "" {
+			return nil, fmt.Errorf("bulk operation %q has no key", op.Action)
+		}
+		if keys[op.Key] {
+			return nil, fmt.Errorf("duplicate bulk operation key %q", op.Key)
+		}
+		keys[op.Key] = true
+	}
+
+	report := &BulkReport{Results: make([]BulkResult, len(ops))}
+	workers := b.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	var reportMu sync.Mutex
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(ops); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				result := b.run(ops[i])
+				reportMu.Lock()
+				report.Results[i] = result
+				if b.OnResult != nil {
+					b.OnResult(result)
+				}
+				reportMu.Unlock()
+			}
+		}()
+	}
+	for i := range ops {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	return report, nil
+}
+
+// run performs op, retrying transient failures.
+func (b *BulkExecutor) run(op BulkOperation) BulkResult {
+	result := BulkResult{Key: op.Key, Action: op.Action}
+	backoff := b.Backoff
+	for {
+		b.waitForPause()
+		result.Attempts++
+		err := op.Do(b.api)
+		if err == nil {
+			result.Status = BulkSucceeded
+			result.Code = 0
+			result.Error = ""
+			return result
+		}
+
+		result.Status = BulkFailed
+		result.Error = err.Error()
+		result.Code = 0
+		if serr, ok := err.(*StatError); ok && serr.Code != nil {
+			result.Code = int(*serr.Code)
+		}
+		if !transient(err) || result.Attempts > b.Retries {
+			return result
+		}
+
+		if result.Code == codeRateLimited {
+			b.pause(backoff)
+		} else {
+			b.Clock.Sleep(backoff + b.Clock.Jitter(backoff))
+		}
+		backoff *= 2
+	}
+}
+
+// pause stops every worker from starting an operation for d.
+func (b *BulkExecutor) pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	until := b.Clock.Now().Add(d)
+	if until.After(b.pauseUntil) {
+		b.pauseUntil = until
+	}
+}
+
+func (b *BulkExecutor) waitForPause() {
+	b.mu.Lock()
+	wait := b.pauseUntil.Sub(b.Clock.Now())
+	b.mu.Unlock()
+	if wait > 0 {
+		b.Clock.Sleep(wait)
+	}
+}
+
+// transient reports whether err may succeed if retried: network errors and
+// timeouts, rate limiting and Duo server errors.  Anything else, such as a
+// validation error, a missing user or an unreadable response, is permanent.
+func transient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var statErr *StatError
+	if errors.As(err, &statErr) && statErr.Code != nil {
+		// Duo codes extend the HTTP status with two digits, as in 42901.
+		status := int(*statErr.Code) / 100
+		return status == http.StatusTooManyRequests || (status >= 500 && status < 600)
+	}
+	return false
+}
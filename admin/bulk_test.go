@@ -0,0 +1,193 @@
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+func failWith(code int32) error {
+	return &StatError{duoapi.StatResult{Stat: "FAIL", Code: &code}}
+}
+
+func TestBulkExecutor(t *testing.T) {
+	var mu sync.Mutex
+	attempts := make(map[string]int)
+	op := func(key string, errs ...error) BulkOperation {
+		return BulkOperation{
+			Key:    key,
+			Action: "test",
+			Do: func(api API) error {
+				mu.Lock()
+				defer mu.Unlock()
+				n := attempts[key]
+				attempts[key]++
+				if n < len(errs) {
+					return errs[n]
+				}
+				if len(errs) > 0 && errs[len(errs)-1] != nil {
+					return errs[len(errs)-1]
+				}
+				return nil
+			},
+		}
+	}
+	ops := []BulkOperation{
+		op("ok"),
+		op("missing", failWith(40401)),
+		op("flaky", &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset")}, failWith(50001), nil),
+		op("limited", failWith(codeRateLimited)),
+		op("invalid", &ValidationError{Fields: []FieldError{{Param: "status", Reason: "is required"}}}),
+		op("garbled", errors.New("invalid character '<' looking for beginning of value")),
+	}
+
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	b := NewBulkExecutor(nil)
+	b.Clock = clock
+	b.Workers = 2
+	var seen []string
+	b.OnResult = func(result BulkResult) {
+		seen = append(seen, result.Key)
+	}
+	report, err := b.Run(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != len(ops) {
+		t.Errorf("Expected OnResult for every operation, got %v", seen)
+	}
+	if report.String() != "2 succeeded, 4 failed" {
+		t.Errorf("Unexpected report %s", report)
+	}
+	want := []struct {
+		status   BulkStatus
+		attempts int
+		code     int
+	}{
+		{BulkSucceeded, 1, 0},
+		{BulkFailed, 1, 40401},
+		{BulkSucceeded, 3, 0},
+		{BulkFailed, 4, codeRateLimited},
+		{BulkFailed, 1, 0},
+		{BulkFailed, 1, 0},
+	}
+	for i, result := range report.Results {
+		if result.Key != ops[i].Key || result.Status != want[i].status ||
+			result.Attempts != want[i].attempts || result.Code != want[i].code {
+			t.Errorf("Unexpected result %+v for %s", result, ops[i].Key)
+		}
+	}
+	// flaky waits 1s and 2s; limited pauses for 1s, 2s and 4s.
+	if clock.slept < 7*time.Second {
+		t.Errorf("Expected at least 7s of backoff, got %v", clock.slept)
+	}
+
+	var csvOut bytes.Buffer
+	if err := report.WriteCSV(&csvOut); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 7 || lines[0] != "key,action,status,attempts,code,error" ||
+		lines[2] != "missing,test,failed,1,40401,duo: request failed with code 40401" {
+		t.Errorf("Unexpected CSV report:\n%s", csvOut.String())
+	}
+
+	var jsonOut bytes.Buffer
+	if err := report.WriteJSON(&jsonOut); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadBulkReport(&jsonOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry := read.Retry(ops)
+	if len(retry) != 4 || retry[0].Key != "missing" || retry[1].Key != "limited" || retry[2].Key != "invalid" {
+		t.Errorf("Unexpected operations to retry %+v", retry)
+	}
+}
+
+func TestBulkRetryOnlyFailedOperationsOfUser(t *testing.T) {
+	api := &bulkAPI{failGroup: "DGFAIL"}
+	ops := []BulkOperation{
+		BulkModifyUser("DU123", UserUpdate{RealName: String("Joe Smith")}),
+		BulkAssociateGroupWithUser("DU123", "DGOK"),
+		BulkAssociateGroupWithUser("DU123", "DGFAIL"),
+	}
+	b := NewBulkExecutor(api)
+	b.Clock = &fakeClock{}
+	report, err := b.Run(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retry := report.Retry(ops)
+	if len(retry) != 1 || retry[0].Key != "DU123 associate group DGFAIL" {
+		t.Fatalf("Expected to retry only the failed association, got %+v", retry)
+	}
+	api.calls = nil
+	if _, err = b.Run(retry); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.calls) != 1 || api.calls[0] != "associate DU123 DGFAIL" {
+		t.Errorf("Unexpected calls on retry %v", api.calls)
+	}
+}
+
+func TestBulkRunRejectsDuplicateKeys(t *testing.T) {
+	api := &bulkAPI{}
+	ops := []BulkOperation{
+		BulkModifyUser("DU123", UserUpdate{RealName: String("Joe Smith")}),
+		BulkModifyUser("DU123", UserUpdate{Email: String("jsmith@example.com")}),
+	}
+	b := NewBulkExecutor(api)
+	b.Clock = &fakeClock{}
+	if _, err := b.Run(ops); err == nil || !strings.Contains(err.Error(), "DU123 modify user") {
+		t.Fatalf("Expected a duplicate key error, got %v", err)
+	}
+	if len(api.calls) != 0 {
+		t.Errorf("Expected no calls for a rejected job, got %v", api.calls)
+	}
+
+	ops[1].Key = "DU123 modify user email"
+	report, err := b.Run(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Succeeded() != 2 || len(api.calls) != 2 {
+		t.Errorf("Unexpected report %s with calls %v", report, api.calls)
+	}
+}
+
+// bulkAPI records the calls made by bulk operations, failing associations
+// with failGroup.
+type bulkAPI struct {
+	API
+	mu        sync.Mutex
+	failGroup string
+	calls     []string
+}
+
+func (a *bulkAPI) ModifyUser(userID string, user UserUpdate) (*GetUserResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, "modify "+userID)
+	return &GetUserResult{StatResult: duoapi.StatResult{Stat: "OK"}}, nil
+}
+
+func (a *bulkAPI) AssociateGroupWithUser(userID, groupID string) (*duoapi.StatResult, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.calls = append(a.calls, "associate "+userID+" "+groupID)
+	if groupID == a.failGroup {
+		code := int32(40401)
+		return &duoapi.StatResult{Stat: "FAIL", Code: &code}, nil
+	}
+	return &duoapi.StatResult{Stat: "OK"}, nil
+}
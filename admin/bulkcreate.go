@@ -0,0 +1,162 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// maxBulkCreateUsers is the most users Duo creates in one bulk request.
+const maxBulkCreateUsers = 100
+
+// UserCreateResult reports the outcome of creating one user.
+type UserCreateResult struct {
+	// Username is the username of the input user.
+	Username string
+	// User is the created user, or nil if it was not created.
+	User *User
+	// Err explains why the user was not created.  It is a *ValidationError,
+	// a *StatError or the error of the request.
+	Err error
+}
+
+// BulkCreateUsersResult holds the outcome of each user given to
+// BulkCreateUsers, in the order they were given.
+type BulkCreateUsersResult struct {
+	Results []UserCreateResult
+}
+
+// Created returns the users which were created.
+func (r *BulkCreateUsersResult) Created() []User {
+	var users []User
+	for _, result := range r.Results {
+		if result.User != nil {
+			users = append(users, *result.User)
+		}
+	}
+	return users
+}
+
+// Failed returns the outcomes of the users which were not created.
+func (r *BulkCreateUsersResult) Failed() []UserCreateResult {
+	var failed []UserCreateResult
+	for _, result := range r.Results {
+		if result.User == nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+type bulkCreateUsersResponse struct {
+	duoapi.StatResult
+	Response []User
+}
+
+// BulkCreateUsers calls POST /admin/v1/users/bulk_create
+// See https://duo.com/docs/adminapi#create-users-in-bulk
+//
+// Users are sent 100 at a time as a JSON list of the parameters CreateUser
+// would send, and created users are matched to the inputs by username.  If
+// Duo rejects a batch, for example because one of its usernames exists, or
+// leaves some of its users out of the response, those users are created one
+// at a time so that each gets its own outcome.
+//
+// Outcomes, including failed requests, are reported per user; the error is
+// reserved for users which cannot be encoded.
+func (c *Client) BulkCreateUsers(users []User) (*BulkCreateUsersResult, error) {
+	result := &BulkCreateUsersResult{Results: make([]UserCreateResult, len(users))}
+
+	var pending []int
+	for i, user := range users {
+		result.Results[i].Username = user.Username
+		if err := validateNewUser(user); err != nil {
+			result.Results[i].Err = err
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += maxBulkCreateUsers {
+		end := start + maxBulkCreateUsers
+		if end > len(pending) {
+			end = len(pending)
+		}
+		if err := c.bulkCreateChunk(users, pending[start:end], result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// bulkCreateChunk creates the users at the given indexes, recording their
+// outcomes in result.
+func (c *Client) bulkCreateChunk(users []User, indexes []int, result *BulkCreateUsersResult) error {
+	specs := make([]map[string]string, len(indexes))
+	for i, index := range indexes {
+		spec := make(map[string]string)
+		for k, v := range users[index].URLValues() {
+			spec[k] = v[0]
+		}
+		specs[i] = spec
+	}
+	encoded, err := json.Marshal(specs)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("users", string(encoded))
+	response := &bulkCreateUsersResponse{}
+	_, body, err := c.SignedCall(http.MethodPost, "/admin/v1/users/bulk_create", params, duoapi.UseTimeout)
+	if err == nil {
+		err = json.Unmarshal(body, response)
+	}
+	if err == nil {
+		err = checkStat(response.StatResult)
+	}
+	if err != nil && transient(err) {
+		// Creating the users one at a time would fail in the same way.
+		for _, index := range indexes {
+			result.Results[index].Err = err
+		}
+		return nil
+	}
+
+	created := make(map[string]*User)
+	for i := range response.Response {
+		created[strings.ToLower(response.Response[i].Username)] = &response.Response[i]
+	}
+	for _, index := range indexes {
+		if user, ok := created[strings.ToLower(users[index].Username)]; ok {
+			result.Results[index].User = user
+			continue
+		}
+
+		single, err := c.createUser(users[index].URLValues())
+		if err == nil {
+			err = checkStat(single.StatResult)
+		}
+		if err != nil {
+			result.Results[index].Err = err
+			continue
+		}
+		result.Results[index].User = &single.Response
+	}
+	return nil
+}
+
+// validateNewUser checks the fields of a user to create which Duo
+// constrains.
+func validateNewUser(user User) error {
+	v := &validator{}
+	v.required("username", user.Username)
+	if user.Status != "" {
+		v.oneOf("status", user.Status, UserStatus(user.Status).settable(),
+			string(UserStatusActive), string(UserStatusBypass), string(UserStatusDisabled))
+	}
+	return v.err()
+}
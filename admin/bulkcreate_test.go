@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestBulkCreateUsers(t *testing.T) {
+	var batches []int
+	singles := 0
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/admin/v1/users/bulk_create":
+			var specs []map[string]string
+			if err := json.Unmarshal([]byte(r.Form.Get("users")), &specs); err != nil {
+				t.Errorf("Invalid users parameter: %v", err)
+				return
+			}
+			batches = append(batches, len(specs))
+			// Leave user7 out of the response to force a single create.
+			var users []map[string]string
+			for _, spec := range specs {
+				if spec["username"] != "user7" {
+					users = append(users, map[string]string{
+						"user_id":  "DU" + spec["username"],
+						"username": spec["username"],
+						"email":    spec["email"],
+					})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"stat": "OK", "response": users})
+		case "/admin/v1/users":
+			singles++
+			if r.Form.Get("username") != "user7" {
+				t.Errorf("Unexpected single create of %s", r.Form.Get("username"))
+			}
+			w.Write([]byte(`{"stat": "FAIL", "code": 40003, "message": "Duplicate resource", "message_detail": "username"}`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+	})
+
+	var users []User
+	for i := 0; i < 250; i++ {
+		users = append(users, User{Username: fmt.Sprintf("user%d", i), Email: fmt.Sprintf("user%d@example.com", i)})
+	}
+	users = append(users, User{Username: "", Email: "nobody@example.com"})
+
+	result, err := duo.BulkCreateUsers(users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(batches) != "[100 100 50]" || singles != 1 {
+		t.Errorf("Unexpected batches %v and %d single creates", batches, singles)
+	}
+	if len(result.Results) != 251 || len(result.Created()) != 249 {
+		t.Fatalf("Unexpected result of %d users, %d created", len(result.Results), len(result.Created()))
+	}
+	if user := result.Results[42].User; user == nil || user.UserID != "DUuser42" || user.Email != "user42@example.com" {
+		t.Errorf("Unexpected user %+v", user)
+	}
+	failed := result.Failed()
+	if failed[0].Username != "user7" || !isDuplicate(failed[0].Err) {
+		t.Errorf("Expected a duplicate failure, got %+v", failed[0])
+	}
+	if _, ok := failed[1].Err.(*ValidationError); !ok {
+		t.Errorf("Expected a validation failure, got %+v", failed[1])
+	}
+}
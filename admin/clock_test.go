@@ -0,0 +1,30 @@
+package admin
+
+import (
+	"sync"
+	"time"
+)
+
+// fakeClock is a duoapi.Clock whose Sleep advances Now instantly.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.slept += d
+}
+
+func (c *fakeClock) Jitter(max time.Duration) time.Duration {
+	return 0
+}
@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// String returns a pointer to s, for setting the optional fields of
+// UserUpdate and similar structs.
+func String(s string) *string {
+	return &s
+}
+
+// Bool returns a pointer to b, for setting optional fields.
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Int returns a pointer to i, for setting optional fields.
+func Int(i int) *int {
+	return &i
+}
+
+// Flag is a boolean which Duo sends and accepts as 1 or 0.  It also decodes
+// true and false.
+type Flag bool
+
+// NewFlag returns a pointer to a Flag set to b, for setting optional
+// fields.
+func NewFlag(b bool) *Flag {
+	f := Flag(b)
+	return &f
+}
+
+// MarshalText encodes f as "1" or "0".
+func (f Flag) MarshalText() ([]byte, error) {
+	if f {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+// UnmarshalJSON decodes a JSON number or boolean.
+func (f *Flag) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "1", "true":
+		*f = true
+	case "0", "false", "null":
+		*f = false
+	default:
+		return fmt.Errorf("cannot decode %s as a flag", data)
+	}
+	return nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// EncodeValues encodes a struct, or a pointer to one, as request parameters
+// named by the url tags of its fields.  Fields without a url tag, or tagged
+// "-", are not encoded.
+//
+// A nil pointer is skipped, leaving the field unchanged, and any other
+// pointer is encoded as the value it points to, even a zero value.  Other
+// fields are skipped when they hold their zero value.  To clear a field,
+// name it in a []string field called NullFields; it is then sent with an
+// empty value.
+//
+// Strings are sent as they are, bools as "true" or "false", numbers in
+// decimal, time.Time as a Unix timestamp in seconds, time.Duration in whole
+// seconds and encoding.TextMarshalers as their text.  A slice is sent as a
+// repeated parameter, or as one comma separated parameter when its tag has
+// the "comma" option, e.g. `url:"capabilities,comma"`.
+func EncodeValues(v interface{}) (url.Values, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return url.Values{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot encode %T as parameters", v)
+	}
+	rt := rv.Type()
+
+	nulls := make(map[string]bool)
+	if field, ok := rt.FieldByName("NullFields"); ok && field.Type == reflect.TypeOf([]string(nil)) {
+		for _, name := range rv.FieldByName("NullFields").Interface().([]string) {
+			f, ok := rt.FieldByName(name)
+			if !ok || urlTagName(f) == "" {
+				return nil, fmt.Errorf("%s has no parameter field %s", rt.Name(), name)
+			}
+			nulls[name] = true
+		}
+	}
+
+	params := url.Values{}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := urlTagName(field)
+		if name == "" {
+			continue
+		}
+		if nulls[field.Name] {
+			params.Set(name, "")
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		} else if fv.IsZero() {
+			continue
+		}
+
+		values, err := encodeValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", rt.Name(), field.Name, err)
+		}
+		if hasTagOption(field, "comma") {
+			values = []string{strings.Join(values, ",")}
+		}
+		if len(values) > 0 {
+			params[name] = values
+		}
+	}
+	return params, nil
+}
+
+// urlTagName returns the parameter name from a field's url tag, or "" if
+// the field is not encoded.
+func urlTagName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("url"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func hasTagOption(field reflect.StructField, option string) bool {
+	for _, o := range strings.Split(field.Tag.Get("url"), ",")[1:] {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeValue encodes a single field value as one or more strings.
+func encodeValue(v reflect.Value) ([]string, error) {
+	switch {
+	case v.Type() == timeType:
+		return []string{strconv.FormatInt(v.Interface().(time.Time).Unix(), 10)}, nil
+	case v.Type() == durationType:
+		return []string{strconv.FormatInt(int64(v.Interface().(time.Duration)/time.Second), 10)}, nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(text)}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return []string{v.String()}, nil
+	case reflect.Bool:
+		return []string{strconv.FormatBool(v.Bool())}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return []string{strconv.FormatInt(v.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return []string{strconv.FormatUint(v.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return []string{strconv.FormatFloat(v.Float(), 'f', -1, 64)}, nil
+	case reflect.Slice, reflect.Array:
+		var values []string
+		for i := 0; i < v.Len(); i++ {
+			elem, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elem...)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("cannot encode %s as a parameter", v.Type())
+}
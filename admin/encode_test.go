@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeValues(t *testing.T) {
+	type params struct {
+		Name       string        `url:"name"`
+		Enabled    bool          `url:"enabled"`
+		Disabled   *bool         `url:"disabled"`
+		Count      int           `url:"count"`
+		Step       *int          `url:"step"`
+		Ratio      float64       `url:"ratio"`
+		Expires    time.Time     `url:"expires"`
+		Valid      time.Duration `url:"valid_secs"`
+		IP         net.IP        `url:"ip"`
+		IDs        []string      `url:"id"`
+		Caps       []string      `url:"capabilities,comma"`
+		Notes      *string       `url:"notes"`
+		Push       *Flag         `url:"push"`
+		Desc       *string       `url:"desc"`
+		Internal   string        `url:"-"`
+		Untagged   string
+		NullFields []string
+	}
+
+	got, err := EncodeValues(&params{
+		Name:       "jsmith",
+		Enabled:    true,
+		Disabled:   Bool(false),
+		Step:       Int(0),
+		Ratio:      0.5,
+		Expires:    time.Unix(1700000000, 0),
+		Valid:      90 * time.Second,
+		IP:         net.ParseIP("192.0.2.1"),
+		IDs:        []string{"a", "b"},
+		Caps:       []string{"push", "sms"},
+		Notes:      String(""),
+		Push:       NewFlag(true),
+		Desc:       String("ignored"),
+		Internal:   "x",
+		Untagged:   "y",
+		NullFields: []string{"Desc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"name":         {"jsmith"},
+		"enabled":      {"true"},
+		"disabled":     {"false"},
+		"step":         {"0"},
+		"ratio":        {"0.5"},
+		"expires":      {"1700000000"},
+		"valid_secs":   {"90"},
+		"ip":           {"192.0.2.1"},
+		"id":           {"a", "b"},
+		"capabilities": {"push,sms"},
+		"notes":        {""},
+		"push":         {"1"},
+		"desc":         {""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeValues() = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeValuesErrors(t *testing.T) {
+	if _, err := EncodeValues("jsmith"); err == nil {
+		t.Error("Expected an error encoding a string")
+	}
+	if _, err := EncodeValues(UserUpdate{NullFields: []string{"UserID"}}); err == nil {
+		t.Error("Expected an error for an unknown null field")
+	}
+	type unsupported struct {
+		Extra map[string]string `url:"extra"`
+	}
+	if _, err := EncodeValues(unsupported{Extra: map[string]string{"a": "b"}}); err == nil {
+		t.Error("Expected an error encoding a map")
+	}
+	if got, err := EncodeValues((*UserUpdate)(nil)); err != nil || len(got) != 0 {
+		t.Errorf("Expected no parameters for a nil struct, got %v, %v", got, err)
+	}
+}
+
+func TestModifyUserClearsFields(t *testing.T) {
+	var params url.Values
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ = getBodyParams(r)
+		w.Write([]byte(modifyUserResponse))
+	})
+	_, err := duo.ModifyUser("DU3RP9I2WOC59VZX672N", UserUpdate{
+		Email:      String("jsmith-new@example.com"),
+		NullFields: []string{"Alias1", "Notes"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"email":  {"jsmith-new@example.com"},
+		"alias1": {""},
+		"notes":  {""},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("Expected parameters %v, got %v", want, params)
+	}
+}
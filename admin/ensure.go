@@ -0,0 +1,411 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// codeDuplicate is the code Duo returns when a created object already
+// exists.
+const codeDuplicate = 40003
+
+// DesiredUser describes the state to which EnsureUser converges a user.
+type DesiredUser struct {
+	// UserUpdate holds the attributes to converge.  Username is required
+	// and identifies the user; nil fields are left unchanged.
+	UserUpdate
+	// Groups lists the names or IDs of every group the user should belong
+	// to.  Nil leaves memberships unchanged, while an empty, non-nil slice
+	// removes the user from all groups.
+	Groups []string
+}
+
+// EnsureUserResult reports what EnsureUser changed.
+type EnsureUserResult struct {
+	// User is the user after convergence.
+	User User
+	// Created is true if the user did not exist.
+	Created bool
+	// Changes lists the parameters modified on an existing user, such as
+	// "email", followed by the memberships added or removed, such as
+	// "+group Contractors".
+	Changes []string
+}
+
+// Changed reports whether EnsureUser created or modified anything.
+func (r *EnsureUserResult) Changed() bool {
+	return r.Created || len(r.Changes) > 0
+}
+
+// EnsureGroupResult reports what EnsureGroup changed.
+type EnsureGroupResult struct {
+	// Group is the group after convergence.
+	Group Group
+	// Created is true if the group did not exist.
+	Created bool
+	// Changes lists the parameters modified on an existing group.
+	Changes []string
+}
+
+// Changed reports whether EnsureGroup created or modified anything.
+func (r *EnsureGroupResult) Changed() bool {
+	return r.Created || len(r.Changes) > 0
+}
+
+// EnsureUser creates the user named desired.Username or modifies it to
+// match desired, sending only the parameters which differ.  When
+// desired.Groups is set, memberships are added and removed to match.
+// Running EnsureUser again with the same desired state changes nothing.
+//
+// If another client creates the user concurrently, EnsureUser converges the
+// user that client created instead of failing.
+func (c *Client) EnsureUser(desired DesiredUser) (*EnsureUserResult, error) {
+	v := &validator{}
+	if desired.Username == nil {
+		v.required("username", "")
+	}
+	desired.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	// Resolve groups first so that an unknown group changes nothing.
+	var groups []Group
+	if desired.Groups != nil {
+		var err error
+		if groups, err = c.resolveGroups(desired.Groups); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &EnsureUserResult{}
+	user, err := c.findUserByUsername(*desired.Username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		created, err := c.CreateUser(desired.UserUpdate)
+		if err != nil {
+			return nil, err
+		}
+		err = checkStat(created.StatResult)
+		switch {
+		case err == nil:
+			result.Created = true
+			user = &created.Response
+		case isDuplicate(err):
+			if user, err = c.findUserByUsername(*desired.Username); err != nil {
+				return nil, err
+			}
+			if user == nil {
+				return nil, fmt.Errorf("user %q exists but cannot be found", *desired.Username)
+			}
+		default:
+			return nil, err
+		}
+	}
+
+	if !result.Created {
+		update, changes := userChanges(desired.UserUpdate, user)
+		if len(changes) > 0 {
+			modified, err := c.ModifyUser(user.UserID, update)
+			if err != nil {
+				return nil, err
+			}
+			if err = checkStat(modified.StatResult); err != nil {
+				return nil, err
+			}
+			user = &modified.Response
+			result.Changes = changes
+		}
+	}
+
+	if desired.Groups != nil {
+		changes, err := c.ensureUserGroups(user.UserID, groups)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) > 0 {
+			result.Changes = append(result.Changes, changes...)
+			user.Groups = groups
+		}
+	}
+
+	result.User = *user
+	return result, nil
+}
+
+// EnsureGroup creates the group named desired.Name or modifies it to match
+// desired, sending only the parameters which differ.  Running EnsureGroup
+// again with the same desired state changes nothing.
+func (c *Client) EnsureGroup(desired GroupUpdate) (*EnsureGroupResult, error) {
+	v := &validator{}
+	if desired.Name == nil {
+		v.required("name", "")
+	}
+	desired.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	result := &EnsureGroupResult{}
+	group, err := c.findGroupByName(*desired.Name)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		created, err := c.CreateGroup(desired)
+		if err != nil {
+			return nil, err
+		}
+		err = checkStat(created.StatResult)
+		switch {
+		case err == nil:
+			result.Created = true
+			result.Group = created.Response
+			return result, nil
+		case isDuplicate(err):
+			if group, err = c.findGroupByName(*desired.Name); err != nil {
+				return nil, err
+			}
+			if group == nil {
+				return nil, fmt.Errorf("group %q exists but cannot be found", *desired.Name)
+			}
+		default:
+			return nil, err
+		}
+	}
+
+	update, changes := groupChanges(desired, group)
+	if len(changes) > 0 {
+		modified, err := c.ModifyGroup(group.GroupID, update)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkStat(modified.StatResult); err != nil {
+			return nil, err
+		}
+		group = &modified.Response
+		result.Changes = changes
+	}
+	result.Group = *group
+	return result, nil
+}
+
+func isDuplicate(err error) bool {
+	serr, ok := err.(*StatError)
+	return ok && serr.Code != nil && *serr.Code == codeDuplicate
+}
+
+// findUserByUsername returns the user with the given username, or nil.
+// Duo also matches aliases, so the username is compared again.
+func (c *Client) findUserByUsername(username string) (*User, error) {
+	result, err := c.GetUsers(GetUsersUsername(username))
+	if err != nil {
+		return nil, err
+	}
+	if err = checkStat(result.StatResult); err != nil {
+		return nil, err
+	}
+	for i := range result.Response {
+		if strings.EqualFold(result.Response[i].Username, username) {
+			return &result.Response[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// findGroupByName returns the group with the given name, or nil.
+func (c *Client) findGroupByName(name string) (*Group, error) {
+	groups, err := c.resolveGroups(nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		if groups[i].Name == name {
+			return &groups[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// resolveGroups returns the groups with the given names or IDs.  With no
+// refs, it returns every group.
+func (c *Client) resolveGroups(refs []string) ([]Group, error) {
+	result, err := c.GetGroups()
+	if err != nil {
+		return nil, err
+	}
+	if err = checkStat(result.StatResult); err != nil {
+		return nil, err
+	}
+	if refs == nil {
+		return result.Response, nil
+	}
+
+	groups := []Group{}
+	for _, ref := range uniqueStrings(refs) {
+		var match *Group
+		for i, group := range result.Response {
+			if group.GroupID == ref || group.Name == ref {
+				if match != nil && match.GroupID != group.GroupID {
+					return nil, fmt.Errorf("group name %q is ambiguous", ref)
+				}
+				match = &result.Response[i]
+			}
+		}
+		if match == nil {
+			return nil, fmt.Errorf("no group named %q", ref)
+		}
+		groups = append(groups, *match)
+	}
+	return groups, nil
+}
+
+// ensureUserGroups associates and disassociates groups so that the user
+// belongs to exactly the given groups.
+func (c *Client) ensureUserGroups(userID string, groups []Group) ([]string, error) {
+	current, err := c.GetUserGroups(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkStat(current.StatResult); err != nil {
+		return nil, err
+	}
+
+	have := make(map[string]bool, len(current.Response))
+	for _, group := range current.Response {
+		have[group.GroupID] = true
+	}
+	want := make(map[string]bool, len(groups))
+	var changes []string
+	for _, group := range groups {
+		want[group.GroupID] = true
+		if have[group.GroupID] {
+			continue
+		}
+		result, err := c.AssociateGroupWithUser(userID, group.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkStat(*result); err != nil {
+			return nil, err
+		}
+		changes = append(changes, "+group "+group.Name)
+	}
+	for _, group := range current.Response {
+		if want[group.GroupID] {
+			continue
+		}
+		result, err := c.DisassociateGroupFromUser(userID, group.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkStat(*result); err != nil {
+			return nil, err
+		}
+		changes = append(changes, "-group "+group.Name)
+	}
+	return changes, nil
+}
+
+// userChanges returns the update needed to converge user to want, and the
+// names of the parameters it sets.  The username identifies the user, so it
+// is never changed.
+func userChanges(want UserUpdate, user *User) (UserUpdate, []string) {
+	update := UserUpdate{}
+	fields := []struct {
+		name, param string
+		want        *string
+		have        string
+		set         **string
+	}{
+		{"Alias1", "alias1", want.Alias1, deref(user.Alias1), &update.Alias1},
+		{"Alias2", "alias2", want.Alias2, deref(user.Alias2), &update.Alias2},
+		{"Alias3", "alias3", want.Alias3, deref(user.Alias3), &update.Alias3},
+		{"Alias4", "alias4", want.Alias4, deref(user.Alias4), &update.Alias4},
+		{"RealName", "realname", want.RealName, deref(user.RealName), &update.RealName},
+		{"Email", "email", want.Email, user.Email, &update.Email},
+		{"Notes", "notes", want.Notes, user.Notes, &update.Notes},
+		{"FirstName", "firstname", want.FirstName, deref(user.FirstName), &update.FirstName},
+		{"LastName", "lastname", want.LastName, deref(user.LastName), &update.LastName},
+	}
+
+	var changes []string
+	for _, field := range fields {
+		if field.want != nil && *field.want != field.have {
+			*field.set = field.want
+			changes = append(changes, field.param)
+		}
+	}
+	if want.Status != nil && string(*want.Status) != user.Status {
+		update.Status = want.Status
+		changes = append(changes, "status")
+	}
+	for _, name := range want.NullFields {
+		known := false
+		for _, field := range fields {
+			if field.name == name {
+				known = true
+				if field.have != "" {
+					update.NullFields = append(update.NullFields, name)
+					changes = append(changes, field.param)
+				}
+			}
+		}
+		if !known {
+			// Let ModifyUser reject the unknown field.
+			update.NullFields = append(update.NullFields, name)
+			changes = append(changes, name)
+		}
+	}
+	return update, changes
+}
+
+// groupChanges returns the update needed to converge group to want, and the
+// names of the parameters it sets.  The name identifies the group, so it is
+// never changed.
+func groupChanges(want GroupUpdate, group *Group) (GroupUpdate, []string) {
+	update := GroupUpdate{}
+	var changes []string
+	if want.Desc != nil && *want.Desc != group.Desc {
+		update.Desc = want.Desc
+		changes = append(changes, "desc")
+	}
+	// Duo reports group statuses capitalized, as in "Active".
+	if want.Status != nil && !strings.EqualFold(string(*want.Status), group.Status) {
+		update.Status = want.Status
+		changes = append(changes, "status")
+	}
+	flags := []struct {
+		param string
+		want  *bool
+		have  bool
+		set   **bool
+	}{
+		{"push_enabled", want.PushEnabled, group.PushEnabled, &update.PushEnabled},
+		{"sms_enabled", want.SMSEnabled, group.SMSEnabled, &update.SMSEnabled},
+		{"voice_enabled", want.VoiceEnabled, group.VoiceEnabled, &update.VoiceEnabled},
+		{"mobile_otp_enabled", want.MobileOTPEnabled, group.MobileOTPEnabled, &update.MobileOTPEnabled},
+	}
+	for _, flag := range flags {
+		if flag.want != nil && *flag.want != flag.have {
+			*flag.set = flag.want
+			changes = append(changes, flag.param)
+		}
+	}
+	for _, name := range want.NullFields {
+		if name != "Desc" || group.Desc != "" {
+			update.NullFields = append(update.NullFields, name)
+			changes = append(changes, strings.ToLower(name))
+		}
+	}
+	return update, changes
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
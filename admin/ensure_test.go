@@ -0,0 +1,231 @@
+package admin
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// TestEnsureUserCreateRace checks that EnsureUser converges a user created
+// by another client between its lookup and its create.
+func TestEnsureUserCreateRace(t *testing.T) {
+	var calls []string
+	lookups := 0
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/users":
+			lookups++
+			if lookups == 1 {
+				w.Write([]byte(`{"stat": "OK", "response": [], "metadata": {}}`))
+				return
+			}
+			w.Write([]byte(`{"stat": "OK", "response": [{"user_id": "DU1", "username": "jsmith", "email": "old@example.com", "status": "active"}], "metadata": {}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/users":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"stat": "FAIL", "code": 40003, "message": "Duplicate resource", "message_detail": "username"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/users/DU1":
+			if r.Form.Encode() != "email=jsmith%40example.com" {
+				t.Errorf("Unexpected modify parameters %s", r.Form.Encode())
+			}
+			w.Write([]byte(`{"stat": "OK", "response": {"user_id": "DU1", "username": "jsmith", "email": "jsmith@example.com", "status": "active"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	result, err := duo.EnsureUser(DesiredUser{UserUpdate: UserUpdate{
+		Username: String("jsmith"),
+		Email:    String("jsmith@example.com"),
+		Status:   NewUserStatus(UserStatusActive),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created || !reflect.DeepEqual(result.Changes, []string{"email"}) || result.User.Email != "jsmith@example.com" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	want := []string{
+		"GET /admin/v1/users",
+		"POST /admin/v1/users",
+		"GET /admin/v1/users",
+		"POST /admin/v1/users/DU1",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
+
+// TestEnsureUserUpdatesExisting checks that EnsureUser sends only the
+// parameters and memberships which differ on an existing user.
+func TestEnsureUserUpdatesExisting(t *testing.T) {
+	var calls []string
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/groups":
+			w.Write([]byte(`{"stat": "OK", "response": [{"group_id": "DG1", "name": "Contractors"}, {"group_id": "DG2", "name": "Staff"}], "metadata": {}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/users":
+			w.Write([]byte(`{"stat": "OK", "response": [{"user_id": "DU1", "username": "jsmith", "realname": "Joe Smith", "email": "old@example.com", "status": "active"}], "metadata": {}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/users/DU1":
+			if r.Form.Encode() != "email=jsmith%40example.com" {
+				t.Errorf("Unexpected modify parameters %s", r.Form.Encode())
+			}
+			w.Write([]byte(`{"stat": "OK", "response": {"user_id": "DU1", "username": "jsmith", "realname": "Joe Smith", "email": "jsmith@example.com", "status": "active"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/users/DU1/groups":
+			w.Write([]byte(`{"stat": "OK", "response": [{"group_id": "DG2", "name": "Staff"}], "metadata": {}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/users/DU1/groups":
+			if r.Form.Get("group_id") != "DG1" {
+				t.Errorf("Unexpected association parameters %s", r.Form.Encode())
+			}
+			w.Write([]byte(`{"stat": "OK", "response": ""}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/admin/v1/users/DU1/groups/DG2":
+			w.Write([]byte(`{"stat": "OK", "response": ""}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	result, err := duo.EnsureUser(DesiredUser{
+		UserUpdate: UserUpdate{
+			Username: String("jsmith"),
+			RealName: String("Joe Smith"),
+			Email:    String("jsmith@example.com"),
+			Status:   NewUserStatus(UserStatusActive),
+		},
+		Groups: []string{"Contractors"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantChanges := []string{"email", "+group Contractors", "-group Staff"}
+	if result.Created || !reflect.DeepEqual(result.Changes, wantChanges) || result.User.Email != "jsmith@example.com" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	want := []string{
+		"GET /admin/v1/groups",
+		"GET /admin/v1/users",
+		"POST /admin/v1/users/DU1",
+		"GET /admin/v1/users/DU1/groups",
+		"POST /admin/v1/users/DU1/groups",
+		"DELETE /admin/v1/users/DU1/groups/DG2",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
+
+// TestEnsureUserUnchanged checks that EnsureUser makes no changes to a user
+// already in the desired state.
+func TestEnsureUserUnchanged(t *testing.T) {
+	var calls []string
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/groups":
+			w.Write([]byte(`{"stat": "OK", "response": [{"group_id": "DG1", "name": "Contractors"}], "metadata": {}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/users":
+			w.Write([]byte(`{"stat": "OK", "response": [{"user_id": "DU1", "username": "jsmith", "email": "jsmith@example.com", "status": "active"}], "metadata": {}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/users/DU1/groups":
+			w.Write([]byte(`{"stat": "OK", "response": [{"group_id": "DG1", "name": "Contractors"}], "metadata": {}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	result, err := duo.EnsureUser(DesiredUser{
+		UserUpdate: UserUpdate{
+			Username: String("jsmith"),
+			Email:    String("jsmith@example.com"),
+			Status:   NewUserStatus(UserStatusActive),
+		},
+		Groups: []string{"DG1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed() || result.User.UserID != "DU1" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	want := []string{
+		"GET /admin/v1/groups",
+		"GET /admin/v1/users",
+		"GET /admin/v1/users/DU1/groups",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+}
+
+// TestEnsureGroup checks that EnsureGroup creates a missing group, modifies
+// only the differing parameters of an existing one and leaves a group in the
+// desired state alone.
+func TestEnsureGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		created  bool
+		changes  []string
+		calls    []string
+	}{
+		{
+			name:    "create",
+			created: true,
+			calls:   []string{"GET /admin/v1/groups", "POST /admin/v1/groups"},
+		},
+		{
+			name:     "update",
+			existing: `{"group_id": "DG1", "name": "Contractors", "desc": "Old", "status": "Active"}`,
+			changes:  []string{"desc"},
+			calls:    []string{"GET /admin/v1/groups", "POST /admin/v1/groups/DG1"},
+		},
+		{
+			name:     "unchanged",
+			existing: `{"group_id": "DG1", "name": "Contractors", "desc": "Contract staff", "status": "Active"}`,
+			calls:    []string{"GET /admin/v1/groups"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var calls []string
+			duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				calls = append(calls, r.Method+" "+r.URL.Path)
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/admin/v1/groups":
+					w.Write([]byte(`{"stat": "OK", "response": [` + test.existing + `], "metadata": {}}`))
+				case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/groups":
+					if r.Form.Encode() != "desc=Contract+staff&name=Contractors&status=active" {
+						t.Errorf("Unexpected create parameters %s", r.Form.Encode())
+					}
+					w.Write([]byte(`{"stat": "OK", "response": {"group_id": "DG1", "name": "Contractors", "desc": "Contract staff", "status": "Active"}}`))
+				case r.Method == http.MethodPost && r.URL.Path == "/admin/v1/groups/DG1":
+					if r.Form.Encode() != "desc=Contract+staff" {
+						t.Errorf("Unexpected modify parameters %s", r.Form.Encode())
+					}
+					w.Write([]byte(`{"stat": "OK", "response": {"group_id": "DG1", "name": "Contractors", "desc": "Contract staff", "status": "Active"}}`))
+				default:
+					t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+				}
+			})
+
+			result, err := duo.EnsureGroup(GroupUpdate{
+				Name:   String("Contractors"),
+				Desc:   String("Contract staff"),
+				Status: NewGroupStatus(GroupStatusActive),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Created != test.created || !reflect.DeepEqual(result.Changes, test.changes) ||
+				result.Group.GroupID != "DG1" || result.Group.Desc != "Contract staff" {
+				t.Errorf("Unexpected result %+v", result)
+			}
+			if !reflect.DeepEqual(calls, test.calls) {
+				t.Errorf("Expected calls %v, got %v", test.calls, calls)
+			}
+		})
+	}
+}
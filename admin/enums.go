@@ -0,0 +1,217 @@
+package admin
+
+import "strings"
+
+// UserStatus is the status of a user.
+type UserStatus string
+
+// User statuses.  Only UserStatusActive, UserStatusBypass and
+// UserStatusDisabled may be set; Duo sets the others.
+const (
+	UserStatusActive          UserStatus = "active"
+	UserStatusBypass          UserStatus = "bypass"
+	UserStatusDisabled        UserStatus = "disabled"
+	UserStatusLockedOut       UserStatus = "locked out"
+	UserStatusPendingDeletion UserStatus = "pending deletion"
+)
+
+// Valid reports whether s is a documented user status.
+func (s UserStatus) Valid() bool {
+	switch s {
+	case UserStatusActive, UserStatusBypass, UserStatusDisabled, UserStatusLockedOut, UserStatusPendingDeletion:
+		return true
+	}
+	return false
+}
+
+// settable reports whether s may be set by a create or modify request.
+func (s UserStatus) settable() bool {
+	return s == UserStatusActive || s == UserStatusBypass || s == UserStatusDisabled
+}
+
+// NewUserStatus returns a pointer to s, for setting UserUpdate.Status.
+func NewUserStatus(s UserStatus) *UserStatus {
+	return &s
+}
+
+// GroupStatus is the status of a group, applied to its members.
+type GroupStatus string
+
+// Group statuses.
+const (
+	GroupStatusActive   GroupStatus = "active"
+	GroupStatusBypass   GroupStatus = "bypass"
+	GroupStatusDisabled GroupStatus = "disabled"
+)
+
+// Valid reports whether s is a documented group status.
+func (s GroupStatus) Valid() bool {
+	switch s {
+	case GroupStatusActive, GroupStatusBypass, GroupStatusDisabled:
+		return true
+	}
+	return false
+}
+
+// NewGroupStatus returns a pointer to s, for setting GroupUpdate.Status.
+func NewGroupStatus(s GroupStatus) *GroupStatus {
+	return &s
+}
+
+// PhoneType is the type of a phone.  Duo capitalizes the types it returns,
+// so Valid ignores case.
+type PhoneType string
+
+// Phone types.
+const (
+	PhoneTypeUnknown  PhoneType = "unknown"
+	PhoneTypeMobile   PhoneType = "mobile"
+	PhoneTypeLandline PhoneType = "landline"
+)
+
+var phoneTypes = []PhoneType{PhoneTypeUnknown, PhoneTypeMobile, PhoneTypeLandline}
+
+// Valid reports whether t is a documented phone type.
+func (t PhoneType) Valid() bool {
+	for _, v := range phoneTypes {
+		if strings.EqualFold(string(t), string(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPhoneType returns a pointer to t, for setting PhoneUpdate.Type.
+func NewPhoneType(t PhoneType) *PhoneType {
+	return &t
+}
+
+// PhonePlatform is the platform of a phone.  Duo capitalizes the platforms
+// it returns, such as "Apple iOS", so Valid ignores case.
+type PhonePlatform string
+
+// Phone platforms.
+const (
+	PhonePlatformUnknown           PhonePlatform = "unknown"
+	PhonePlatformGoogleAndroid     PhonePlatform = "google android"
+	PhonePlatformAppleIOS          PhonePlatform = "apple ios"
+	PhonePlatformWindowsPhone      PhonePlatform = "windows phone"
+	PhonePlatformRIMBlackberry     PhonePlatform = "rim blackberry"
+	PhonePlatformJavaJ2ME          PhonePlatform = "java j2me"
+	PhonePlatformPalmWebOS         PhonePlatform = "palm webos"
+	PhonePlatformSymbianOS         PhonePlatform = "symbian os"
+	PhonePlatformWindowsMobile     PhonePlatform = "windows mobile"
+	PhonePlatformGenericSmartphone PhonePlatform = "generic smartphone"
+)
+
+var phonePlatforms = []PhonePlatform{
+	PhonePlatformUnknown, PhonePlatformGoogleAndroid, PhonePlatformAppleIOS,
+	PhonePlatformWindowsPhone, PhonePlatformRIMBlackberry, PhonePlatformJavaJ2ME,
+	PhonePlatformPalmWebOS, PhonePlatformSymbianOS, PhonePlatformWindowsMobile,
+	PhonePlatformGenericSmartphone,
+}
+
+// Valid reports whether p is a documented phone platform.
+func (p PhonePlatform) Valid() bool {
+	for _, v := range phonePlatforms {
+		if strings.EqualFold(string(p), string(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPhonePlatform returns a pointer to p, for setting PhoneUpdate.Platform.
+func NewPhonePlatform(p PhonePlatform) *PhonePlatform {
+	return &p
+}
+
+// TokenType is the type of a hardware token.
+type TokenType string
+
+// Hardware token types.
+const (
+	TokenTypeHOTP6   TokenType = "h6"
+	TokenTypeHOTP8   TokenType = "h8"
+	TokenTypeTOTP6   TokenType = "t6"
+	TokenTypeTOTP8   TokenType = "t8"
+	TokenTypeYubiKey TokenType = "yk"
+	TokenTypeDuoD100 TokenType = "d1"
+)
+
+// Valid reports whether t is a documented token type.
+func (t TokenType) Valid() bool {
+	switch t {
+	case TokenTypeHOTP6, TokenTypeHOTP8, TokenTypeTOTP6, TokenTypeTOTP8, TokenTypeYubiKey, TokenTypeDuoD100:
+		return true
+	}
+	return false
+}
+
+// AdminRole is the role of an administrator, which sets its permissions.
+type AdminRole string
+
+// Administrator roles.
+const (
+	AdminRoleOwner              AdminRole = "Owner"
+	AdminRoleAdministrator      AdminRole = "Administrator"
+	AdminRoleApplicationManager AdminRole = "Application Manager"
+	AdminRoleUserManager        AdminRole = "User Manager"
+	AdminRoleSecurityAnalyst    AdminRole = "Security Analyst"
+	AdminRoleHelpDesk           AdminRole = "Help Desk"
+	AdminRoleBilling            AdminRole = "Billing"
+	AdminRolePhishingManager    AdminRole = "Phishing Manager"
+	AdminRoleReadOnly           AdminRole = "Read-only"
+)
+
+var adminRoles = []AdminRole{
+	AdminRoleOwner, AdminRoleAdministrator, AdminRoleApplicationManager,
+	AdminRoleUserManager, AdminRoleSecurityAnalyst, AdminRoleHelpDesk,
+	AdminRoleBilling, AdminRolePhishingManager, AdminRoleReadOnly,
+}
+
+// Valid reports whether r is a documented administrator role.
+func (r AdminRole) Valid() bool {
+	for _, v := range adminRoles {
+		if r == v {
+			return true
+		}
+	}
+	return false
+}
+
+// NewAdminRole returns a pointer to r, for setting AdminUpdate.Role.
+func NewAdminRole(r AdminRole) *AdminRole {
+	return &r
+}
+
+// AdminStatus is the status of an administrator.
+type AdminStatus string
+
+// Administrator statuses.  Only AdminStatusActive and AdminStatusDisabled
+// may be set; Duo sets the others.
+const (
+	AdminStatusActive            AdminStatus = "Active"
+	AdminStatusDisabled          AdminStatus = "Disabled"
+	AdminStatusExpired           AdminStatus = "Expired"
+	AdminStatusPendingActivation AdminStatus = "Pending Activation"
+)
+
+// Valid reports whether s is a documented administrator status.
+func (s AdminStatus) Valid() bool {
+	switch s {
+	case AdminStatusActive, AdminStatusDisabled, AdminStatusExpired, AdminStatusPendingActivation:
+		return true
+	}
+	return false
+}
+
+// settable reports whether s may be set by a modify request.
+func (s AdminStatus) settable() bool {
+	return s == AdminStatusActive || s == AdminStatusDisabled
+}
+
+// NewAdminStatus returns a pointer to s, for setting AdminUpdate.Status.
+func NewAdminStatus(s AdminStatus) *AdminStatus {
+	return &s
+}
@@ -0,0 +1,255 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// Integration models an application protected by Duo, or an API
+// integration such as this one.
+type Integration struct {
+	// The AdminAPI permissions apply only to Admin API integrations.
+	AdminAPIAdmins        Flag `json:"adminapi_admins"`
+	AdminAPIInfo          Flag `json:"adminapi_info"`
+	AdminAPIIntegrations  Flag `json:"adminapi_integrations"`
+	AdminAPIReadLog       Flag `json:"adminapi_read_log"`
+	AdminAPIReadResource  Flag `json:"adminapi_read_resource"`
+	AdminAPISettings      Flag `json:"adminapi_settings"`
+	AdminAPIWriteResource Flag `json:"adminapi_write_resource"`
+	Greeting              string
+	// GroupsAllowed holds the IDs of the groups allowed to use the
+	// integration, or is empty when every user may.
+	GroupsAllowed  []string `json:"groups_allowed"`
+	IntegrationKey string   `json:"integration_key"`
+	Name           string
+	Notes          string
+	// SecretKey is only returned by some requests; use
+	// GetIntegrationSecretKey to read it.
+	SecretKey          string `json:"secret_key"`
+	SelfServiceAllowed Flag   `json:"self_service_allowed"`
+	Type               string
+}
+
+func (i Integration) id() string {
+	return i.IntegrationKey
+}
+
+// IntegrationUpdate holds the fields of an integration to create or modify.
+// Nil fields are left unchanged; name fields in NullFields to clear them.
+type IntegrationUpdate struct {
+	Name *string `url:"name"`
+	// Type, such as "websdk" or "adminapi", may only be set by
+	// CreateIntegration.
+	Type                  string   `url:"type"`
+	Notes                 *string  `url:"notes"`
+	Greeting              *string  `url:"greeting"`
+	GroupsAllowed         []string `url:"groups_allowed,comma"`
+	SelfServiceAllowed    *Flag    `url:"self_service_allowed"`
+	AdminAPIAdmins        *Flag    `url:"adminapi_admins"`
+	AdminAPIInfo          *Flag    `url:"adminapi_info"`
+	AdminAPIIntegrations  *Flag    `url:"adminapi_integrations"`
+	AdminAPIReadLog       *Flag    `url:"adminapi_read_log"`
+	AdminAPIReadResource  *Flag    `url:"adminapi_read_resource"`
+	AdminAPISettings      *Flag    `url:"adminapi_settings"`
+	AdminAPIWriteResource *Flag    `url:"adminapi_write_resource"`
+
+	NullFields []string
+}
+
+// GetIntegrationsResult models responses containing a list of
+// integrations.
+type GetIntegrationsResult struct {
+	duoapi.StatResult
+	ListResult
+	Response []Integration
+}
+
+func (result *GetIntegrationsResult) getResponse() interface{} {
+	return result.Response
+}
+
+func (result *GetIntegrationsResult) appendResponse(integrations interface{}) {
+	asserted_integrations := integrations.([]Integration)
+	result.Response = append(result.Response, asserted_integrations...)
+}
+
+// GetIntegrations calls GET /admin/v1/integrations
+// See https://duo.com/docs/adminapi#retrieve-integrations
+func (c *Client) GetIntegrations(options ...func(*url.Values)) (*GetIntegrationsResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrieveIntegrations(params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetIntegrationsResult), nil
+}
+
+func (c *Client) retrieveIntegrations(params url.Values) (*GetIntegrationsResult, error) {
+	_, body, err := c.SignedCall(http.MethodGet, "/admin/v1/integrations", params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetIntegrationsResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetIntegrationResult models responses containing a single integration.
+type GetIntegrationResult struct {
+	duoapi.StatResult
+	Response Integration
+}
+
+// GetIntegration calls GET /admin/v1/integrations/:integration_key
+// See https://duo.com/docs/adminapi#retrieve-integration-by-integration-key
+func (c *Client) GetIntegration(integrationKey string) (*GetIntegrationResult, error) {
+	path := fmt.Sprintf("/admin/v1/integrations/%s", integrationKey)
+	return c.integrationCall(http.MethodGet, path, nil)
+}
+
+// CreateIntegration calls POST /admin/v1/integrations
+// See https://duo.com/docs/adminapi#create-integration
+//
+// Name and Type are required.  The response holds the new integration's
+// secret key.
+func (c *Client) CreateIntegration(integration IntegrationUpdate) (*GetIntegrationResult, error) {
+	v := &validator{}
+	if integration.Name == nil {
+		v.required("name", "")
+	}
+	v.required("type", integration.Type)
+	integration.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(integration)
+	if err != nil {
+		return nil, err
+	}
+	return c.integrationCall(http.MethodPost, "/admin/v1/integrations", params)
+}
+
+// ModifyIntegration calls POST /admin/v1/integrations/:integration_key
+// See https://duo.com/docs/adminapi#modify-integration
+func (c *Client) ModifyIntegration(integrationKey string, integration IntegrationUpdate) (*GetIntegrationResult, error) {
+	path := fmt.Sprintf("/admin/v1/integrations/%s", integrationKey)
+
+	v := &validator{}
+	if integration.Type != "" {
+		v.add("type", integration.Type, "cannot be modified")
+	}
+	integration.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	params, err := EncodeValues(integration)
+	if err != nil {
+		return nil, err
+	}
+	return c.integrationCall(http.MethodPost, path, params)
+}
+
+func (c *Client) integrationCall(method, path string, params url.Values) (*GetIntegrationResult, error) {
+	_, body, err := c.SignedCall(method, path, params, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetIntegrationResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteIntegration calls DELETE /admin/v1/integrations/:integration_key
+// See https://duo.com/docs/adminapi#delete-integration
+func (c *Client) DeleteIntegration(integrationKey string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v1/integrations/%s", integrationKey)
+	return c.adminAction(http.MethodDelete, path)
+}
+
+// Secret keys
+
+// IntegrationSecretKey models the secret key of an integration.
+type IntegrationSecretKey struct {
+	SecretKey string `json:"secret_key"`
+}
+
+// IntegrationSecretKeyResult models responses containing the secret key of
+// an integration.
+type IntegrationSecretKeyResult struct {
+	duoapi.StatResult
+	Response IntegrationSecretKey
+}
+
+// GetIntegrationSecretKey calls GET /admin/v1/integrations/:integration_key/skey
+// See https://duo.com/docs/adminapi#retrieve-integration-secret-key
+func (c *Client) GetIntegrationSecretKey(integrationKey string) (*IntegrationSecretKeyResult, error) {
+	path := fmt.Sprintf("/admin/v1/integrations/%s/skey", integrationKey)
+
+	_, body, err := c.SignedCall(http.MethodGet, path, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IntegrationSecretKeyResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RotateIntegrationSecretKey replaces the secret key of the integration
+// with integrationKey and returns the new key.  The old key stops working
+// at once, so switch clients to the new one, for example with
+// duoapi.NewDuoApi(integrationKey, secretKey, ...), before using them again.
+// Rotate the key of the integration c itself signs with from another
+// integration, since c may need its old key to read the new one.  A FAIL
+// response is returned as a *StatError.
+func (c *Client) RotateIntegrationSecretKey(integrationKey string) (string, error) {
+	path := fmt.Sprintf("/admin/v1/integrations/%s", integrationKey)
+
+	params := url.Values{}
+	params.Set("reset_secret_key", "1")
+	reset, err := c.integrationCall(http.MethodPost, path, params)
+	if err != nil {
+		return "", err
+	}
+	if err := checkStat(reset.StatResult); err != nil {
+		return "", err
+	}
+	if reset.Response.SecretKey != "" {
+		return reset.Response.SecretKey, nil
+	}
+
+	// Newer accounts leave the key out of integration responses.
+	key, err := c.GetIntegrationSecretKey(integrationKey)
+	if err != nil {
+		return "", err
+	}
+	if err := checkStat(key.StatResult); err != nil {
+		return "", err
+	}
+	return key.Response.SecretKey, nil
+}
@@ -0,0 +1,221 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const getIntegrationsPage1Response = `{
+	"stat": "OK",
+	"response": [{
+		"adminapi_admins": 0,
+		"adminapi_info": 1,
+		"adminapi_integrations": 1,
+		"adminapi_read_log": 1,
+		"adminapi_read_resource": 1,
+		"adminapi_settings": 0,
+		"adminapi_write_resource": 1,
+		"greeting": "",
+		"groups_allowed": [],
+		"integration_key": "DIRPNK5JHAV69IOFQ9BA",
+		"name": "Provisioning",
+		"notes": "",
+		"self_service_allowed": false,
+		"type": "adminapi"
+	}],
+	"metadata": {
+		"prev_offset": null,
+		"next_offset": 1,
+		"total_objects": 2
+	}
+}`
+
+const getIntegrationsPage2Response = `{
+	"stat": "OK",
+	"response": [{
+		"greeting": "Welcome",
+		"groups_allowed": ["DGAJ2U6NTO0ZX2W4SB4O"],
+		"integration_key": "DIWHCQ6KZFXEH5Z3X1G5",
+		"name": "Intranet",
+		"notes": "Web SDK",
+		"self_service_allowed": true,
+		"type": "websdk"
+	}],
+	"metadata": {
+		"prev_offset": 0,
+		"next_offset": null,
+		"total_objects": 2
+	}
+}`
+
+func TestGetIntegrationsMultiple(t *testing.T) {
+	requests := []*http.Request{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if len(requests) == 0 {
+			fmt.Fprintln(w, getIntegrationsPage1Response)
+		} else {
+			fmt.Fprintln(w, getIntegrationsPage2Response)
+		}
+		requests = append(requests, r)
+	})
+
+	result, err := duo.GetIntegrations()
+	if err != nil {
+		t.Errorf("Unexpected error from GetIntegrations call %v", err.Error())
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected two requests, found %d", len(requests))
+	}
+	if len(result.Response) != 2 {
+		t.Fatalf("Expected two integrations in the response, found %d", len(result.Response))
+	}
+	adminAPI, web := result.Response[0], result.Response[1]
+	want := Integration{
+		AdminAPIInfo:          true,
+		AdminAPIIntegrations:  true,
+		AdminAPIReadLog:       true,
+		AdminAPIReadResource:  true,
+		AdminAPIWriteResource: true,
+		GroupsAllowed:         []string{},
+		IntegrationKey:        "DIRPNK5JHAV69IOFQ9BA",
+		Name:                  "Provisioning",
+		Type:                  "adminapi",
+	}
+	if !reflect.DeepEqual(adminAPI, want) {
+		t.Errorf("Unexpected integration %+v", adminAPI)
+	}
+	if !bool(web.SelfServiceAllowed) || !reflect.DeepEqual(web.GroupsAllowed, []string{"DGAJ2U6NTO0ZX2W4SB4O"}) {
+		t.Errorf("Unexpected integration %+v", web)
+	}
+}
+
+func TestGetIntegration(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/v1/integrations/DIRPNK5JHAV69IOFQ9BA" {
+			t.Errorf("Unexpected request path %s", r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"integration_key": "DIRPNK5JHAV69IOFQ9BA", "name": "Provisioning", "type": "adminapi"}}`)
+	})
+
+	result, err := duo.GetIntegration("DIRPNK5JHAV69IOFQ9BA")
+	if err != nil {
+		t.Errorf("Unexpected error from GetIntegration call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+	if result.Response.Name != "Provisioning" {
+		t.Errorf("Expected name Provisioning, but got %s", result.Response.Name)
+	}
+}
+
+func TestCreateIntegration(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		want := "adminapi_read_log=1&adminapi_write_resource=0&groups_allowed=DG1%2CDG2&name=Provisioning&type=adminapi"
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/v1/integrations" || params.Encode() != want {
+			t.Errorf("Unexpected request %s %s %s", r.Method, r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"integration_key": "DIRPNK5JHAV69IOFQ9BA", "name": "Provisioning", "secret_key": "secret", "type": "adminapi"}}`)
+	})
+
+	result, err := duo.CreateIntegration(IntegrationUpdate{
+		Name:                  String("Provisioning"),
+		Type:                  "adminapi",
+		GroupsAllowed:         []string{"DG1", "DG2"},
+		AdminAPIReadLog:       NewFlag(true),
+		AdminAPIWriteResource: NewFlag(false),
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from CreateIntegration call %v", err.Error())
+	}
+	if result.Response.SecretKey != "secret" {
+		t.Errorf("Expected the new secret key, but got %+v", result.Response)
+	}
+
+	if _, err := duo.CreateIntegration(IntegrationUpdate{Name: String("Provisioning")}); err == nil {
+		t.Errorf("Expected a validation error for a missing type")
+	}
+}
+
+func TestModifyIntegration(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		if r.URL.Path != "/admin/v1/integrations/DIRPNK5JHAV69IOFQ9BA" || params.Encode() != "greeting=&notes=Rotated+yearly" {
+			t.Errorf("Unexpected request %s %s", r.URL.Path, params.Encode())
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": {"integration_key": "DIRPNK5JHAV69IOFQ9BA", "notes": "Rotated yearly"}}`)
+	})
+
+	result, err := duo.ModifyIntegration("DIRPNK5JHAV69IOFQ9BA", IntegrationUpdate{
+		Notes:      String("Rotated yearly"),
+		NullFields: []string{"Greeting"},
+	})
+	if err != nil {
+		t.Errorf("Unexpected error from ModifyIntegration call %v", err.Error())
+	}
+	if result.Response.Notes != "Rotated yearly" {
+		t.Errorf("Unexpected integration %+v", result.Response)
+	}
+
+	if _, err := duo.ModifyIntegration("DIRPNK5JHAV69IOFQ9BA", IntegrationUpdate{Type: "websdk"}); err == nil {
+		t.Errorf("Expected a validation error for modifying the type")
+	}
+}
+
+func TestDeleteIntegration(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/admin/v1/integrations/DIRPNK5JHAV69IOFQ9BA" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": ""}`)
+	})
+
+	result, err := duo.DeleteIntegration("DIRPNK5JHAV69IOFQ9BA")
+	if err != nil {
+		t.Errorf("Unexpected error from DeleteIntegration call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
+func TestRotateIntegrationSecretKey(t *testing.T) {
+	var calls []string
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		params, _ := getBodyParams(r)
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+params.Encode())
+		switch r.URL.Path {
+		case "/admin/v1/integrations/DIRPNK5JHAV69IOFQ9BA":
+			// The reset response leaves the key out, as newer accounts do.
+			fmt.Fprintln(w, `{"stat": "OK", "response": {"integration_key": "DIRPNK5JHAV69IOFQ9BA"}}`)
+		case "/admin/v1/integrations/DIRPNK5JHAV69IOFQ9BA/skey":
+			fmt.Fprintln(w, `{"stat": "OK", "response": {"secret_key": "newsecret"}}`)
+		case "/admin/v1/integrations/DIMISSING":
+			fmt.Fprintln(w, `{"stat": "FAIL", "code": 40401, "message": "Resource not found"}`)
+		}
+	})
+
+	key, err := duo.RotateIntegrationSecretKey("DIRPNK5JHAV69IOFQ9BA")
+	if err != nil {
+		t.Errorf("Unexpected error from RotateIntegrationSecretKey call %v", err.Error())
+	}
+	if key != "newsecret" {
+		t.Errorf("Expected the new secret key, but got %q", key)
+	}
+	want := []string{
+		"POST /admin/v1/integrations/DIRPNK5JHAV69IOFQ9BA reset_secret_key=1",
+		"GET /admin/v1/integrations/DIRPNK5JHAV69IOFQ9BA/skey ",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected calls %v, got %v", want, calls)
+	}
+
+	if _, err := duo.RotateIntegrationSecretKey("DIMISSING"); err == nil {
+		t.Errorf("Expected an error rotating a missing integration")
+	} else if serr, ok := err.(*StatError); !ok || *serr.Code != 40401 {
+		t.Errorf("Expected a *StatError, but got %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package admin
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// StatError reports a FAIL response to one of the requests made by an
+// iterator or by concurrent paging.
+type StatError struct {
+	duoapi.StatResult
+}
+
+func (e *StatError) Error() string {
+	msg := "duo: request failed"
+	if e.Code != nil {
+		msg += fmt.Sprintf(" with code %d", *e.Code)
+	}
+	if e.Message != nil {
+		msg += ": " + *e.Message
+	}
+	if e.Message_Detail != nil {
+		msg += " (" + *e.Message_Detail + ")"
+	}
+	return msg
+}
+
+// checkStat returns a *StatError unless result is OK.
+func checkStat(result duoapi.StatResult) error {
+	if result.Stat != "OK" {
+		return &StatError{result}
+	}
+	return nil
+}
+
+// listIterator fetches pages of a list endpoint on demand and steps through
+// their items.  It is embedded in the typed iterators, which add Item.
+//
+// Unlike the Get methods, an iterator fetches every page even when a limit
+// option is given; the limit sets the page size instead.
+type listIterator struct {
+	fetch  pageFetcher
+	params url.Values
+
+	page   responsePage
+	offset uint64 // offset of the first item of page
+	index  int
+	length int
+	done   bool
+	err    error
+}
+
+func newListIterator(options []func(*url.Values), fetch pageFetcher) listIterator {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+	if params.Get("limit") == "" {
+		params.Set("limit", "100")
+	}
+
+	it := listIterator{fetch: fetch, params: params}
+	if s := params.Get("offset"); s != "" {
+		offset, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			it.err = fmt.Errorf("invalid offset %q: %v", s, err)
+		}
+		it.offset = offset
+	}
+	return it
+}
+
+// Next advances to the next item, fetching the next page when the current
+// one is used up.  It returns false when there are no more items or a
+// request fails, after which Err reports the failure.
+func (it *listIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if it.page != nil && it.index+1 < it.length {
+		it.index++
+		return true
+	}
+
+	for {
+		offset := it.offset
+		if it.page != nil {
+			next := it.page.metadata().NextOffset.String()
+			if next == "" {
+				it.done = true
+				return false
+			}
+			n, err := strconv.ParseUint(next, 10, 64)
+			if err != nil {
+				it.err = fmt.Errorf("invalid next_offset %q: %v", next, err)
+				return false
+			}
+			if n <= it.offset {
+				it.err = fmt.Errorf("next_offset %d does not advance past offset %d", n, it.offset)
+				return false
+			}
+			offset = n
+		}
+
+		it.params.Set("offset", strconv.FormatUint(offset, 10))
+		page, err := it.fetch(it.params)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page
+		it.offset = offset
+		it.index = 0
+		it.length = reflect.ValueOf(page.getResponse()).Len()
+		if it.length > 0 {
+			return true
+		}
+	}
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *listIterator) Err() error {
+	return it.err
+}
+
+// Cursor returns the offset of the item after the current one.  Pass it to
+// the Offset option to resume an interrupted iteration with a new iterator.
+func (it *listIterator) Cursor() uint64 {
+	if it.length == 0 {
+		return it.offset
+	}
+	return it.offset + uint64(it.index) + 1
+}
+
+// UserIterator steps through users in API order, fetching a page at a time.
+// Call Next before each call to Item:
+//
+//	it := client.Users()
+//	for it.Next() {
+//		user := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		// Resume later with client.Users(admin.Offset(it.Cursor())).
+//	}
+type UserIterator struct {
+	listIterator
+}
+
+// Users returns an iterator over the users selected by options, which are
+// those accepted by GetUsers.
+func (c *Client) Users(options ...func(*url.Values)) *UserIterator {
+	return NewUserIterator(c.retrieveUsers, options...)
+}
+
+// NewUserIterator returns an iterator over the user pages returned by fetch,
+// which is called with the parameters of each page in turn.  API
+// implementations other than Client, such as fakes.Admin, use it to provide
+// Users.
+func NewUserIterator(fetch func(url.Values) (*GetUsersResult, error), options ...func(*url.Values)) *UserIterator {
+	return &UserIterator{newListIterator(options, func(params url.Values) (responsePage, error) {
+		result, err := fetch(params)
+		if err != nil {
+			return nil, err
+		}
+		return result, checkStat(result.StatResult)
+	})}
+}
+
+// Item returns the current user.
+func (it *UserIterator) Item() User {
+	return it.page.(*GetUsersResult).Response[it.index]
+}
+
+// GroupIterator steps through groups in API order, fetching a page at a
+// time.  It is used like UserIterator.
+type GroupIterator struct {
+	listIterator
+}
+
+// Groups returns an iterator over the groups selected by options.
+func (c *Client) Groups(options ...func(*url.Values)) *GroupIterator {
+	return NewGroupIterator(c.retrieveGroups, options...)
+}
+
+// NewGroupIterator returns an iterator over the group pages returned by fetch,
+// like NewUserIterator.
+func NewGroupIterator(fetch func(url.Values) (*GetGroupsResult, error), options ...func(*url.Values)) *GroupIterator {
+	return &GroupIterator{newListIterator(options, func(params url.Values) (responsePage, error) {
+		result, err := fetch(params)
+		if err != nil {
+			return nil, err
+		}
+		return result, checkStat(result.StatResult)
+	})}
+}
+
+// Item returns the current group.
+func (it *GroupIterator) Item() Group {
+	return it.page.(*GetGroupsResult).Response[it.index]
+}
+
+// PhoneIterator steps through phones in API order, fetching a page at a
+// time.  It is used like UserIterator.
+type PhoneIterator struct {
+	listIterator
+}
+
+// Phones returns an iterator over the phones selected by options, which are
+// those accepted by GetPhones.
+func (c *Client) Phones(options ...func(*url.Values)) *PhoneIterator {
+	return NewPhoneIterator(c.retrievePhones, options...)
+}
+
+// NewPhoneIterator returns an iterator over the phone pages returned by fetch,
+// like NewUserIterator.
+func NewPhoneIterator(fetch func(url.Values) (*GetPhonesResult, error), options ...func(*url.Values)) *PhoneIterator {
+	return &PhoneIterator{newListIterator(options, func(params url.Values) (responsePage, error) {
+		result, err := fetch(params)
+		if err != nil {
+			return nil, err
+		}
+		return result, checkStat(result.StatResult)
+	})}
+}
+
+// Item returns the current phone.
+func (it *PhoneIterator) Item() Phone {
+	return it.page.(*GetPhonesResult).Response[it.index]
+}
+
+// TokenIterator steps through hardware tokens in API order, fetching a page
+// at a time.  It is used like UserIterator.
+type TokenIterator struct {
+	listIterator
+}
+
+// Tokens returns an iterator over the tokens selected by options, which are
+// those accepted by GetTokens.
+func (c *Client) Tokens(options ...func(*url.Values)) *TokenIterator {
+	return NewTokenIterator(c.retrieveTokens, options...)
+}
+
+// NewTokenIterator returns an iterator over the token pages returned by fetch,
+// like NewUserIterator.
+func NewTokenIterator(fetch func(url.Values) (*GetTokensResult, error), options ...func(*url.Values)) *TokenIterator {
+	return &TokenIterator{newListIterator(options, func(params url.Values) (responsePage, error) {
+		result, err := fetch(params)
+		if err != nil {
+			return nil, err
+		}
+		return result, checkStat(result.StatResult)
+	})}
+}
+
+// Item returns the current token.
+func (it *TokenIterator) Item() Token {
+	return it.page.(*GetTokensResult).Response[it.index]
+}
+
+// U2FTokenIterator steps through U2F tokens in API order, fetching a page at
+// a time.  It is used like UserIterator.
+type U2FTokenIterator struct {
+	listIterator
+}
+
+// U2FTokens returns an iterator over the U2F tokens selected by options.
+func (c *Client) U2FTokens(options ...func(*url.Values)) *U2FTokenIterator {
+	return NewU2FTokenIterator(c.retrieveU2FTokens, options...)
+}
+
+// NewU2FTokenIterator returns an iterator over the U2F token pages returned by fetch,
+// like NewUserIterator.
+func NewU2FTokenIterator(fetch func(url.Values) (*GetU2FTokensResult, error), options ...func(*url.Values)) *U2FTokenIterator {
+	return &U2FTokenIterator{newListIterator(options, func(params url.Values) (responsePage, error) {
+		result, err := fetch(params)
+		if err != nil {
+			return nil, err
+		}
+		return result, checkStat(result.StatResult)
+	})}
+}
+
+// Item returns the current U2F token.
+func (it *U2FTokenIterator) Item() U2FToken {
+	return it.page.(*GetU2FTokensResult).Response[it.index]
+}
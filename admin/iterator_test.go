@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// pagingServer serves /admin/v1/users and /admin/v1/groups from n generated
+// items, honouring limit and offset.  Requests at failAt offset fail.
+type pagingServer struct {
+	n       int
+	failAt  int
+	offsets []int
+}
+
+func (s *pagingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	s.offsets = append(s.offsets, offset)
+	if offset == s.failAt {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintln(w, `{"stat": "FAIL", "code": 50000, "message": "Internal server error"}`)
+		return
+	}
+
+	end := offset + limit
+	if end > s.n {
+		end = s.n
+	}
+	items := []map[string]string{}
+	for i := offset; i < end; i++ {
+		switch r.URL.Path {
+		case "/admin/v1/users":
+			items = append(items, map[string]string{"user_id": fmt.Sprintf("DU%d", i), "username": fmt.Sprintf("user%d", i)})
+		case "/admin/v1/groups":
+			items = append(items, map[string]string{"group_id": fmt.Sprintf("DG%d", i), "name": fmt.Sprintf("group%d", i)})
+		}
+	}
+	var next interface{}
+	if end < s.n {
+		next = end
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"stat":     "OK",
+		"response": items,
+		"metadata": map[string]interface{}{"next_offset": next, "total_objects": s.n},
+	})
+}
+
+func TestUserIterator(t *testing.T) {
+	srv := &pagingServer{n: 7, failAt: -1}
+	duo := newTestClient(t, srv.ServeHTTP)
+
+	it := duo.Users(Limit(3))
+	var names []string
+	for it.Next() {
+		names = append(names, it.Item().Username)
+		if it.Cursor() != uint64(len(names)) {
+			t.Errorf("Expected cursor %d, got %d", len(names), it.Cursor())
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 7 {
+		t.Fatalf("Expected 7 users, got %v", names)
+	}
+	for i, name := range names {
+		if name != fmt.Sprintf("user%d", i) {
+			t.Errorf("Expected users in API order, got %v", names)
+			break
+		}
+	}
+	if fmt.Sprint(srv.offsets) != "[0 3 6]" {
+		t.Errorf("Expected pages at offsets [0 3 6], got %v", srv.offsets)
+	}
+	if it.Next() {
+		t.Error("Expected Next to keep returning false")
+	}
+}
+
+func TestGroupIteratorResume(t *testing.T) {
+	srv := &pagingServer{n: 5, failAt: 2}
+	duo := newTestClient(t, srv.ServeHTTP)
+
+	it := duo.Groups(Limit(2))
+	var names []string
+	for it.Next() {
+		names = append(names, it.Item().Name)
+	}
+	statErr, ok := it.Err().(*StatError)
+	if !ok {
+		t.Fatalf("Expected a *StatError, got %v", it.Err())
+	}
+	if statErr.Code == nil || *statErr.Code != 50000 {
+		t.Errorf("Unexpected error: %v", statErr)
+	}
+	if fmt.Sprint(names) != "[group0 group1]" {
+		t.Errorf("Expected the first page before the error, got %v", names)
+	}
+	if it.Cursor() != 2 {
+		t.Fatalf("Expected cursor 2, got %d", it.Cursor())
+	}
+
+	srv.failAt = -1
+	it = duo.Groups(Limit(2), Offset(it.Cursor()))
+	for it.Next() {
+		names = append(names, it.Item().Name)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(names) != "[group0 group1 group2 group3 group4]" {
+		t.Errorf("Expected all groups after resuming, got %v", names)
+	}
+}
+
+func TestIteratorEmpty(t *testing.T) {
+	duo := newTestClient(t, (&pagingServer{n: 0, failAt: -1}).ServeHTTP)
+
+	it := duo.Users()
+	if it.Next() {
+		t.Errorf("Expected no users, got %+v", it.Item())
+	}
+	if it.Err() != nil || it.Cursor() != 0 {
+		t.Errorf("Unexpected state: err %v, cursor %d", it.Err(), it.Cursor())
+	}
+}
+
+func TestGetUsersMultipageOrder(t *testing.T) {
+	duo := newTestClient(t, (&pagingServer{n: 250, failAt: -1}).ServeHTTP)
+
+	result, err := duo.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Response) != 250 {
+		t.Fatalf("Expected 250 users, got %d", len(result.Response))
+	}
+	for i, user := range result.Response {
+		if user.Username != fmt.Sprintf("user%d", i) {
+			t.Fatalf("Expected user%d at index %d, got %s", i, i, user.Username)
+		}
+	}
+	if result.Metadata.NextOffset != "" {
+		t.Errorf("Expected the last page's metadata, got next_offset %s", result.Metadata.NextOffset)
+	}
+}
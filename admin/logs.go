@@ -174,6 +174,11 @@ func getLogListV1NextOffset(end time.Time, timestamps ...time.Time) func(params
 		}
 	}
 
+	// A maximum timestamp of zero means there is no next timestamp to fetch
+	if max.IsZero() {
+		return nil
+	}
+
 	// Next mintime should be the maximum timestamp of the collected logs
 	next := max
 
@@ -182,11 +187,6 @@ func getLogListV1NextOffset(end time.Time, timestamps ...time.Time) func(params
 		next = next.Add(1 * time.Second)
 	}
 
-	// A next mintime of zero means there is no next timestamp to fetch
-	if next.IsZero() {
-		return nil
-	}
-
 	// Configures the mintime parameter of the next request that is the maximum timestamp of received logs (in seconds).
 	return func(params *url.Values) {
 		params.Set("mintime", fmt.Sprintf("%d", next.Unix()))
@@ -199,6 +199,17 @@ func getLogListV1NextOffset(end time.Time, timestamps ...time.Time) func(params
 type AdminLogResult struct {
 	duoapi.StatResult
 	Logs AdminLogList `json:"response"`
+
+	// fetched is when the page was requested, by the client's Clock.
+	fetched time.Time
+}
+
+// GetNextOffset returns an option that will configure a request to fetch the next page of logs, up to maxtime. A zero maxtime fetches logs up to the time this page was requested, as reported by the client's Clock. It returns nil when no more logs can be fetched.
+func (result *AdminLogResult) GetNextOffset(maxtime time.Time) func(params *url.Values) {
+	if maxtime.IsZero() {
+		maxtime = result.fetched
+	}
+	return result.Logs.GetNextOffset(maxtime)
 }
 
 // An AdminLog retrieved from https://duo.com/docs/adminapi#administrator-logs
@@ -233,7 +244,7 @@ func (logs AdminLogList) GetNextOffset(maxtime time.Time) func(params *url.Value
 	return getLogListV1NextOffset(maxtime, timestamps...)
 }
 
-// GetAdminLogs retrieves a page of admin logs with timestamps starting at mintime. It relies on the option provided by AdminLogResult.Logs.GetNextOffset() for pagination.
+// GetAdminLogs retrieves a page of admin logs with timestamps starting at mintime. It relies on the option provided by AdminLogResult.GetNextOffset() for pagination.
 // Calls GET /admin/v1/logs/administrator
 // See https://duo.com/docs/adminapi#administrator-logs
 func (c *Client) GetAdminLogs(mintime time.Time, options ...func(*url.Values)) (*AdminLogResult, error) {
@@ -252,6 +263,7 @@ func (c *Client) GetAdminLogs(mintime time.Time, options ...func(*url.Values)) (
 	}
 
 	// Retrieve page of admin logs
+	fetched := c.Clock().Now()
 	resp, body, err := c.SignedCall(
 		http.MethodGet,
 		"/admin/v1/logs/administrator",
@@ -265,7 +277,7 @@ func (c *Client) GetAdminLogs(mintime time.Time, options ...func(*url.Values)) (
 	}
 
 	// Unmarshal received JSON into expected structure
-	result := &AdminLogResult{}
+	result := &AdminLogResult{fetched: fetched}
 	if err = json.Unmarshal(body, result); err != nil {
 		return nil, err
 	}
@@ -279,6 +291,17 @@ func (c *Client) GetAdminLogs(mintime time.Time, options ...func(*url.Values)) (
 type TelephonyLogResult struct {
 	duoapi.StatResult
 	Logs TelephonyLogList `json:"response"`
+
+	// fetched is when the page was requested, by the client's Clock.
+	fetched time.Time
+}
+
+// GetNextOffset returns an option that will configure a request to fetch the next page of logs, up to maxtime. A zero maxtime fetches logs up to the time this page was requested, as reported by the client's Clock. It returns nil when no more logs can be fetched.
+func (result *TelephonyLogResult) GetNextOffset(maxtime time.Time) func(params *url.Values) {
+	if maxtime.IsZero() {
+		maxtime = result.fetched
+	}
+	return result.Logs.GetNextOffset(maxtime)
 }
 
 // A TelephonyLog retrieved from https://duo.com/docs/adminapi#telephony-logs
@@ -313,7 +336,7 @@ func (logs TelephonyLogList) GetNextOffset(maxtime time.Time) func(params *url.V
 	return getLogListV1NextOffset(maxtime, timestamps...)
 }
 
-// GetTelephonyLogs retrieves a page of telephony logs with timestamps starting at mintime. It relies on the option provided by TelephonyLogResult.Logs.GetNextOffset() for pagination.
+// GetTelephonyLogs retrieves a page of telephony logs with timestamps starting at mintime. It relies on the option provided by TelephonyLogResult.GetNextOffset() for pagination.
 // Calls GET /admin/v1/logs/telephony
 // See https://duo.com/docs/adminapi#telephony-logs
 func (c *Client) GetTelephonyLogs(mintime time.Time, options ...func(*url.Values)) (*TelephonyLogResult, error) {
@@ -332,6 +355,7 @@ func (c *Client) GetTelephonyLogs(mintime time.Time, options ...func(*url.Values
 	}
 
 	// Retrieve page of telephony logs
+	fetched := c.Clock().Now()
 	resp, body, err := c.SignedCall(
 		http.MethodGet,
 		"/admin/v1/logs/telephony",
@@ -345,7 +369,7 @@ func (c *Client) GetTelephonyLogs(mintime time.Time, options ...func(*url.Values
 	}
 
 	// Unmarshal received JSON into expected structure
-	result := &TelephonyLogResult{}
+	result := &TelephonyLogResult{fetched: fetched}
 	if err = json.Unmarshal(body, result); err != nil {
 		return nil, err
 	}
@@ -1,12 +1,16 @@
 package admin
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
 )
 
 /*
@@ -331,3 +335,85 @@ func TestTelephonyLogsNextOffset(t *testing.T) {
 		t.Errorf("Expected new mintime to be 1346172820, got: %v", newMintime)
 	}
 }
+
+// TestLogListV1NextOffsetEdgeCases covers pagination of pages at the end of the requested time range.
+func TestLogListV1NextOffsetEdgeCases(t *testing.T) {
+	end := time.Unix(1346172825, 0)
+	timestamps := make([]time.Time, maxLogV1PageSize)
+	for i := range timestamps {
+		timestamps[i] = time.Unix(1346172816+int64(i%5), 0)
+	}
+
+	params := &url.Values{}
+	getLogListV1NextOffset(end, timestamps...)(params)
+	if newMintime := params.Get("mintime"); newMintime != "1346172820" {
+		t.Errorf("Expected new mintime to be 1346172820, got: %v", newMintime)
+	}
+
+	// A page extending past the end of the range is the last one
+	timestamps[0] = end.Add(time.Second)
+	if next := getLogListV1NextOffset(end, timestamps...); next != nil {
+		t.Errorf("Expected no next page available, got non-nil option")
+	}
+
+	// A page of zero timestamps has no next page
+	zeros := make([]time.Time, maxLogV1PageSize)
+	if next := getLogListV1NextOffset(end, zeros...); next != nil {
+		t.Errorf("Expected no next page available, got non-nil option")
+	}
+}
+
+// TestLogsV1NextOffsetDefaultsToClock ensures that pagination of V1 logs without a maxtime stops at the time reported by the client's Clock.
+func TestLogsV1NextOffsetDefaultsToClock(t *testing.T) {
+	logs := make([]map[string]interface{}, maxLogV1PageSize)
+	for i := range logs {
+		logs[i] = map[string]interface{}{"timestamp": 1346172816 + i%5}
+	}
+	page, err := json.Marshal(map[string]interface{}{"stat": "OK", "response": logs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(page)
+		}),
+	)
+	defer ts.Close()
+
+	clock := &fakeClock{now: time.Unix(1346172830, 0)}
+	host := strings.Split(ts.URL, "//")[1]
+	base := duoapi.NewDuoApi("eyekey", "esskey", host, "GoTestClient", duoapi.SetInsecure(), duoapi.SetClock(clock))
+	duo := New(*base)
+
+	adminLogs, err := duo.GetAdminLogs(time.Unix(1346172815, 0))
+	if err != nil {
+		t.Fatalf("Unexpected error from GetAdminLogs call: %v", err)
+	}
+	telephonyLogs, err := duo.GetTelephonyLogs(time.Unix(1346172815, 0))
+	if err != nil {
+		t.Fatalf("Unexpected error from GetTelephonyLogs call: %v", err)
+	}
+	for _, next := range []func(*url.Values){adminLogs.GetNextOffset(time.Time{}), telephonyLogs.GetNextOffset(time.Time{})} {
+		if next == nil {
+			t.Fatalf("Expected an option to fetch the next page, got nil")
+		}
+		params := &url.Values{}
+		next(params)
+		if newMintime := params.Get("mintime"); newMintime != "1346172820" {
+			t.Errorf("Expected new mintime to be 1346172820, got: %v", newMintime)
+		}
+	}
+
+	// Logs after the time of the request are past the end of the range
+	clock.now = time.Unix(1346172818, 0)
+	adminLogs, err = duo.GetAdminLogs(time.Unix(1346172815, 0))
+	if err != nil {
+		t.Fatalf("Unexpected error from GetAdminLogs call: %v", err)
+	}
+	if next := adminLogs.GetNextOffset(time.Time{}); next != nil {
+		t.Errorf("Expected no next page available, got non-nil option")
+	}
+	if next := adminLogs.GetNextOffset(time.Unix(1346172825, 0)); next == nil {
+		t.Errorf("Expected an explicit maxtime to override the clock, got nil")
+	}
+}
@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// maxLookupListSize is the most identifiers Duo accepts in username_list or
+// user_id_list.
+const maxLookupListSize = 100
+
+// GetUsersEmail sets the optional email parameter for a GetUsers request.
+func GetUsersEmail(email string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("email", email)
+	}
+}
+
+// GetUsersUsernameList sets the optional username_list parameter for a
+// GetUsers request.  Duo accepts at most 100 usernames or aliases; use
+// GetUsersByUsernames for longer lists.
+func GetUsersUsernameList(usernames ...string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("username_list", jsonList(usernames))
+	}
+}
+
+// GetUsersUserIDList sets the optional user_id_list parameter for a GetUsers
+// request.  Duo accepts at most 100 user IDs; use GetUsersByIDs for longer
+// lists.
+func GetUsersUserIDList(userIDs ...string) func(*url.Values) {
+	return func(opts *url.Values) {
+		opts.Set("user_id_list", jsonList(userIDs))
+	}
+}
+
+func jsonList(items []string) string {
+	if items == nil {
+		items = []string{}
+	}
+	list, _ := json.Marshal(items)
+	return string(list)
+}
+
+// UserLookupResult holds the users found by a batch lookup.
+type UserLookupResult struct {
+	// Users maps each identifier which was found to its user.
+	Users map[string]User
+	// NotFound lists the identifiers which matched no user, in the order
+	// they were requested.
+	NotFound []string
+	// Ambiguous lists the email addresses which matched more than one
+	// user.  They are in neither Users nor NotFound.
+	Ambiguous []string
+}
+
+// GetUsersByUsernames looks up users by username or alias, sending 100
+// names per request.  The result is keyed by the requested names.
+func (c *Client) GetUsersByUsernames(usernames []string) (*UserLookupResult, error) {
+	return c.lookupUsers(usernames, GetUsersUsernameList, func(user User) []string {
+		names := []string{user.Username}
+		for _, alias := range []*string{user.Alias1, user.Alias2, user.Alias3, user.Alias4} {
+			if alias != nil {
+				names = append(names, *alias)
+			}
+		}
+		return names
+	})
+}
+
+// GetUsersByIDs looks up users by user ID, sending 100 IDs per request.  The
+// result is keyed by the requested IDs.
+func (c *Client) GetUsersByIDs(userIDs []string) (*UserLookupResult, error) {
+	return c.lookupUsers(userIDs, GetUsersUserIDList, func(user User) []string {
+		return []string{user.UserID}
+	})
+}
+
+// GetUsersByEmails looks up users by email address.  Duo has no list filter
+// for email addresses, so one request is sent per address.  The result is
+// keyed by the requested addresses.
+func (c *Client) GetUsersByEmails(emails []string) (*UserLookupResult, error) {
+	lookup := &UserLookupResult{Users: make(map[string]User)}
+	for _, email := range uniqueStrings(emails) {
+		result, err := c.GetUsers(GetUsersEmail(email))
+		if err != nil {
+			return nil, err
+		}
+		if err = checkStat(result.StatResult); err != nil {
+			return nil, err
+		}
+		switch len(result.Response) {
+		case 0:
+			lookup.NotFound = append(lookup.NotFound, email)
+		case 1:
+			lookup.Users[email] = result.Response[0]
+		default:
+			lookup.Ambiguous = append(lookup.Ambiguous, email)
+		}
+	}
+	return lookup, nil
+}
+
+// lookupUsers fetches the users matching ids in chunks using a list filter,
+// and keys them by the requested identifiers matching keys(user).
+func (c *Client) lookupUsers(
+	ids []string,
+	filter func(...string) func(*url.Values),
+	keys func(User) []string,
+) (*UserLookupResult, error) {
+	ids = uniqueStrings(ids)
+	lookup := &UserLookupResult{Users: make(map[string]User)}
+
+	for start := 0; start < len(ids); start += maxLookupListSize {
+		end := start + maxLookupListSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+
+		result, err := c.GetUsers(filter(chunk...))
+		if err != nil {
+			return nil, err
+		}
+		if err = checkStat(result.StatResult); err != nil {
+			return nil, err
+		}
+		for _, user := range result.Response {
+			for _, key := range keys(user) {
+				for _, id := range chunk {
+					if strings.EqualFold(id, key) {
+						lookup.Users[id] = user
+					}
+				}
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if _, ok := lookup.Users[id]; !ok {
+			lookup.NotFound = append(lookup.NotFound, id)
+		}
+	}
+	return lookup, nil
+}
+
+// uniqueStrings returns items without duplicates, in their original order.
+func uniqueStrings(items []string) []string {
+	seen := make(map[string]bool, len(items))
+	var unique []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			unique = append(unique, item)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,119 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// lookupServer serves /admin/v1/users filtered by username_list,
+// user_id_list or email from users jsmith0 to jsmith{n-1}.  Every user has
+// an alias, and users 0 and 1 share an email address.
+type lookupServer struct {
+	n        int
+	requests []string
+}
+
+func (s *lookupServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var names, ids []string
+	json.Unmarshal([]byte(query.Get("username_list")), &names)
+	json.Unmarshal([]byte(query.Get("user_id_list")), &ids)
+	email := query.Get("email")
+	if len(names) > 100 || len(ids) > 100 {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"stat": "FAIL", "code": 40002, "message": "Invalid request parameters", "message_detail": "username_list"}`)
+		return
+	}
+	s.requests = append(s.requests, query.Encode())
+
+	users := []map[string]interface{}{}
+	for i := 0; i < s.n; i++ {
+		user := map[string]interface{}{
+			"user_id":  fmt.Sprintf("DU%d", i),
+			"username": fmt.Sprintf("jsmith%d", i),
+			"alias1":   fmt.Sprintf("joe.smith%d", i),
+			"email":    fmt.Sprintf("jsmith%d@example.com", i),
+		}
+		if i < 2 {
+			user["email"] = "shared@example.com"
+		}
+		match := false
+		for _, name := range names {
+			match = match || strings.EqualFold(name, user["username"].(string)) || strings.EqualFold(name, user["alias1"].(string))
+		}
+		for _, id := range ids {
+			match = match || id == user["user_id"]
+		}
+		match = match || (email != "" && strings.EqualFold(email, user["email"].(string)))
+		if match {
+			users = append(users, user)
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"stat":     "OK",
+		"response": users,
+		"metadata": map[string]interface{}{"total_objects": len(users)},
+	})
+}
+
+func TestGetUsersByUsernames(t *testing.T) {
+	srv := &lookupServer{n: 300}
+	duo := newTestClient(t, srv.ServeHTTP)
+
+	var usernames []string
+	for i := 0; i < 250; i++ {
+		usernames = append(usernames, fmt.Sprintf("jsmith%d", i))
+	}
+	usernames = append(usernames, "JOE.SMITH299", "nobody", "jsmith0", "ghost")
+
+	result, err := duo.GetUsersByUsernames(usernames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.requests) != 3 {
+		t.Errorf("Expected 3 chunked requests, got %d", len(srv.requests))
+	}
+	if len(result.Users) != 251 {
+		t.Errorf("Expected 251 users, got %d", len(result.Users))
+	}
+	if result.Users["jsmith42"].UserID != "DU42" {
+		t.Errorf("Unexpected user for jsmith42: %+v", result.Users["jsmith42"])
+	}
+	if result.Users["JOE.SMITH299"].UserID != "DU299" {
+		t.Errorf("Expected the alias to be matched, got %+v", result.Users["JOE.SMITH299"])
+	}
+	if !reflect.DeepEqual(result.NotFound, []string{"nobody", "ghost"}) {
+		t.Errorf("Unexpected not found list %v", result.NotFound)
+	}
+}
+
+func TestGetUsersByIDsAndEmails(t *testing.T) {
+	srv := &lookupServer{n: 5}
+	duo := newTestClient(t, srv.ServeHTTP)
+
+	byID, err := duo.GetUsersByIDs([]string{"DU3", "DU9", "DU4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byID.Users) != 2 || byID.Users["DU3"].Username != "jsmith3" || !reflect.DeepEqual(byID.NotFound, []string{"DU9"}) {
+		t.Errorf("Unexpected result %+v", byID)
+	}
+	if srv.requests[0] != `limit=100&offset=0&user_id_list=%5B%22DU3%22%2C%22DU9%22%2C%22DU4%22%5D` {
+		t.Errorf("Unexpected request %s", srv.requests[0])
+	}
+
+	byEmail, err := duo.GetUsersByEmails([]string{"jsmith4@example.com", "shared@example.com", "nobody@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byEmail.Users) != 1 || byEmail.Users["jsmith4@example.com"].UserID != "DU4" {
+		t.Errorf("Unexpected users %+v", byEmail.Users)
+	}
+	if !reflect.DeepEqual(byEmail.Ambiguous, []string{"shared@example.com"}) || !reflect.DeepEqual(byEmail.NotFound, []string{"nobody@example.com"}) {
+		t.Errorf("Unexpected result %+v", byEmail)
+	}
+}
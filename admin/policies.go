@@ -0,0 +1,268 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// Policy models a Duo policy.  The global policy applies to every
+// application; other policies apply to the applications and groups they are
+// assigned to, and override the global policy's settings section by section.
+type Policy struct {
+	PolicyKey      string `json:"policy_key"`
+	PolicyName     string `json:"policy_name"`
+	IsGlobalPolicy bool   `json:"is_global_policy"`
+	// Sections maps section names, such as "authentication_methods", to the
+	// settings the policy sets.
+	Sections map[string]PolicySection
+}
+
+func (p Policy) id() string {
+	return p.PolicyKey
+}
+
+// PolicySection holds the settings of a policy section by name, as decoded
+// by encoding/json.
+type PolicySection map[string]interface{}
+
+// PolicyUpdate holds the fields of a policy to create or update.  Nil and
+// empty fields are left unchanged.
+type PolicyUpdate struct {
+	PolicyName          *string             `json:"policy_name,omitempty"`
+	ApplyToApps         *PolicyApps         `json:"apply_to_apps,omitempty"`
+	ApplyToGroupsInApps *PolicyGroupsInApps `json:"apply_to_groups_in_apps,omitempty"`
+	// Sections are merged into the policy's sections, setting only the
+	// settings given.
+	Sections map[string]PolicySection `json:"sections,omitempty"`
+	// SectionsToDelete may only be set by UpdatePolicy.  It names sections
+	// to remove, so that the global policy's settings apply instead.
+	SectionsToDelete []string `json:"sections_to_delete,omitempty"`
+}
+
+// PolicyApps assigns a policy to, or removes it from, applications.
+type PolicyApps struct {
+	AffectedIntegrationKeys   []string `json:"affected_app_integration_keys,omitempty"`
+	UnaffectedIntegrationKeys []string `json:"unaffected_app_integration_keys,omitempty"`
+}
+
+// PolicyGroupsInApps assigns a policy to, or removes it from, groups of
+// users of applications.
+type PolicyGroupsInApps struct {
+	Apply   []PolicyGroupsInApp `json:"apply_group_policies_list,omitempty"`
+	Unapply []PolicyGroupsInApp `json:"unapply_group_policies_list,omitempty"`
+}
+
+// PolicyGroupsInApp names groups of users of the application with
+// IntegrationKey.
+type PolicyGroupsInApp struct {
+	IntegrationKey string   `json:"app_integration_key"`
+	GroupIDs       []string `json:"group_id_list"`
+}
+
+// GetPoliciesResult models responses containing a list of policies.
+type GetPoliciesResult struct {
+	duoapi.StatResult
+	ListResult
+	Response []Policy
+}
+
+func (result *GetPoliciesResult) getResponse() interface{} {
+	return result.Response
+}
+
+func (result *GetPoliciesResult) appendResponse(policies interface{}) {
+	asserted_policies := policies.([]Policy)
+	result.Response = append(result.Response, asserted_policies...)
+}
+
+// GetPolicies calls GET /admin/v2/policies
+// See https://duo.com/docs/adminapi#retrieve-policies
+func (c *Client) GetPolicies(options ...func(*url.Values)) (*GetPoliciesResult, error) {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+
+	cb := func(params url.Values) (responsePage, error) {
+		return c.retrievePolicies(params)
+	}
+	response, err := c.retrieveItems(params, cb)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*GetPoliciesResult), nil
+}
+
+func (c *Client) retrievePolicies(params url.Values) (*GetPoliciesResult, error) {
+	_, body, err := c.JSONSignedCall(http.MethodGet, "/admin/v2/policies", params, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetPoliciesResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetPolicyResult models responses containing a single policy.
+type GetPolicyResult struct {
+	duoapi.StatResult
+	Response Policy
+}
+
+// GetPolicy calls GET /admin/v2/policies/:policy_key
+// See https://duo.com/docs/adminapi#retrieve-policy-by-key
+func (c *Client) GetPolicy(policyKey string) (*GetPolicyResult, error) {
+	path := fmt.Sprintf("/admin/v2/policies/%s", policyKey)
+	return c.policyCall(http.MethodGet, path, nil)
+}
+
+// GetGlobalPolicy calls GET /admin/v2/policies/global
+// See https://duo.com/docs/adminapi#retrieve-global-policy
+func (c *Client) GetGlobalPolicy() (*GetPolicyResult, error) {
+	return c.policyCall(http.MethodGet, "/admin/v2/policies/global", nil)
+}
+
+// CreatePolicy calls POST /admin/v2/policies
+// See https://duo.com/docs/adminapi#create-policy
+//
+// PolicyName is required.
+func (c *Client) CreatePolicy(policy PolicyUpdate) (*GetPolicyResult, error) {
+	v := &validator{}
+	if policy.PolicyName == nil {
+		v.required("policy_name", "")
+	}
+	if len(policy.SectionsToDelete) > 0 {
+		v.add("sections_to_delete", "", "applies only to existing policies")
+	}
+	policy.validate(v)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return c.policyCall(http.MethodPost, "/admin/v2/policies", policy)
+}
+
+// UpdatePolicy calls PUT /admin/v2/policies/:policy_key
+// See https://duo.com/docs/adminapi#update-policy
+func (c *Client) UpdatePolicy(policyKey string, policy PolicyUpdate) (*GetPolicyResult, error) {
+	path := fmt.Sprintf("/admin/v2/policies/%s", policyKey)
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.policyCall(http.MethodPut, path, policy)
+}
+
+func (c *Client) policyCall(method, path string, policy interface{}) (*GetPolicyResult, error) {
+	_, body, err := c.JSONSignedCall(method, path, nil, policy, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetPolicyResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CopyPolicyResult models responses containing the copies of a policy.
+type CopyPolicyResult struct {
+	duoapi.StatResult
+	Response []Policy
+}
+
+// CopyPolicy calls POST /admin/v2/policies/copy
+// See https://duo.com/docs/adminapi#copy-policy
+//
+// It makes a copy of the policy with policyKey for each of newNames, or a
+// single copy named by Duo if newNames is empty.  Copies are not assigned to
+// any applications or groups.
+func (c *Client) CopyPolicy(policyKey string, newNames []string) (*CopyPolicyResult, error) {
+	request := struct {
+		PolicyKey string   `json:"policy_key"`
+		NewNames  []string `json:"new_policy_names_list,omitempty"`
+	}{policyKey, newNames}
+
+	_, body, err := c.JSONSignedCall(http.MethodPost, "/admin/v2/policies/copy", nil, request, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CopyPolicyResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeletePolicy calls DELETE /admin/v2/policies/:policy_key
+// See https://duo.com/docs/adminapi#delete-policy
+//
+// The global policy cannot be deleted.
+func (c *Client) DeletePolicy(policyKey string) (*duoapi.StatResult, error) {
+	path := fmt.Sprintf("/admin/v2/policies/%s", policyKey)
+
+	_, body, err := c.JSONSignedCall(http.MethodDelete, path, nil, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &duoapi.StatResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Effective policies
+
+// EffectivePolicy models the policy Duo applies when a user authenticates to
+// an application.  It merges the global policy, the application's policy and
+// the policy of the user's groups in the application, each replacing whole
+// sections of those before it.
+type EffectivePolicy struct {
+	Sections map[string]PolicySection
+}
+
+// EffectivePolicyResult models responses containing an effective policy.
+type EffectivePolicyResult struct {
+	duoapi.StatResult
+	Response EffectivePolicy
+}
+
+// CalculatePolicy calls GET /admin/v2/policies/calculate
+// See https://duo.com/docs/adminapi#calculate-policy
+//
+// It returns the policy applied to the user with userID when authenticating
+// to the integration with integrationKey.
+func (c *Client) CalculatePolicy(integrationKey, userID string) (*EffectivePolicyResult, error) {
+	params := url.Values{}
+	params.Set("integration_key", integrationKey)
+	params.Set("user_id", userID)
+
+	_, body, err := c.JSONSignedCall(http.MethodGet, "/admin/v2/policies/calculate", params, nil, duoapi.UseTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EffectivePolicyResult{}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
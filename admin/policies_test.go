@@ -0,0 +1,269 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const getPoliciesPage1Response = `{
+	"stat": "OK",
+	"response": [{
+		"is_global_policy": true,
+		"policy_key": "POSTGSOOGS7LBN5KWKGX",
+		"policy_name": "Global Policy",
+		"sections": {
+			"authentication_methods": {
+				"allowed_auth_list": ["duo-push", "webauthn-roaming"],
+				"blocked_auth_list": ["sms"]
+			},
+			"new_user_policy": {"new_user_behavior": "enroll"}
+		}
+	}],
+	"metadata": {
+		"prev_offset": null,
+		"next_offset": 1,
+		"total_objects": 2
+	}
+}`
+
+const getPoliciesPage2Response = `{
+	"stat": "OK",
+	"response": [{
+		"is_global_policy": false,
+		"policy_key": "POVNQ1I4Y2UDRKBSSO6W",
+		"policy_name": "Contractors",
+		"sections": {
+			"new_user_policy": {"new_user_behavior": "deny"}
+		}
+	}],
+	"metadata": {
+		"prev_offset": 0,
+		"next_offset": null,
+		"total_objects": 2
+	}
+}`
+
+func TestGetPoliciesMultiple(t *testing.T) {
+	requests := []*http.Request{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if len(requests) == 0 {
+			fmt.Fprintln(w, getPoliciesPage1Response)
+		} else {
+			fmt.Fprintln(w, getPoliciesPage2Response)
+		}
+		requests = append(requests, r)
+	})
+
+	result, err := duo.GetPolicies()
+	if err != nil {
+		t.Errorf("Unexpected error from GetPolicies call %v", err.Error())
+	}
+	if len(requests) != 2 {
+		t.Errorf("Expected two requests, found %d", len(requests))
+	}
+	if requests[1].URL.Query().Get("offset") != "1" || requests[1].Header.Get("X-Duo-Date") == "" {
+		t.Errorf("Unexpected second request %s", requests[1].URL)
+	}
+	if len(result.Response) != 2 {
+		t.Fatalf("Expected two policies in the response, found %d", len(result.Response))
+	}
+	global := result.Response[0]
+	if !global.IsGlobalPolicy || global.PolicyName != "Global Policy" {
+		t.Errorf("Unexpected global policy %+v", global)
+	}
+	methods := global.Sections["authentication_methods"]
+	if !reflect.DeepEqual(methods["blocked_auth_list"], []interface{}{"sms"}) {
+		t.Errorf("Unexpected authentication methods %+v", methods)
+	}
+	if behavior := result.Response[1].Sections["new_user_policy"]["new_user_behavior"]; behavior != "deny" {
+		t.Errorf("Unexpected new user behavior %v", behavior)
+	}
+}
+
+const createPolicyResponse = `{
+	"stat": "OK",
+	"response": {
+		"is_global_policy": false,
+		"policy_key": "POVNQ1I4Y2UDRKBSSO6W",
+		"policy_name": "Contractors",
+		"sections": {
+			"new_user_policy": {"new_user_behavior": "deny"}
+		}
+	}
+}`
+
+func TestCreatePolicy(t *testing.T) {
+	var body map[string]interface{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/v2/policies" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Unexpected Content-Type %q", ct)
+		}
+		raw, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("Invalid JSON body %q", raw)
+		}
+		fmt.Fprintln(w, createPolicyResponse)
+	})
+
+	result, err := duo.CreatePolicy(PolicyUpdate{
+		PolicyName:  String("Contractors"),
+		ApplyToApps: &PolicyApps{AffectedIntegrationKeys: []string{"DIRPNK5JHAV69IOFQ9BA"}},
+		ApplyToGroupsInApps: &PolicyGroupsInApps{
+			Apply: []PolicyGroupsInApp{{IntegrationKey: "DIWHCQ6KZFXEH5Z3X1G5", GroupIDs: []string{"DGAJ2U6NTO0ZX2W4SB4O"}}},
+		},
+		Sections: map[string]PolicySection{
+			"new_user_policy": {"new_user_behavior": "deny"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error from CreatePolicy call %v", err.Error())
+	}
+	if result.Stat != "OK" || result.Response.PolicyKey != "POVNQ1I4Y2UDRKBSSO6W" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	want := map[string]interface{}{
+		"policy_name":   "Contractors",
+		"apply_to_apps": map[string]interface{}{"affected_app_integration_keys": []interface{}{"DIRPNK5JHAV69IOFQ9BA"}},
+		"apply_to_groups_in_apps": map[string]interface{}{
+			"apply_group_policies_list": []interface{}{map[string]interface{}{
+				"app_integration_key": "DIWHCQ6KZFXEH5Z3X1G5",
+				"group_id_list":       []interface{}{"DGAJ2U6NTO0ZX2W4SB4O"},
+			}},
+		},
+		"sections": map[string]interface{}{
+			"new_user_policy": map[string]interface{}{"new_user_behavior": "deny"},
+		},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("Expected body %v, got %v", want, body)
+	}
+}
+
+func TestPolicyValidation(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request %s", r.URL.Path)
+	})
+
+	_, err := duo.CreatePolicy(PolicyUpdate{SectionsToDelete: []string{"browsers"}})
+	verr, ok := err.(*ValidationError)
+	if !ok || len(verr.Fields) != 2 || verr.Fields[0].Param != "policy_name" || verr.Fields[1].Param != "sections_to_delete" {
+		t.Errorf("Expected policy_name and sections_to_delete errors, got %v", err)
+	}
+	_, err = duo.UpdatePolicy("POVNQ1I4Y2UDRKBSSO6W", PolicyUpdate{
+		PolicyName:          String(""),
+		ApplyToGroupsInApps: &PolicyGroupsInApps{Unapply: []PolicyGroupsInApp{{GroupIDs: []string{"DGAJ2U6NTO0ZX2W4SB4O"}}}},
+	})
+	if verr, ok := err.(*ValidationError); !ok || len(verr.Fields) != 2 {
+		t.Errorf("Expected two validation errors, got %v", err)
+	}
+}
+
+func TestUpdatePolicy(t *testing.T) {
+	var body map[string]interface{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/admin/v2/policies/POVNQ1I4Y2UDRKBSSO6W" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprintln(w, createPolicyResponse)
+	})
+
+	_, err := duo.UpdatePolicy("POVNQ1I4Y2UDRKBSSO6W", PolicyUpdate{SectionsToDelete: []string{"browsers"}})
+	if err != nil {
+		t.Fatalf("Unexpected error from UpdatePolicy call %v", err.Error())
+	}
+	want := map[string]interface{}{"sections_to_delete": []interface{}{"browsers"}}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("Expected body %v, got %v", want, body)
+	}
+}
+
+const copyPolicyResponse = `{
+	"stat": "OK",
+	"response": [{
+		"is_global_policy": false,
+		"policy_key": "POA8F5MW5SXUU7QVVJ7V",
+		"policy_name": "Contractors (EMEA)",
+		"sections": {}
+	}]
+}`
+
+func TestCopyPolicy(t *testing.T) {
+	var body map[string]interface{}
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/v2/policies/copy" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprintln(w, copyPolicyResponse)
+	})
+
+	result, err := duo.CopyPolicy("POVNQ1I4Y2UDRKBSSO6W", []string{"Contractors (EMEA)"})
+	if err != nil {
+		t.Fatalf("Unexpected error from CopyPolicy call %v", err.Error())
+	}
+	if len(result.Response) != 1 || result.Response[0].PolicyName != "Contractors (EMEA)" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	want := map[string]interface{}{
+		"policy_key":            "POVNQ1I4Y2UDRKBSSO6W",
+		"new_policy_names_list": []interface{}{"Contractors (EMEA)"},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("Expected body %v, got %v", want, body)
+	}
+}
+
+func TestDeletePolicy(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/admin/v2/policies/POVNQ1I4Y2UDRKBSSO6W" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"stat": "OK", "response": ""}`)
+	})
+
+	result, err := duo.DeletePolicy("POVNQ1I4Y2UDRKBSSO6W")
+	if err != nil {
+		t.Errorf("Unexpected error from DeletePolicy call %v", err.Error())
+	}
+	if result.Stat != "OK" {
+		t.Errorf("Expected OK, but got %s", result.Stat)
+	}
+}
+
+const calculatePolicyResponse = `{
+	"stat": "OK",
+	"response": {
+		"sections": {
+			"authentication_methods": {"allowed_auth_list": ["duo-push"]},
+			"new_user_policy": {"new_user_behavior": "deny"}
+		}
+	}
+}`
+
+func TestCalculatePolicy(t *testing.T) {
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/admin/v2/policies/calculate" ||
+			query.Get("integration_key") != "DIRPNK5JHAV69IOFQ9BA" || query.Get("user_id") != "DU3RP9I2WOC59VZX672N" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		fmt.Fprintln(w, calculatePolicyResponse)
+	})
+
+	result, err := duo.CalculatePolicy("DIRPNK5JHAV69IOFQ9BA", "DU3RP9I2WOC59VZX672N")
+	if err != nil {
+		t.Fatalf("Unexpected error from CalculatePolicy call %v", err.Error())
+	}
+	if behavior := result.Response.Sections["new_user_policy"]["new_user_behavior"]; behavior != "deny" {
+		t.Errorf("Unexpected effective policy %+v", result.Response)
+	}
+}
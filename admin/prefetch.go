@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// SetPageConcurrency makes GetUsers, GetGroups, GetPhones, GetTokens,
+// GetU2FTokens, GetWebAuthnCredentials and the per-user list methods fetch
+// up to workers pages at a time when they retrieve a whole list.  The
+// offsets of the remaining pages are computed from the first page's
+// total_objects.  Each request still backs off when rate limited, so keep
+// workers small; 1 or less restores sequential paging, the default.
+//
+// Pages are merged in API order.  An item which moves to a later page while
+// the pages are fetched, because others were added before it, is returned
+// only once.  As with sequential paging, an item which moves to an earlier,
+// already fetched page may be missed.
+func (c *Client) SetPageConcurrency(workers int) {
+	c.pageConcurrency = workers
+}
+
+// prefetchItems fetches the pages following first concurrently and merges
+// them into first.
+func (c *Client) prefetchItems(params url.Values, fetcher pageFetcher, first responsePage) (responsePage, error) {
+	var offsets []int
+	next, nextErr := strconv.Atoi(first.metadata().NextOffset.String())
+	total, totalErr := strconv.Atoi(first.metadata().TotalObjects.String())
+	limit, limitErr := strconv.Atoi(params.Get("limit"))
+	if nextErr == nil && totalErr == nil && limitErr == nil && limit > 0 {
+		for offset := next; offset < total; offset += limit {
+			offsets = append(offsets, offset)
+		}
+	}
+
+	pages := make([]responsePage, len(offsets))
+	errs := make([]error, len(offsets))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < c.pageConcurrency && w < len(offsets); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				pageParams := url.Values{}
+				for k, v := range params {
+					pageParams[k] = v
+				}
+				pageParams.Set("offset", strconv.Itoa(offsets[i]))
+				pages[i], errs[i] = fetcher(pageParams)
+				if errs[i] == nil {
+					errs[i] = pageStat(pages[i])
+				}
+			}
+		}()
+	}
+	for i := range offsets {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	markSeen(first.getResponse(), seen)
+	for i, page := range pages {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		first.appendResponse(unseenItems(page.getResponse(), seen))
+		first.setMetadata(page.metadata())
+	}
+
+	// Items added during the fetch can push the end of the list past the
+	// computed pages, or total_objects may have been missing; fetch the
+	// rest sequentially.
+	params.Set("offset", first.metadata().NextOffset.String())
+	for params.Get("offset") != "" {
+		page, err := fetcher(params)
+		if err == nil {
+			err = pageStat(page)
+		}
+		if err != nil {
+			return nil, err
+		}
+		first.appendResponse(unseenItems(page.getResponse(), seen))
+		first.setMetadata(page.metadata())
+		params.Set("offset", first.metadata().NextOffset.String())
+	}
+	return first, nil
+}
+
+// pageStat returns a *StatError for a page after the first with a FAIL
+// stat, which would otherwise silently end or leave a gap in the list.
+func pageStat(page responsePage) error {
+	field := reflect.ValueOf(page).Elem().FieldByName("StatResult")
+	if stat, ok := field.Interface().(duoapi.StatResult); ok {
+		return checkStat(stat)
+	}
+	return nil
+}
+
+// identified is implemented by list items with an ID, by which pages
+// fetched concurrently are merged.
+type identified interface {
+	id() string
+}
+
+// itemID returns the ID identifying a list item, or "" if it has none.
+func itemID(item interface{}) string {
+	if item, ok := item.(identified); ok {
+		return item.id()
+	}
+	return ""
+}
+
+// markSeen adds the IDs of a slice of items to seen.
+func markSeen(items interface{}, seen map[string]bool) {
+	v := reflect.ValueOf(items)
+	for i := 0; i < v.Len(); i++ {
+		if id := itemID(v.Index(i).Interface()); id != "" {
+			seen[id] = true
+		}
+	}
+}
+
+// unseenItems returns the items of a slice whose IDs are not in seen, and
+// adds their IDs to seen.  Items without an ID are always returned.
+func unseenItems(items interface{}, seen map[string]bool) interface{} {
+	v := reflect.ValueOf(items)
+	unseen := reflect.MakeSlice(v.Type(), 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		id := itemID(v.Index(i).Interface())
+		if id != "" {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+		}
+		unseen = reflect.Append(unseen, v.Index(i))
+	}
+	return unseen.Interface()
+}
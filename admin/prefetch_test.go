@@ -0,0 +1,163 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// shiftingServer serves n users from /admin/v1/users.  After the first
+// page has been served, shift new users are inserted at the start of the
+// list, moving every existing user to a later offset.
+type shiftingServer struct {
+	mu      sync.Mutex
+	n       int
+	shift   int
+	served  bool
+	failAt  int
+	offsets []int
+}
+
+func (s *shiftingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	s.mu.Lock()
+	s.offsets = append(s.offsets, offset)
+	shift := 0
+	if s.served {
+		shift = s.shift
+	}
+	s.served = true
+	s.mu.Unlock()
+
+	if offset == s.failAt {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, `{"stat": "FAIL", "code": 50301, "message": "Service unavailable"}`)
+		return
+	}
+
+	n := s.n + shift
+	end := offset + limit
+	if end > n {
+		end = n
+	}
+	users := []map[string]string{}
+	for i := offset; i < end; i++ {
+		name := fmt.Sprintf("user%d", i-shift)
+		if i < shift {
+			name = fmt.Sprintf("new%d", i)
+		}
+		users = append(users, map[string]string{"user_id": "DU-" + name, "username": name})
+	}
+	var next interface{}
+	if end < n {
+		next = end
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"stat":     "OK",
+		"response": users,
+		"metadata": map[string]interface{}{"next_offset": next, "total_objects": n},
+	})
+}
+
+func TestGetUsersPageConcurrency(t *testing.T) {
+	srv := &shiftingServer{n: 950, failAt: -1}
+	duo := newTestClient(t, srv.ServeHTTP)
+	duo.SetPageConcurrency(4)
+
+	result, err := duo.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Response) != 950 {
+		t.Fatalf("Expected 950 users, got %d", len(result.Response))
+	}
+	for i, user := range result.Response {
+		if user.Username != fmt.Sprintf("user%d", i) {
+			t.Fatalf("Expected user%d at index %d, got %s", i, i, user.Username)
+		}
+	}
+
+	sort.Ints(srv.offsets)
+	if fmt.Sprint(srv.offsets) != "[0 100 200 300 400 500 600 700 800 900]" {
+		t.Errorf("Unexpected page offsets %v", srv.offsets)
+	}
+}
+
+func TestGetUsersPageConcurrencyDeduplicates(t *testing.T) {
+	srv := &shiftingServer{n: 250, shift: 3, failAt: -1}
+	duo := newTestClient(t, srv.ServeHTTP)
+	duo.SetPageConcurrency(2)
+
+	result, err := duo.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The new users were inserted before the first page, so they are
+	// missed, but every existing user is returned exactly once.
+	if len(result.Response) != 250 {
+		t.Fatalf("Expected 250 users, got %d", len(result.Response))
+	}
+	for i, user := range result.Response {
+		if user.Username != fmt.Sprintf("user%d", i) {
+			t.Fatalf("Expected user%d at index %d, got %s", i, i, user.Username)
+		}
+	}
+	if result.Metadata.NextOffset != "" {
+		t.Errorf("Expected the last page's metadata, got next_offset %s", result.Metadata.NextOffset)
+	}
+}
+
+func TestGetUsersPageConcurrencyFailure(t *testing.T) {
+	srv := &shiftingServer{n: 500, failAt: 200}
+	duo := newTestClient(t, srv.ServeHTTP)
+	duo.SetPageConcurrency(3)
+
+	_, err := duo.GetUsers()
+	statErr, ok := err.(*StatError)
+	if !ok {
+		t.Fatalf("Expected a *StatError, got %v", err)
+	}
+	if statErr.Code == nil || *statErr.Code != 50301 {
+		t.Errorf("Unexpected error: %v", statErr)
+	}
+}
+
+func TestGetUsersPageFailure(t *testing.T) {
+	srv := &shiftingServer{n: 500, failAt: 200}
+	duo := newTestClient(t, srv.ServeHTTP)
+
+	_, err := duo.GetUsers()
+	statErr, ok := err.(*StatError)
+	if !ok {
+		t.Fatalf("Expected a *StatError, got %v", err)
+	}
+	if statErr.Code == nil || *statErr.Code != 50301 {
+		t.Errorf("Unexpected error: %v", statErr)
+	}
+	if fmt.Sprint(srv.offsets) != "[0 100 200]" {
+		t.Errorf("Expected paging to stop at the failed page, got offsets %v", srv.offsets)
+	}
+}
+
+func TestListItemsAreIdentified(t *testing.T) {
+	pages := []responsePage{
+		&GetUsersResult{}, &GetGroupsResult{}, &GetPhonesResult{}, &GetTokensResult{},
+		&GetU2FTokensResult{}, &GetWebAuthnCredentialsResult{}, &GetBypassCodesResult{},
+		&GetAdminsResult{}, &GetAdminActivationsResult{}, &GetAdminUnitsResult{},
+		&GetIntegrationsResult{}, &GetPoliciesResult{},
+	}
+	identifiedType := reflect.TypeOf((*identified)(nil)).Elem()
+	for _, page := range pages {
+		item := reflect.TypeOf(page.getResponse()).Elem()
+		if !item.Implements(identifiedType) {
+			t.Errorf("%s items have no id method", item.Name())
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxBypassCodes is the most bypass codes Duo creates in one request.
+const maxBypassCodes = 10
+
+// maxAdminActivationDays is the longest an administrator activation link
+// may remain valid.
+const maxAdminActivationDays = 31
+
+// FieldError describes a single invalid request parameter.
+type FieldError struct {
+	// Param is the name of the parameter, such as "status".
+	Param string
+	// Value is the rejected value.
+	Value string
+	// Reason explains why the value is invalid.
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s %s", e.Param, e.Reason)
+	}
+	return fmt.Sprintf("%s %q %s", e.Param, e.Value, e.Reason)
+}
+
+// ValidationError is returned, before any request is sent, when parameters
+// would be rejected by Duo.  It lists every invalid parameter.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		msgs[i] = field.Error()
+	}
+	return "invalid parameters: " + strings.Join(msgs, "; ")
+}
+
+// validator collects the invalid parameters of a request.
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(param, value, reason string) {
+	v.fields = append(v.fields, FieldError{Param: param, Value: value, Reason: reason})
+}
+
+func (v *validator) required(param, value string) {
+	if value == "" {
+		v.add(param, "", "is required")
+	}
+}
+
+// oneOf rejects a value which is not one of allowed, including an empty
+// one.  Callers skip fields which are not set.
+func (v *validator) oneOf(param, value string, valid bool, allowed ...string) {
+	if !valid {
+		v.add(param, value, "must be one of "+strings.Join(allowed, ", "))
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// Validate checks the fields of u which Duo constrains.
+func (u UserUpdate) Validate() error {
+	v := &validator{}
+	u.validate(v)
+	return v.err()
+}
+
+func (u UserUpdate) validate(v *validator) {
+	if u.Username != nil && *u.Username == "" {
+		v.add("username", "", "must not be empty")
+	}
+	if u.Status != nil {
+		v.oneOf("status", string(*u.Status), u.Status.settable(),
+			string(UserStatusActive), string(UserStatusBypass), string(UserStatusDisabled))
+	}
+}
+
+// Validate checks the fields of g which Duo constrains.
+func (g GroupUpdate) Validate() error {
+	v := &validator{}
+	g.validate(v)
+	return v.err()
+}
+
+func (g GroupUpdate) validate(v *validator) {
+	if g.Name != nil && *g.Name == "" {
+		v.add("name", "", "must not be empty")
+	}
+	if g.Status != nil {
+		v.oneOf("status", string(*g.Status), g.Status.Valid(),
+			string(GroupStatusActive), string(GroupStatusBypass), string(GroupStatusDisabled))
+	}
+}
+
+// Validate checks the fields of p which Duo constrains.
+func (p PhoneUpdate) Validate() error {
+	v := &validator{}
+	allowed := make([]string, len(phoneTypes))
+	for i, t := range phoneTypes {
+		allowed[i] = string(t)
+	}
+	if p.Type != nil {
+		v.oneOf("type", string(*p.Type), p.Type.Valid(), allowed...)
+	}
+	allowed = make([]string, len(phonePlatforms))
+	for i, platform := range phonePlatforms {
+		allowed[i] = string(platform)
+	}
+	if p.Platform != nil {
+		v.oneOf("platform", string(*p.Platform), p.Platform.Valid(), allowed...)
+	}
+	return v.err()
+}
+
+// Validate checks that t has the fields its type of token requires.
+func (t TokenCreate) Validate() error {
+	v := &validator{}
+	t.validate(v)
+	return v.err()
+}
+
+func (t TokenCreate) validate(v *validator) {
+	v.required("type", string(t.Type))
+	if t.Type != "" {
+		validateTokenType(v, t.Type)
+	}
+	v.required("serial", t.Serial)
+
+	switch t.Type {
+	case TokenTypeHOTP6, TokenTypeHOTP8, TokenTypeTOTP6, TokenTypeTOTP8:
+		v.required("secret", t.Secret)
+	case TokenTypeYubiKey:
+		v.required("private_id", t.PrivateID)
+		v.required("aes_key", t.AESKey)
+	case TokenTypeDuoD100:
+		v.add("type", string(t.Type), "cannot be created")
+	}
+	if t.Counter != nil {
+		if t.Type != TokenTypeHOTP6 && t.Type != TokenTypeHOTP8 {
+			v.add("counter", fmt.Sprint(*t.Counter), "applies only to HOTP tokens")
+		} else if *t.Counter < 0 {
+			v.add("counter", fmt.Sprint(*t.Counter), "must not be negative")
+		}
+	}
+	if t.TOTPStep != nil {
+		if t.Type != TokenTypeTOTP6 && t.Type != TokenTypeTOTP8 {
+			v.add("totp_step", fmt.Sprint(*t.TOTPStep), "applies only to TOTP tokens")
+		} else if *t.TOTPStep != 30 && *t.TOTPStep != 60 {
+			v.add("totp_step", fmt.Sprint(*t.TOTPStep), "must be 30 or 60")
+		}
+	}
+}
+
+func validateTokenType(v *validator, t TokenType) {
+	v.oneOf("type", string(t), t.Valid(),
+		string(TokenTypeHOTP6), string(TokenTypeHOTP8), string(TokenTypeTOTP6),
+		string(TokenTypeTOTP8), string(TokenTypeYubiKey), string(TokenTypeDuoD100))
+}
+
+// Validate checks the fields of b which Duo constrains.
+func (b BypassCodesCreate) Validate() error {
+	v := &validator{}
+	b.validate(v)
+	return v.err()
+}
+
+func (b BypassCodesCreate) validate(v *validator) {
+	if b.Count != nil {
+		count := strconv.Itoa(*b.Count)
+		if *b.Count < 1 || *b.Count > maxBypassCodes {
+			v.add("count", count, fmt.Sprintf("must be from 1 to %d", maxBypassCodes))
+		}
+		if len(b.Codes) > 0 {
+			v.add("count", count, "cannot be combined with codes")
+		}
+	}
+	if len(b.Codes) > maxBypassCodes {
+		v.add("codes", strings.Join(b.Codes, ","), fmt.Sprintf("must hold at most %d codes", maxBypassCodes))
+	}
+	if b.ValidSecs != nil && *b.ValidSecs < 0 {
+		v.add("valid_secs", strconv.Itoa(*b.ValidSecs), "must not be negative")
+	}
+	if b.ReuseCount != nil && *b.ReuseCount < 0 {
+		v.add("reuse_count", strconv.Itoa(*b.ReuseCount), "must not be negative")
+	}
+}
+
+// Validate checks the fields of a which Duo constrains.
+func (a AdminUpdate) Validate() error {
+	v := &validator{}
+	a.validate(v)
+	return v.err()
+}
+
+func (a AdminUpdate) validate(v *validator) {
+	if a.Email != nil && *a.Email == "" {
+		v.add("email", "", "must not be empty")
+	}
+	if a.Name != nil && *a.Name == "" {
+		v.add("name", "", "must not be empty")
+	}
+	if a.Role != nil {
+		v.oneOf("role", string(*a.Role), a.Role.Valid(), adminRoleNames()...)
+	}
+	if a.Status != nil {
+		v.oneOf("status", string(*a.Status), a.Status.settable(),
+			string(AdminStatusActive), string(AdminStatusDisabled))
+	}
+	validateAdminActivationDays(v, a.ValidDays)
+}
+
+// Validate checks the fields of a which Duo constrains.
+func (a AdminActivationCreate) Validate() error {
+	v := &validator{}
+	a.validate(v)
+	return v.err()
+}
+
+func (a AdminActivationCreate) validate(v *validator) {
+	v.required("email", a.Email)
+	if a.Role != nil {
+		v.oneOf("admin_role", string(*a.Role), a.Role.Valid(), adminRoleNames()...)
+	}
+	validateAdminActivationDays(v, a.ValidDays)
+}
+
+func adminRoleNames() []string {
+	names := make([]string, len(adminRoles))
+	for i, r := range adminRoles {
+		names[i] = string(r)
+	}
+	return names
+}
+
+func validateAdminActivationDays(v *validator, days *int) {
+	if days != nil && (*days < 1 || *days > maxAdminActivationDays) {
+		v.add("valid_days", strconv.Itoa(*days), fmt.Sprintf("must be from 1 to %d", maxAdminActivationDays))
+	}
+}
+
+// Validate checks the fields of u which Duo constrains.
+func (u AdminUnitUpdate) Validate() error {
+	v := &validator{}
+	u.validate(v)
+	return v.err()
+}
+
+func (u AdminUnitUpdate) validate(v *validator) {
+	if u.Name != nil && *u.Name == "" {
+		v.add("name", "", "must not be empty")
+	}
+	if u.Description != nil && *u.Description == "" {
+		v.add("description", "", "must not be empty")
+	}
+}
+
+// Validate checks the fields of i which Duo constrains.
+func (i IntegrationUpdate) Validate() error {
+	v := &validator{}
+	i.validate(v)
+	return v.err()
+}
+
+func (i IntegrationUpdate) validate(v *validator) {
+	if i.Name != nil && *i.Name == "" {
+		v.add("name", "", "must not be empty")
+	}
+}
+
+// Validate checks the fields of p which Duo constrains.
+func (p PolicyUpdate) Validate() error {
+	v := &validator{}
+	p.validate(v)
+	return v.err()
+}
+
+func (p PolicyUpdate) validate(v *validator) {
+	if p.PolicyName != nil && *p.PolicyName == "" {
+		v.add("policy_name", "", "must not be empty")
+	}
+	if groups := p.ApplyToGroupsInApps; groups != nil {
+		for _, list := range [][]PolicyGroupsInApp{groups.Apply, groups.Unapply} {
+			for _, g := range list {
+				if g.IntegrationKey == "" {
+					v.add("app_integration_key", "", "is required")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestEnumsValid(t *testing.T) {
+	if !UserStatusLockedOut.Valid() || UserStatus("disable").Valid() {
+		t.Error("Unexpected UserStatus.Valid result")
+	}
+	if !GroupStatusBypass.Valid() || GroupStatus("locked out").Valid() {
+		t.Error("Unexpected GroupStatus.Valid result")
+	}
+	if !PhoneType("Mobile").Valid() || PhoneType("cell").Valid() {
+		t.Error("Unexpected PhoneType.Valid result")
+	}
+	if !PhonePlatform("Apple iOS").Valid() || PhonePlatform("ios").Valid() {
+		t.Error("Unexpected PhonePlatform.Valid result")
+	}
+	if !TokenTypeYubiKey.Valid() || TokenType("H6").Valid() {
+		t.Error("Unexpected TokenType.Valid result")
+	}
+}
+
+func TestValidationErrorListsEveryField(t *testing.T) {
+	err := TokenCreate{
+		Type:     TokenTypeYubiKey,
+		Counter:  Int(3),
+		TOTPStep: Int(30),
+	}.Validate()
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+	var params []string
+	for _, field := range verr.Fields {
+		params = append(params, field.Param)
+	}
+	want := []string{"serial", "private_id", "aes_key", "counter", "totp_step"}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("Expected invalid parameters %v, got %v", want, params)
+	}
+	if verr.Error() != `invalid parameters: serial is required; private_id is required; aes_key is required; counter "3" applies only to HOTP tokens; totp_step "30" applies only to TOTP tokens` {
+		t.Errorf("Unexpected message: %s", verr)
+	}
+
+	if err := (TokenCreate{Type: TokenTypeTOTP6, Serial: "1", Secret: "abc", TOTPStep: Int(60)}).Validate(); err != nil {
+		t.Errorf("Expected a valid TOTP token, got %v", err)
+	}
+	if err := (PhoneUpdate{Type: NewPhoneType("cell"), Platform: NewPhonePlatform("Apple iOS")}).Validate(); err == nil {
+		t.Error("Expected an invalid phone type")
+	}
+	if err := (GroupUpdate{Status: NewGroupStatus("enabled")}).Validate(); err == nil {
+		t.Error("Expected an invalid group status")
+	}
+	// An explicitly empty value is invalid; only nil fields are skipped.
+	err = UserUpdate{Status: NewUserStatus("")}.Validate()
+	if verr, ok := err.(*ValidationError); !ok || len(verr.Fields) != 1 || verr.Fields[0].Param != "status" {
+		t.Errorf("Expected an invalid empty user status, got %v", err)
+	}
+	if err := (PhoneUpdate{Platform: NewPhonePlatform("")}).Validate(); err == nil {
+		t.Error("Expected an invalid empty phone platform")
+	}
+	if err := (UserUpdate{}).Validate(); err != nil {
+		t.Errorf("Expected unset fields to be skipped, got %v", err)
+	}
+}
+
+func TestUserValidationSendsNoRequest(t *testing.T) {
+	requests := 0
+	duo := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(createUserResponse))
+	})
+
+	_, err := duo.CreateUser(UserUpdate{Status: NewUserStatus("disable")})
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+	if len(verr.Fields) != 2 || verr.Fields[0].Param != "username" || verr.Fields[1].Param != "status" {
+		t.Errorf("Unexpected invalid fields %+v", verr.Fields)
+	}
+
+	if _, err = duo.ModifyUser("DU3RP9I2WOC59VZX672N", UserUpdate{Status: NewUserStatus(UserStatusLockedOut)}); err == nil {
+		t.Error("Expected locked out to be rejected as a new status")
+	}
+	if _, err = duo.GetTokens(GetTokensTypeAndSerial("hotp", "123")); err == nil {
+		t.Error("Expected an invalid token type")
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+
+	if _, err = duo.ModifyUser("DU3RP9I2WOC59VZX672N", UserUpdate{Status: NewUserStatus(UserStatusBypass)}); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected a request for a valid update, got %d", requests)
+	}
+}
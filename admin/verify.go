@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"errors"
+	"time"
+)
+
+// verificationPushPollInterval is the wait between checks of a verification
+// push's response.
+const verificationPushPollInterval = 2 * time.Second
+
+// ErrVerificationPushTimeout is returned by WaitForVerificationPush when the
+// user has not answered the push before the timeout.
+var ErrVerificationPushTimeout = errors.New("duo: verification push was not answered before the timeout")
+
+// WaitForVerificationPush polls GetVerificationPushResponse until the user
+// approves, denies or reports the push sent by SendVerificationPush, or
+// until timeout has passed.
+//
+// On timeout it returns the last response, whose result is still waiting,
+// with ErrVerificationPushTimeout.  A FAIL response is returned as a
+// *StatError.
+func (c *Client) WaitForVerificationPush(userID, pushID string, timeout time.Duration) (*VerificationPushResponseResult, error) {
+	clock := c.Clock()
+	deadline := clock.Now().Add(timeout)
+	for {
+		result, err := c.GetVerificationPushResponse(userID, pushID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkStat(result.StatResult); err != nil {
+			return result, err
+		}
+		if result.Response.Result != VerificationPushWaiting {
+			return result, nil
+		}
+
+		remaining := deadline.Sub(clock.Now())
+		if remaining <= 0 {
+			return result, ErrVerificationPushTimeout
+		}
+		if remaining > verificationPushPollInterval {
+			remaining = verificationPushPollInterval
+		}
+		clock.Sleep(remaining)
+	}
+}
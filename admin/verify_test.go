@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+func buildVerifyClient(t *testing.T, answers []string) (*Client, *fakeClock, func()) {
+	polls := 0
+	ts := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/admin/v1/users/DU1/verification_push_response" || r.URL.Query().Get("push_id") != "PUSH1" {
+				t.Errorf("Unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+			}
+			answer := answers[len(answers)-1]
+			if polls < len(answers) {
+				answer = answers[polls]
+			}
+			polls++
+			w.Write([]byte(`{"stat": "OK", "response": {"push_id": "PUSH1", "result": "` + answer + `"}}`))
+		}),
+	)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	host := strings.Split(ts.URL, "//")[1]
+	base := duoapi.NewDuoApi("eyekey", "esskey", host, "GoTestClient", duoapi.SetInsecure(), duoapi.SetClock(clock))
+	return New(*base), clock, ts.Close
+}
+
+func TestWaitForVerificationPush(t *testing.T) {
+	duo, clock, done := buildVerifyClient(t, []string{"waiting", "waiting", "approve"})
+	defer done()
+
+	result, err := duo.WaitForVerificationPush("DU1", "PUSH1", time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error from WaitForVerificationPush call %v", err)
+	}
+	if result.Response.Result != VerificationPushApprove {
+		t.Errorf("Expected approve, but got %s", result.Response.Result)
+	}
+	if clock.slept != 2*verificationPushPollInterval {
+		t.Errorf("Expected two poll intervals, but slept %v", clock.slept)
+	}
+}
+
+func TestWaitForVerificationPushTimeout(t *testing.T) {
+	duo, clock, done := buildVerifyClient(t, []string{"waiting"})
+	defer done()
+
+	result, err := duo.WaitForVerificationPush("DU1", "PUSH1", 5*time.Second)
+	if err != ErrVerificationPushTimeout {
+		t.Fatalf("Expected ErrVerificationPushTimeout, but got %v", err)
+	}
+	if result.Response.Result != VerificationPushWaiting {
+		t.Errorf("Expected waiting, but got %s", result.Response.Result)
+	}
+	if clock.slept != 5*time.Second {
+		t.Errorf("Expected to wait exactly the timeout, but slept %v", clock.slept)
+	}
+}
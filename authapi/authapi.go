@@ -12,6 +12,22 @@ type AuthApi struct {
 	duoapi.DuoApi
 }
 
+// Authenticator is the set of Auth API methods provided by AuthApi.
+// Depend on Authenticator rather than *AuthApi to substitute a fake, such as
+// fakes.Authenticator, in tests.
+type Authenticator interface {
+	Ping() (*PingResult, error)
+	Check() (*CheckResult, error)
+	Logo() (*LogoResult, error)
+	Enroll(options ...func(*url.Values)) (*EnrollResult, error)
+	EnrollStatus(userid string, activationCode string) (*EnrollStatusResult, error)
+	Preauth(options ...func(*url.Values)) (*PreauthResult, error)
+	Auth(factor string, options ...func(*url.Values)) (*AuthResult, error)
+	AuthStatus(txid string) (*AuthStatusResult, error)
+}
+
+var _ Authenticator = (*AuthApi)(nil)
+
 // Build a new Duo Auth API object.
 // api is a duoapi.DuoApi object used to make the Duo Rest API calls.
 // Example: authapi.NewAuthApi(*duoapi.NewDuoApi(ikey,skey,host,userAgent,duoapi.SetTimeout(10*time.Second)))
@@ -0,0 +1,287 @@
+// Package cassette records Duo API traffic to a file and replays it, so that
+// tests can be run deterministically against responses captured once from a
+// real Duo account.
+//
+// Record against a sandbox account:
+//
+//	rec := cassette.NewRecorder(nil)
+//	api := duoapi.NewDuoApi(ikey, skey, host, "", duoapi.SetTransport(rec.Install))
+//	... make calls ...
+//	err := rec.Save("testdata/users.json")
+//
+// Then replay in CI, failing on any request which was not recorded:
+//
+//	c, err := cassette.Load("testdata/users.json")
+//	player := cassette.NewPlayer(c)
+//	player.Strict = true
+//	api := duoapi.NewDuoApi("ikey", "skey", host, "", duoapi.SetTransport(player.Install))
+//
+// Both the Recorder and the Player are http.RoundTrippers, and can also be
+// used with DuoApi.SetCustomHTTPClient through their Client methods.
+//
+// Cassettes never contain request signatures or dates.  The values of the
+// parameters and JSON fields listed in DefaultScrubbedParams are replaced
+// with a placeholder, as are the bypass codes returned by the paths in
+// DefaultScrubbedResponses.  Requests are matched on their method, path,
+// parameters and JSON body only.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+)
+
+// Scrubbed replaces the value of secret-bearing parameters in a cassette.
+const Scrubbed = "[SCRUBBED]"
+
+// DefaultScrubbedParams lists the parameters and JSON fields whose values
+// are scrubbed unless a Recorder or Player is configured otherwise.  Only
+// string values are scrubbed in JSON, so the numeric code of a failed
+// response is kept.
+var DefaultScrubbedParams = []string{
+	"activation_barcode",
+	"activation_msg",
+	"activation_url",
+	"code",
+	"codes",
+	"link",
+	"passcode",
+	"password",
+	"secret_key",
+	"skey",
+}
+
+// DefaultScrubbedResponses lists the paths, as path.Match patterns, whose
+// whole response is scrubbed unless a Recorder is configured otherwise.
+// These return bare lists of bypass codes, which have no field name to
+// scrub by.
+var DefaultScrubbedResponses = []string{
+	"/admin/v1/users/*/bypass_codes",
+}
+
+// Cassette is a sequence of recorded request and response pairs.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.  Headers, including Authorization and
+// Date, are not recorded.  Form bodies are recorded in Params; other bodies
+// are recorded in Body, JSON bodies in their canonical form.
+type Request struct {
+	Method string     `json:"method"`
+	Path   string     `json:"path"`
+	Params url.Values `json:"params"`
+	Body   string     `json:"body,omitempty"`
+}
+
+// Response is a recorded response.  Its Date header is not recorded.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Load reads a cassette from a file written by Save.
+func Load(path string) (*Cassette, error) {
+	body, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{}
+	if err = json.Unmarshal(body, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save writes the cassette to a file as indented JSON.
+func (c *Cassette) Save(path string) error {
+	body, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(body, '\n'), 0644)
+}
+
+// readRequest reads the method, path, parameters and body of req, scrubbing
+// the values of the scrub parameters.  The parameters are taken from the
+// query string and, for requests with a form body, from the body.  JSON
+// bodies are scrubbed and canonicalized so that they match regardless of
+// field order and spacing.  The body is restored so the request can still
+// be sent.
+func readRequest(req *http.Request, scrub []string) (Request, error) {
+	recorded := Request{Method: req.Method, Path: req.URL.Path, Params: url.Values{}}
+	for k, v := range req.URL.Query() {
+		recorded.Params[k] = v
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return Request{}, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		switch mediaType {
+		case "application/x-www-form-urlencoded":
+			form, err := url.ParseQuery(string(body))
+			if err != nil {
+				return Request{}, err
+			}
+			for k, v := range form {
+				recorded.Params[k] = append(recorded.Params[k], v...)
+			}
+		case "application/json":
+			canonical, err := canonicalJSON(body, scrub)
+			if err != nil {
+				return Request{}, err
+			}
+			recorded.Body = canonical
+		default:
+			recorded.Body = string(body)
+		}
+	}
+	for _, name := range scrub {
+		if values, ok := recorded.Params[name]; ok {
+			for i := range values {
+				values[i] = Scrubbed
+			}
+		}
+	}
+	return recorded, nil
+}
+
+// matches reports whether two requests have the same method, path,
+// canonical parameters and body.
+func (r Request) matches(other Request) bool {
+	return r.Method == other.Method &&
+		r.Path == other.Path &&
+		r.Params.Encode() == other.Params.Encode() &&
+		r.Body == other.Body
+}
+
+// canonicalJSON returns body with the scrub fields scrubbed, re-encoded
+// with sorted keys and no insignificant space.
+func canonicalJSON(body []byte, scrub []string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return "", err
+	}
+	scrubJSON(v, scrub)
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(canonical), nil
+}
+
+// scrubResponse returns body with the scrub fields scrubbed and, when path
+// matches one of the responses patterns, its whole response scrubbed.
+// Bodies which are not JSON objects, or which contain nothing to scrub,
+// are returned unchanged.
+func scrubResponse(urlPath string, body []byte, scrub, responses []string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v map[string]interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return body, nil
+	}
+	changed := scrubJSON(v, scrub)
+	for _, pattern := range responses {
+		if matched, _ := path.Match(pattern, urlPath); !matched {
+			continue
+		}
+		if scrubbed, ok := scrubValue(v["response"]); ok {
+			v["response"] = scrubbed
+			changed = true
+		}
+	}
+	if !changed {
+		return body, nil
+	}
+	return json.Marshal(v)
+}
+
+// scrubJSON scrubs the string values of the scrub fields found anywhere in
+// v, in place, and reports whether any were.
+func scrubJSON(v interface{}, scrub []string) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if contains(scrub, k) {
+				if scrubbed, ok := scrubValue(value); ok {
+					v[k] = scrubbed
+					changed = true
+					continue
+				}
+			}
+			if scrubJSON(value, scrub) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if scrubJSON(value, scrub) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// scrubValue returns the scrubbed form of a string or a list of strings.
+// Other values are not scrubbed.
+func scrubValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case string:
+		return Scrubbed, true
+	case []interface{}:
+		scrubbed := make([]interface{}, len(v))
+		for i, value := range v {
+			if _, ok := value.(string); !ok {
+				return v, false
+			}
+			scrubbed[i] = Scrubbed
+		}
+		return scrubbed, true
+	}
+	return v, false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func scrubList(scrub []string) []string {
+	if scrub == nil {
+		return DefaultScrubbedParams
+	}
+	return scrub
+}
+
+func scrubResponseList(responses []string) []string {
+	if responses == nil {
+		return DefaultScrubbedResponses
+	}
+	return responses
+}
@@ -0,0 +1,256 @@
+package cassette
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/admin"
+	"github.com/duosecurity/duo_api_golang/duotest"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := duotest.NewAdminServer("ikey", "skey")
+	defer srv.Close()
+
+	rec := NewRecorder(srv.Server.Client().Transport)
+	client := srv.Client()
+	client.SetCustomHTTPClient(rec.Client())
+
+	created, err := client.CreateUser(admin.UserUpdate{Username: admin.String("jsmith")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetUsers(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "users.json")
+	if err = rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"Authorization", "Date", "skey"} {
+		if strings.Contains(string(saved), secret) {
+			t.Errorf("Cassette contains %q:\n%s", secret, saved)
+		}
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Interactions) != 2 {
+		t.Fatalf("Expected 2 interactions, got %d", len(c.Interactions))
+	}
+
+	// Replay against a different host with different keys; neither is
+	// part of the match.
+	player := NewPlayer(c)
+	player.Strict = true
+	base := duoapi.NewDuoApi("other-ikey", "other-skey", "api-replay.invalid", "")
+	replay := admin.New(*base)
+	replay.SetCustomHTTPClient(player.Client())
+
+	result, err := replay.CreateUser(admin.UserUpdate{Username: admin.String("jsmith")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Response.UserID != created.Response.UserID {
+		t.Errorf("Expected user ID %q, got %q", created.Response.UserID, result.Response.UserID)
+	}
+	users, err := replay.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users.Response) != 1 || users.Response[0].Username != "jsmith" {
+		t.Errorf("Unexpected users: %+v", users.Response)
+	}
+
+	_, err = replay.CreateUser(admin.UserUpdate{Username: admin.String("someone-else")})
+	var unmatched *UnmatchedError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("Expected *UnmatchedError, got %v", err)
+	}
+	if unmatched.Request.Params.Get("username") != "someone-else" {
+		t.Errorf("Unexpected unmatched request: %+v", unmatched.Request)
+	}
+}
+
+func TestRecorderInstall(t *testing.T) {
+	srv := duotest.NewAdminServer("ikey", "skey")
+	defer srv.Close()
+
+	rec := &Recorder{}
+	base := duoapi.NewDuoApi("ikey", "skey", srv.Host(), "", duoapi.SetInsecure(), duoapi.SetTransport(rec.Install))
+	client := admin.New(*base)
+	if _, err := client.GetUsers(); err != nil {
+		t.Fatal(err)
+	}
+
+	interactions := rec.Cassette().Interactions
+	if len(interactions) != 1 {
+		t.Fatalf("Expected 1 interaction, got %d", len(interactions))
+	}
+	got := interactions[0]
+	if got.Request.Method != "GET" || got.Request.Path != "/admin/v1/users" {
+		t.Errorf("Unexpected request: %+v", got.Request)
+	}
+	if got.Response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", got.Response.StatusCode)
+	}
+}
+
+func TestScrubbedParamsMatch(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://api.example.com/auth/v2/auth",
+		strings.NewReader("factor=passcode&passcode=123456&username=jsmith"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorded, err := readRequest(req, DefaultScrubbedParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.Params.Get("passcode") != Scrubbed {
+		t.Errorf("Expected passcode to be scrubbed, got %q", recorded.Params.Get("passcode"))
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	if string(body) != "factor=passcode&passcode=123456&username=jsmith" {
+		t.Errorf("Request body was not restored: %q", body)
+	}
+
+	player := NewPlayer(&Cassette{Interactions: []Interaction{
+		{Request: recorded, Response: Response{StatusCode: 200, Body: "first"}},
+		{Request: recorded, Response: Response{StatusCode: 200, Body: "second"}},
+	}})
+	player.Strict = true
+	for _, want := range []string{"first", "second", "second"} {
+		req, _ := http.NewRequest("POST", "https://api-other.example.com/auth/v2/auth",
+			strings.NewReader("username=jsmith&passcode=654321&factor=passcode"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := player.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if string(body) != want {
+			t.Errorf("Expected %q, got %q", want, body)
+		}
+	}
+}
+
+func TestJSONBodyMatch(t *testing.T) {
+	req, _ := http.NewRequest("PUT", "https://api.example.com/admin/v2/policies/POabc",
+		strings.NewReader(`{"policy_name": "100% of users", "sections": {"b": 2, "a": 1}}`))
+	req.Header.Set("Content-Type", "application/json")
+	recorded, err := readRequest(req, DefaultScrubbedParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded.Params) != 0 {
+		t.Errorf("Expected no parameters, got %v", recorded.Params)
+	}
+	want := `{"policy_name":"100% of users","sections":{"a":1,"b":2}}`
+	if recorded.Body != want {
+		t.Errorf("Expected body %s, got %s", want, recorded.Body)
+	}
+
+	player := NewPlayer(&Cassette{Interactions: []Interaction{
+		{Request: recorded, Response: Response{StatusCode: 200, Body: "ok"}},
+	}})
+	player.Strict = true
+	req, _ = http.NewRequest("PUT", "https://api.example.com/admin/v2/policies/POabc",
+		strings.NewReader(`{"sections":{"a":1,"b":2},"policy_name":"100% of users"}`))
+	req.Header.Set("Content-Type", "application/json")
+	if _, err = player.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ = http.NewRequest("PUT", "https://api.example.com/admin/v2/policies/POabc",
+		strings.NewReader(`{"policy_name":"50% of users"}`))
+	req.Header.Set("Content-Type", "application/json")
+	_, err = player.RoundTrip(req)
+	var unmatched *UnmatchedError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("Expected *UnmatchedError, got %v", err)
+	}
+}
+
+func TestRecorderScrubsResponses(t *testing.T) {
+	srv := duotest.NewAdminServer("ikey", "skey")
+	defer srv.Close()
+
+	rec := NewRecorder(srv.Server.Client().Transport)
+	client := srv.Client()
+	client.SetCustomHTTPClient(rec.Client())
+
+	integration, err := client.CreateIntegration(admin.IntegrationUpdate{
+		Name: admin.String("Web SSO"),
+		Type: "websdk",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretKey, err := client.RotateIntegrationSecretKey(integration.Response.IntegrationKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := client.CreateUser(admin.UserUpdate{Username: admin.String("jsmith")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := client.CreateUserBypassCodes(user.Response.UserID, admin.BypassCodesCreate{Count: admin.Int(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := client.GetUser("DUNOSUCHUSER")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	if err = rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := append([]string{integration.Response.SecretKey, secretKey}, codes.Response...)
+	for _, secret := range secrets {
+		if secret != "" && strings.Contains(string(saved), secret) {
+			t.Errorf("Cassette contains %q:\n%s", secret, saved)
+		}
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := NewPlayer(c)
+	player.Strict = true
+	base := duoapi.NewDuoApi("ikey", "skey", "api-replay.invalid", "")
+	replay := admin.New(*base)
+	replay.SetCustomHTTPClient(player.Client())
+
+	rotated, err := replay.RotateIntegrationSecretKey(integration.Response.IntegrationKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated != Scrubbed {
+		t.Errorf("Expected a scrubbed secret key, got %q", rotated)
+	}
+	// The numeric code of a failed response is not scrubbed.
+	replayed, err := replay.GetUser("DUNOSUCHUSER")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Code == nil || missing.Code == nil || *replayed.Code != *missing.Code {
+		t.Errorf("Expected code %v, got %v", missing.Code, replayed.Code)
+	}
+}
@@ -0,0 +1,117 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// UnmatchedError is returned by a strict Player for a request which is not
+// in its cassette.
+type UnmatchedError struct {
+	Request Request
+}
+
+func (e *UnmatchedError) Error() string {
+	return fmt.Sprintf("cassette: no recorded interaction for %s %s?%s",
+		e.Request.Method, e.Request.Path, e.Request.Params.Encode())
+}
+
+// Player is an http.RoundTripper which answers requests from a cassette.
+//
+// A request matches a recorded interaction with the same method, path and
+// parameters; its signature, date and host are ignored.  Interactions which
+// match the same request are replayed in the order they were recorded, and
+// the last of them is repeated once they have all been used.
+type Player struct {
+	// Strict makes requests without a matching interaction fail with an
+	// *UnmatchedError.  Otherwise they are sent through Transport.
+	Strict bool
+	// Transport sends unmatched requests when Strict is false.  If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Scrub lists the parameters and JSON fields whose values are ignored
+	// when matching.  It should be the list the cassette was recorded
+	// with.  If nil, DefaultScrubbedParams is used.
+	Scrub []string
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewPlayer returns a Player which replays c.
+func NewPlayer(c *Cassette) *Player {
+	return &Player{cassette: c, used: make([]bool, len(c.Interactions))}
+}
+
+// RoundTrip answers req with the response of its matching interaction.
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := readRequest(req, scrubList(p.Scrub))
+	if err != nil {
+		return nil, err
+	}
+
+	if interaction := p.match(recorded); interaction != nil {
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	if p.Strict {
+		return nil, &UnmatchedError{Request: recorded}
+	}
+	transport := p.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(req)
+}
+
+// match returns the next interaction matching req, or nil.
+func (p *Player) match(req Request) *Interaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	last := -1
+	for i := range p.cassette.Interactions {
+		if !p.cassette.Interactions[i].Request.matches(req) {
+			continue
+		}
+		if !p.used[i] {
+			p.used[i] = true
+			return &p.cassette.Interactions[i]
+		}
+		last = i
+	}
+	if last >= 0 {
+		return &p.cassette.Interactions[last]
+	}
+	return nil
+}
+
+// Install answers all HTTPS requests made through tr from the Player.  When
+// the Player is not strict, unmatched requests are sent through a copy of
+// tr.  Pass it to duoapi.SetTransport.
+func (p *Player) Install(tr *http.Transport) {
+	if p.Transport == nil {
+		p.Transport = tr.Clone()
+	}
+	tr.RegisterProtocol("https", p)
+}
+
+// Client returns an HTTP client which replays through the Player, for use
+// with DuoApi.SetCustomHTTPClient.
+func (p *Player) Client() *http.Client {
+	return &http.Client{Transport: p}
+}
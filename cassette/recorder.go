@@ -0,0 +1,102 @@
+package cassette
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper which sends requests to Duo through
+// Transport and records each request and response.
+type Recorder struct {
+	// Transport sends the recorded requests.  If nil, http.DefaultTransport
+	// is used.
+	Transport http.RoundTripper
+	// Scrub lists the parameters and JSON fields whose values are not
+	// recorded.  If nil, DefaultScrubbedParams is used.
+	Scrub []string
+	// ScrubResponses lists the paths whose whole response is not recorded.
+	// If nil, DefaultScrubbedResponses is used.
+	ScrubResponses []string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder which sends requests through transport.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	return &Recorder{Transport: transport}
+}
+
+// RoundTrip sends req and records the request and its response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := readRequest(req, scrubList(r.Scrub))
+	if err != nil {
+		return nil, err
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	scrubbed, err := scrubResponse(recorded.Path, body, scrubList(r.Scrub), scrubResponseList(r.ScrubResponses))
+	if err != nil {
+		return nil, err
+	}
+
+	// The response date would make every re-recorded cassette differ.
+	header := resp.Header.Clone()
+	header.Del("Date")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       string(scrubbed),
+		},
+	})
+	return resp, nil
+}
+
+// Install sends all HTTPS requests made through tr to the Recorder, which
+// forwards them through a copy of tr, keeping its TLS and proxy settings.
+// Pass it to duoapi.SetTransport.
+func (r *Recorder) Install(tr *http.Transport) {
+	if r.Transport == nil {
+		r.Transport = tr.Clone()
+	}
+	tr.RegisterProtocol("https", r)
+}
+
+// Client returns an HTTP client which records through the Recorder, for
+// use with DuoApi.SetCustomHTTPClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Cassette returns a copy of the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the interactions recorded so far to a cassette file.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
@@ -0,0 +1,194 @@
+package chaos
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/admin"
+	"github.com/duosecurity/duo_api_golang/duotest"
+)
+
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) Jitter(max time.Duration) time.Duration {
+	return 0
+}
+
+// newClient returns an admin client for srv whose requests pass through a
+// chaos Transport with the given rules.
+func newClient(t *testing.T, srv *duotest.AdminServer, rules string) (*admin.Client, *Transport, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	chaos, err := New(rules, srv.Server.Client().Transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chaos.Clock = clock
+	base := duoapi.NewDuoApi(srv.IKey, srv.SKey, srv.Host(), "", duoapi.SetClock(clock))
+	base.SetCustomHTTPClient(chaos.Client())
+	return admin.New(*base), chaos, clock
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules(`
+		POST /auth/v2/auth status=429 count=3   # a storm
+		/admin/v1/* status=503 rate=0.25 after=2; GET /admin/v1/users truncate=10
+		/auth/v2/preauth latency=5s
+		* reset latency=1s
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 5 {
+		t.Fatalf("Expected 5 rules, got %d", len(rules))
+	}
+
+	storm := rules[0]
+	if storm.Method != "POST" || storm.Pattern != "/auth/v2/auth" || storm.Fault != FaultStatus || storm.Status != 429 || storm.Count != 3 || storm.Rate != 1 {
+		t.Errorf("Unexpected rule: %+v", storm)
+	}
+	if r := rules[1]; r.Method != "" || r.Rate != 0.25 || r.After != 2 || !r.matches("GET", "/admin/v1/users/DU1/phones") || r.matches("GET", "/auth/v2/check") {
+		t.Errorf("Unexpected rule: %+v", r)
+	}
+	if r := rules[2]; r.Fault != FaultTruncate || r.Truncate != 10 || r.matches("POST", "/admin/v1/users") {
+		t.Errorf("Unexpected rule: %+v", r)
+	}
+	if r := rules[3]; r.Fault != FaultLatency || r.Latency != 5*time.Second {
+		t.Errorf("Unexpected rule: %+v", r)
+	}
+	if r := rules[4]; r.Fault != FaultReset || r.Latency != time.Second || !r.matches("GET", "/anything") {
+		t.Errorf("Unexpected rule: %+v", r)
+	}
+
+	for _, bad := range []string{
+		"GET",
+		"/auth/v2/auth",
+		"/auth/v2/auth status=200",
+		"/auth/v2/auth status=503 reset",
+		"/auth/v2/auth reset=1",
+		"/auth/v2/auth tls rate=2",
+		"/auth/v2/auth latency=soon",
+		"/auth/v2/auth explode",
+	} {
+		if _, err := ParseRule(bad); err == nil {
+			t.Errorf("Expected %q to fail to parse", bad)
+		}
+	}
+}
+
+func TestRateLimitStormIsRetried(t *testing.T) {
+	srv := duotest.NewAdminServer("ikey", "skey")
+	defer srv.Close()
+	client, chaos, clock := newClient(t, srv, "POST /admin/v1/users status=429 count=3")
+
+	result, err := client.CreateUser(admin.UserUpdate{Username: admin.String("jsmith")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stat != "OK" {
+		t.Fatalf("Expected the call to succeed once the storm cleared, got %+v", result)
+	}
+
+	injections := chaos.Injections()
+	if len(injections) != 3 {
+		t.Fatalf("Expected 3 injections, got %d", len(injections))
+	}
+	if got := injections[0].String(); got != "POST /admin/v1/users: status=429 (POST /admin/v1/users status=429 count=3)" {
+		t.Errorf("Unexpected injection: %s", got)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if len(clock.slept) != len(want) {
+		t.Fatalf("Expected backoff %v, got %v", want, clock.slept)
+	}
+	for i := range want {
+		if clock.slept[i] != want[i] {
+			t.Errorf("Expected backoff %v, got %v", want, clock.slept)
+			break
+		}
+	}
+}
+
+func TestServerErrorsAndCorruption(t *testing.T) {
+	srv := duotest.NewAdminServer("ikey", "skey")
+	defer srv.Close()
+	client, chaos, _ := newClient(t, srv, `
+		GET /admin/v1/users status=503 count=1
+		GET /admin/v1/users truncate count=1
+	`)
+
+	result, err := client.GetUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stat != "FAIL" || result.Code == nil || *result.Code != 50301 {
+		t.Errorf("Expected a 503 FAIL result, got %+v", result.StatResult)
+	}
+
+	if _, err = client.GetUsers(); err == nil {
+		t.Error("Expected an error decoding a truncated body")
+	}
+
+	if _, err = client.GetUsers(); err != nil {
+		t.Errorf("Expected requests to succeed once the rules were spent, got %v", err)
+	}
+	if n := len(chaos.Injections()); n != 2 {
+		t.Errorf("Expected 2 injections, got %d", n)
+	}
+}
+
+func TestConnectionFailures(t *testing.T) {
+	srv := duotest.NewAdminServer("ikey", "skey")
+	defer srv.Close()
+	client, chaos, clock := newClient(t, srv, `
+		/admin/v1/users reset latency=3s after=1
+		/admin/v1/groups tls
+	`)
+	var reported []Injection
+	chaos.OnInject = func(i Injection) { reported = append(reported, i) }
+
+	if _, err := client.GetUsers(); err != nil {
+		t.Fatalf("Expected the first request to pass, got %v", err)
+	}
+	_, err := client.GetUsers()
+	if !errors.Is(err, syscall.ECONNRESET) {
+		t.Errorf("Expected a connection reset, got %v", err)
+	}
+	if len(clock.slept) != 1 || clock.slept[0] != 3*time.Second {
+		t.Errorf("Expected 3s of latency, got %v", clock.slept)
+	}
+
+	_, err = client.GetGroups()
+	if err == nil || !strings.Contains(err.Error(), "tls: handshake failure") {
+		t.Errorf("Expected a TLS handshake failure, got %v", err)
+	}
+
+	if len(reported) != 2 || reported[0].Fault != FaultReset || reported[1].Fault != FaultTLS {
+		t.Errorf("Unexpected reported injections: %v", reported)
+	}
+	chaos.Reset()
+	if n := len(chaos.Injections()); n != 0 {
+		t.Errorf("Expected Reset to forget injections, got %d", n)
+	}
+}
@@ -0,0 +1,204 @@
+// Package chaos provides an http.RoundTripper which injects faults into
+// Duo API traffic, to test how clients behave when Duo misbehaves.
+//
+// Faults are described by rules, one per line or separated by semicolons:
+//
+//	[METHOD] PATTERN FAULT [latency=DURATION] [rate=P] [after=N] [count=N]
+//
+// PATTERN matches the request path, and "*" in it matches any sequence of
+// characters.  FAULT is one of:
+//
+//	latency=DURATION  delay the request, then send it
+//	status=CODE       respond with a Duo FAIL response with the HTTP status CODE
+//	truncate[=BYTES]  send the request, then cut its response body to BYTES,
+//	                  or to half its length
+//	tls               fail the request with a TLS handshake failure
+//	reset             fail the request with a connection reset
+//
+// A rule fires on a matching request with probability rate (default 1),
+// ignoring the first after matches and firing at most count times (default
+// unlimited).  The first rule to fire on a request applies.  For example:
+//
+//	POST /auth/v2/auth status=429 count=3   # a 429 storm which clears
+//	/admin/v1/* status=503 rate=0.1
+//	GET /admin/v1/users truncate
+//	/auth/v2/preauth latency=5s
+//	* reset after=10 count=1
+package chaos
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault is a kind of injected failure.
+type Fault string
+
+const (
+	FaultLatency  Fault = "latency"
+	FaultStatus   Fault = "status"
+	FaultTruncate Fault = "truncate"
+	FaultTLS      Fault = "tls"
+	FaultReset    Fault = "reset"
+)
+
+// Rule injects a fault into requests matching Method and Pattern.
+type Rule struct {
+	// Method is the HTTP method to match, or "" for any method.
+	Method string
+	// Pattern matches request paths; "*" matches any sequence of
+	// characters.
+	Pattern string
+	// Fault is the failure to inject.
+	Fault Fault
+	// Latency delays the request.  It may be combined with any Fault.
+	Latency time.Duration
+	// Status is the HTTP status code of a FaultStatus response.
+	Status int
+	// Truncate is the number of body bytes kept by FaultTruncate, or 0 to
+	// keep half of the body.
+	Truncate int
+	// Rate is the probability that the rule fires on a matching request.
+	Rate float64
+	// After is the number of matching requests to let through before the
+	// rule first fires.
+	After int
+	// Count is the maximum number of times the rule fires, or 0 for no
+	// limit.
+	Count int
+
+	text string
+	re   *regexp.Regexp
+}
+
+// String returns the rule as it was written.
+func (r *Rule) String() string {
+	return r.text
+}
+
+func (r *Rule) matches(method, path string) bool {
+	if r.Method != "" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	return r.re.MatchString(path)
+}
+
+// ParseRules parses rules written in the package's rule language.  Blank
+// lines and text following a "#" are ignored.
+func ParseRules(text string) ([]*Rule, error) {
+	var rules []*Rule
+	for _, line := range strings.FieldsFunc(text, func(c rune) bool { return c == '\n' || c == ';' }) {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rule, err := ParseRule(line)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseRule parses a single rule.
+func ParseRule(text string) (*Rule, error) {
+	fields := strings.Fields(text)
+	rule := &Rule{Rate: 1, text: strings.Join(fields, " ")}
+	fail := func(format string, args ...interface{}) (*Rule, error) {
+		return nil, fmt.Errorf("chaos: rule %q: %s", rule.text, fmt.Sprintf(format, args...))
+	}
+
+	if len(fields) > 0 && !strings.HasPrefix(fields[0], "/") && fields[0] != "*" {
+		rule.Method = strings.ToUpper(fields[0])
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return fail("missing path pattern")
+	}
+	rule.Pattern = fields[0]
+	rule.re = compilePattern(rule.Pattern)
+
+	for _, field := range fields[1:] {
+		name, value := field, ""
+		hasValue := false
+		if i := strings.Index(field, "="); i >= 0 {
+			name, value, hasValue = field[:i], field[i+1:], true
+		}
+
+		var err error
+		switch name {
+		case "latency":
+			rule.Latency, err = time.ParseDuration(value)
+			if err == nil && rule.Latency <= 0 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "status":
+			rule.Status, err = strconv.Atoi(value)
+			if err == nil && (rule.Status < 400 || rule.Status > 599) {
+				err = fmt.Errorf("must be a 4xx or 5xx status code")
+			}
+		case "truncate":
+			if hasValue {
+				rule.Truncate, err = strconv.Atoi(value)
+				if err == nil && rule.Truncate < 0 {
+					err = fmt.Errorf("must not be negative")
+				}
+			}
+		case "tls", "reset":
+			if hasValue {
+				err = fmt.Errorf("takes no value")
+			}
+		case "rate":
+			rule.Rate, err = strconv.ParseFloat(value, 64)
+			if err == nil && (rule.Rate < 0 || rule.Rate > 1) {
+				err = fmt.Errorf("must be between 0 and 1")
+			}
+		case "after":
+			rule.After, err = strconv.Atoi(value)
+			if err == nil && rule.After < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		case "count":
+			rule.Count, err = strconv.Atoi(value)
+			if err == nil && rule.Count < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		default:
+			return fail("unknown option %q", name)
+		}
+		if err != nil {
+			return fail("invalid %s: %v", name, err)
+		}
+
+		switch name {
+		case "status", "truncate", "tls", "reset":
+			if rule.Fault != "" && rule.Fault != FaultLatency {
+				return fail("more than one fault")
+			}
+			rule.Fault = Fault(name)
+		case "latency":
+			if rule.Fault == "" {
+				rule.Fault = FaultLatency
+			}
+		}
+	}
+	if rule.Fault == "" {
+		return fail("missing fault")
+	}
+	return rule, nil
+}
+
+// compilePattern converts a path pattern to an anchored regular expression.
+func compilePattern(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
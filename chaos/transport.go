@@ -0,0 +1,265 @@
+package chaos
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// Injection records a fault injected into a request.
+type Injection struct {
+	Time    time.Time
+	Rule    *Rule
+	Method  string
+	Path    string
+	Fault   Fault
+	Latency time.Duration
+}
+
+func (i Injection) String() string {
+	s := fmt.Sprintf("%s %s: %s", i.Method, i.Path, i.Fault)
+	switch {
+	case i.Fault == FaultStatus:
+		s += "=" + strconv.Itoa(i.Rule.Status)
+	case i.Fault == FaultTruncate && i.Rule.Truncate > 0:
+		s += "=" + strconv.Itoa(i.Rule.Truncate)
+	}
+	if i.Latency > 0 && i.Fault != FaultLatency {
+		s += " after " + i.Latency.String()
+	}
+	return s + " (" + i.Rule.String() + ")"
+}
+
+// Transport is an http.RoundTripper which injects the faults described by
+// its Rules, and sends other requests through Transport.
+type Transport struct {
+	Rules []*Rule
+	// Transport sends requests which are not failed.  If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Clock times injected latency.  If nil, latency is waited for in real
+	// time and cut short if the request's context is cancelled.
+	Clock duoapi.Clock
+	// Rand decides whether rules with a rate below 1 fire.  If nil, the
+	// math/rand package's source is used.
+	Rand *rand.Rand
+	// OnInject, if set, is called for each injected fault.
+	OnInject func(Injection)
+
+	mu         sync.Mutex
+	matched    map[*Rule]int
+	fired      map[*Rule]int
+	injections []Injection
+}
+
+// New returns a Transport injecting the faults described by rules, which
+// are parsed with ParseRules.
+func New(rules string, transport http.RoundTripper) (*Transport, error) {
+	parsed, err := ParseRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return &Transport{Rules: parsed, Transport: transport}, nil
+}
+
+// Client returns an HTTP client which sends requests through the Transport,
+// for use with DuoApi.SetCustomHTTPClient.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Install sends all HTTPS requests made through tr to the Transport, which
+// forwards them through a copy of tr.  Pass it to duoapi.SetTransport.
+func (t *Transport) Install(tr *http.Transport) {
+	if t.Transport == nil {
+		t.Transport = tr.Clone()
+	}
+	tr.RegisterProtocol("https", t)
+}
+
+// Injections returns the faults injected so far, in order.
+func (t *Transport) Injections() []Injection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Injection(nil), t.injections...)
+}
+
+// Reset forgets the injected faults and restarts the after and count limits
+// of every rule.
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.matched = nil
+	t.fired = nil
+	t.injections = nil
+}
+
+// RoundTrip injects the fault of the first rule to fire on req, or sends
+// req unchanged.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rule := t.fire(req)
+	if rule == nil {
+		return t.next().RoundTrip(req)
+	}
+
+	if rule.Latency > 0 {
+		if err := t.sleep(req, rule.Latency); err != nil {
+			closeBody(req)
+			return nil, err
+		}
+	}
+
+	switch rule.Fault {
+	case FaultStatus:
+		closeBody(req)
+		return statusResponse(req, rule.Status), nil
+	case FaultTLS:
+		closeBody(req)
+		return nil, &net.OpError{Op: "remote error", Net: "tcp", Err: errors.New("tls: handshake failure")}
+	case FaultReset:
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case FaultTruncate:
+		resp, err := t.next().RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		keep := len(body) / 2
+		if rule.Truncate > 0 {
+			keep = rule.Truncate
+			if keep > len(body) {
+				keep = len(body)
+			}
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body[:keep]))
+		resp.ContentLength = int64(keep)
+		resp.Header.Del("Content-Length")
+		return resp, nil
+	}
+	return t.next().RoundTrip(req)
+}
+
+// fire returns the first rule to fire on req, recording its injection.
+func (t *Transport) fire(req *http.Request) *Rule {
+	t.mu.Lock()
+	if t.matched == nil {
+		t.matched = make(map[*Rule]int)
+		t.fired = make(map[*Rule]int)
+	}
+
+	var injection Injection
+	for _, rule := range t.Rules {
+		if !rule.matches(req.Method, req.URL.Path) {
+			continue
+		}
+		t.matched[rule]++
+		if t.matched[rule] <= rule.After {
+			continue
+		}
+		if rule.Count > 0 && t.fired[rule] >= rule.Count {
+			continue
+		}
+		if rule.Rate < 1 && t.random() >= rule.Rate {
+			continue
+		}
+		t.fired[rule]++
+		injection = Injection{
+			Time:    t.now(),
+			Rule:    rule,
+			Method:  req.Method,
+			Path:    req.URL.Path,
+			Fault:   rule.Fault,
+			Latency: rule.Latency,
+		}
+		t.injections = append(t.injections, injection)
+		break
+	}
+	t.mu.Unlock()
+
+	if injection.Rule == nil {
+		return nil
+	}
+	if t.OnInject != nil {
+		t.OnInject(injection)
+	}
+	return injection.Rule
+}
+
+func (t *Transport) next() http.RoundTripper {
+	if t.Transport == nil {
+		return http.DefaultTransport
+	}
+	return t.Transport
+}
+
+func (t *Transport) now() time.Time {
+	if t.Clock == nil {
+		return time.Now()
+	}
+	return t.Clock.Now()
+}
+
+func (t *Transport) random() float64 {
+	if t.Rand == nil {
+		return rand.Float64()
+	}
+	return t.Rand.Float64()
+}
+
+func (t *Transport) sleep(req *http.Request, d time.Duration) error {
+	if t.Clock != nil {
+		t.Clock.Sleep(d)
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// statusResponse returns a Duo FAIL response with the given HTTP status.
+func statusResponse(req *http.Request, status int) *http.Response {
+	body, _ := json.Marshal(map[string]interface{}{
+		"stat":    "FAIL",
+		"code":    status*100 + 1,
+		"message": http.StatusText(status),
+	})
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
@@ -0,0 +1,100 @@
+// Command duo-auth-fake runs duotest's fake Duo Auth API server, so that
+// applications written in any language can be tested against simulated
+// users.
+//
+// Usage:
+//
+//	duo-auth-fake -listen 127.0.0.1:8443 -ikey DIXXXXXXXXXXXXXXXXXX -skey secret \
+//		-user alice=approve:3s -user bob=deny -user carol=passcode:0s:123456
+//
+// Each -user flag configures a user as username=outcome[:after[:passcode]],
+// where outcome is one of approve, deny, timeout, passcode, fraud,
+// not_enrolled or bypass.  Unknown users behave as -default.  The server
+// uses TLS with a self-signed certificate unless -cert and -key are given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/duosecurity/duo_api_golang/duotest"
+)
+
+type userFlags []string
+
+func (u *userFlags) String() string {
+	return strings.Join(*u, ",")
+}
+
+func (u *userFlags) Set(value string) error {
+	*u = append(*u, value)
+	return nil
+}
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8443", "address to listen on")
+	ikey := flag.String("ikey", "", "integration key requests must be signed with")
+	skey := flag.String("skey", "", "secret key requests must be signed with")
+	defaultScenario := flag.String("default", "not_enrolled", "scenario of unknown users")
+	cert := flag.String("cert", "", "TLS certificate file")
+	key := flag.String("key", "", "TLS private key file")
+	logo := flag.String("logo", "", "PNG file served as the logo")
+	var users userFlags
+	flag.Var(&users, "user", "username=outcome[:after[:passcode]] (repeatable)")
+	flag.Parse()
+
+	if *ikey == "" || *skey == "" {
+		fmt.Fprintln(os.Stderr, "duo-auth-fake: -ikey and -skey are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	handler := duotest.NewAuthHandler(*ikey, *skey)
+	scenario, err := duotest.ParseScenario(*defaultScenario)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handler.DefaultScenario = scenario
+
+	for _, user := range users {
+		parts := strings.SplitN(user, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			log.Fatalf("duo-auth-fake: invalid -user %q", user)
+		}
+		scenario, err := duotest.ParseScenario(parts[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		userID := handler.SetUser(parts[0], scenario)
+		log.Printf("user %s (%s): %s", parts[0], userID, parts[1])
+	}
+
+	if *logo != "" {
+		if handler.Logo, err = ioutil.ReadFile(*logo); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *cert != "" || *key != "" {
+		log.Printf("listening on https://%s", *listen)
+		log.Fatal(http.ListenAndServeTLS(*listen, *cert, *key, handler))
+	}
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener.Close()
+	srv.Listener = l
+	srv.StartTLS()
+	log.Printf("listening on %s with a self-signed certificate", srv.URL)
+	select {}
+}
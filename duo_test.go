@@ -2,9 +2,16 @@ package duoapi
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"strings"
@@ -154,6 +161,74 @@ func TestV2Canonicalize(t *testing.T) {
 	}
 }
 
+// The version 5 vectors below are written out from the canonical request
+// documented at https://duo.com/docs/adminapi#authentication rather than
+// produced by canonicalizeV5: the X-Duo-Date date, the upper-cased method,
+// the lower-cased host, the path, the canonical query parameters, then the
+// hex SHA-512 digests of the body and of the signed X-Duo headers, one per
+// line.  sha512ABC and sha512Empty are the SHA-512 digests of "abc" and ""
+// given as examples in FIPS 180-2, Appendix C.
+const (
+	sha512ABC   = "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"
+	sha512Empty = "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e"
+)
+
+// authorizationV5 returns the Authorization header for a version 5
+// canonical request, computed independently of signV5.
+func authorizationV5(ikey, skey, canon string) string {
+	mac := hmac.New(sha512.New, []byte(skey))
+	mac.Write([]byte(canon))
+	auth := ikey + ":" + hex.EncodeToString(mac.Sum(nil))
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+}
+
+func TestCanonicalizeV5(t *testing.T) {
+	values := url.Values{}
+	values.Set("user_id", "DU3RP9I2WOC59VZX672N")
+	values.Set("integration_key", "DIRPNK5JHAV69IOFQ9BA")
+	canon := canonicalizeV5("PoSt",
+		"foO.BAr52.cOm",
+		"/Foo/BaR2/qux",
+		values,
+		[]byte("abc"),
+		"Tue, 21 Aug 2012 17:29:18 +0000")
+	expected := "Tue, 21 Aug 2012 17:29:18 +0000\n" +
+		"POST\n" +
+		"foo.bar52.com\n" +
+		"/Foo/BaR2/qux\n" +
+		"integration_key=DIRPNK5JHAV69IOFQ9BA&user_id=DU3RP9I2WOC59VZX672N\n" +
+		sha512ABC + "\n" +
+		sha512Empty
+	if canon != expected {
+		t.Error("Mismatch!\n" + expected + "\n" + canon)
+	}
+
+	// Without a body, the body digest is that of the empty string.
+	canon = canonicalizeV5("GET", "api-XXXXXXXX.duosecurity.com", "/admin/v2/policies", nil, nil,
+		"Tue, 21 Aug 2012 17:29:18 +0000")
+	expected = "Tue, 21 Aug 2012 17:29:18 +0000\nGET\napi-xxxxxxxx.duosecurity.com\n/admin/v2/policies\n\n" +
+		sha512Empty + "\n" + sha512Empty
+	if canon != expected {
+		t.Error("Mismatch!\n" + expected + "\n" + canon)
+	}
+}
+
+func TestSignV5(t *testing.T) {
+	res := signV5("DIWJ8X6AEYOR5OMC6TQ1",
+		"Zh5eGmUq9zpfQnyUIu5OL9iWoMMv5ZNmk3zLJ4Ep",
+		"POST",
+		"api-XXXXXXXX.duosecurity.com",
+		"/admin/v2/policies",
+		"Tue, 21 Aug 2012 17:29:18 +0000",
+		nil,
+		[]byte("abc"))
+	canon := "Tue, 21 Aug 2012 17:29:18 +0000\nPOST\napi-xxxxxxxx.duosecurity.com\n/admin/v2/policies\n\n" +
+		sha512ABC + "\n" + sha512Empty
+	if expected := authorizationV5("DIWJ8X6AEYOR5OMC6TQ1", "Zh5eGmUq9zpfQnyUIu5OL9iWoMMv5ZNmk3zLJ4Ep", canon); res != expected {
+		t.Errorf("Expected version 5 signature %q, got %q", expected, res)
+	}
+}
+
 func TestNewDuo(t *testing.T) {
 	duo := NewDuoApi("ABC", "123", "api-XXXXXXX.duosecurity.com", "go-client")
 	if duo == nil {
@@ -343,3 +418,319 @@ func (svc *mockSleepService) Sleep(duration time.Duration) {
 	}
 	svc.sleepCalls = append(svc.sleepCalls, duration)
 }
+
+func TestProxyBypassMatches(t *testing.T) {
+	bypass := proxyBypass{"internal.example.com", ".corp.example.com", "*.lab.example.com"}
+	cases := map[string]bool{
+		"internal.example.com":         true,
+		"INTERNAL.example.com:443":     true,
+		"other.example.com":            false,
+		"corp.example.com":             true,
+		"api.corp.example.com:443":     true,
+		"lab.example.com":              true,
+		"a.b.lab.example.com":          true,
+		"notcorp.example.com":          false,
+		"api-xxxxxxxx.duosecurity.com": false,
+	}
+	for host, want := range cases {
+		if got := bypass.matches(host); got != want {
+			t.Errorf("matches(%q) = %v, want %v", host, got, want)
+		}
+	}
+	if !(proxyBypass{"*"}).matches("anything.example.com:443") {
+		t.Error("Expected * to match every host")
+	}
+}
+
+// serveSOCKS5 accepts a single connection on l, checks the client's
+// credentials and tunnels the connection through to the requested address.
+func serveSOCKS5(t *testing.T, l net.Listener, username, password string) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 262)
+	// Greeting
+	if _, err = io.ReadFull(conn, buf[:2]); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		t.Error(err)
+		return
+	}
+	conn.Write([]byte{socks5Version, socks5AuthPassword})
+
+	// Username/password authentication
+	io.ReadFull(conn, buf[:2])
+	user := make([]byte, buf[1])
+	io.ReadFull(conn, user)
+	io.ReadFull(conn, buf[:1])
+	pass := make([]byte, buf[0])
+	io.ReadFull(conn, pass)
+	if string(user) != username || string(pass) != password {
+		conn.Write([]byte{socks5PasswordVersion, 0x01})
+		return
+	}
+	conn.Write([]byte{socks5PasswordVersion, 0x00})
+
+	// Connect request
+	io.ReadFull(conn, buf[:4])
+	var host string
+	switch buf[3] {
+	case socks5AddrIPv4:
+		io.ReadFull(conn, buf[:net.IPv4len])
+		host = net.IP(buf[:net.IPv4len]).String()
+	case socks5AddrDomain:
+		io.ReadFull(conn, buf[:1])
+		name := make([]byte, buf[0])
+		io.ReadFull(conn, name)
+		host = string(name)
+	default:
+		t.Errorf("Unexpected address type %d", buf[3])
+		return
+	}
+	io.ReadFull(conn, buf[:2])
+	port := int(buf[0])<<8 | int(buf[1])
+
+	target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		conn.Write([]byte{socks5Version, 0x05, 0x00, socks5AddrIPv4, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer target.Close()
+	conn.Write([]byte{socks5Version, 0x00, 0x00, socks5AddrIPv4, 127, 0, 0, 1, 0, 0})
+
+	go io.Copy(target, conn)
+	io.Copy(conn, target)
+}
+
+func TestSOCKS5Proxy(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"stat": "OK"}`))
+	}))
+	defer ts.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go serveSOCKS5(t, l, "proxyuser", "proxypass")
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	duo := NewDuoApi("ABC", "123", host, "go-client",
+		SetInsecure(),
+		SetTimeout(5*time.Second),
+		SetSOCKS5Proxy(l.Addr().String(), "proxyuser", "proxypass"))
+
+	_, body, err := duo.SignedCall("GET", "/auth/v2/check", nil, UseTimeout)
+	if err != nil {
+		t.Fatal("Unexpected error through SOCKS5 proxy: " + err.Error())
+	}
+	if string(body) != `{"stat": "OK"}` {
+		t.Errorf("Unexpected body: %s", body)
+	}
+}
+
+func TestSOCKS5ProxyBadCredentials(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go serveSOCKS5(t, l, "proxyuser", "proxypass")
+
+	duo := NewDuoApi("ABC", "123", "127.0.0.1:1", "go-client",
+		SetTimeout(5*time.Second),
+		SetSOCKS5Proxy(l.Addr().String(), "proxyuser", "wrong"))
+
+	_, _, err = duo.Call("GET", "/auth/v2/ping", nil, UseTimeout)
+	if err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Fatalf("Expected authentication failure, got %v", err)
+	}
+}
+
+func TestProxyAuthAndBypass(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"stat": "OK"}`))
+	}))
+	defer ts.Close()
+
+	var proxyAuth string
+	ps := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		http.Error(w, "proxy says no", http.StatusProxyAuthRequired)
+	}))
+	defer ps.Close()
+	proxyURL, _ := url.Parse(ps.URL)
+
+	host := strings.TrimPrefix(ts.URL, "https://")
+	duo := NewDuoApi("ABC", "123", host, "go-client",
+		SetInsecure(),
+		SetProxy(http.ProxyURL(proxyURL)),
+		SetProxyAuth("proxyuser", "proxypass"))
+
+	if _, _, err := duo.Call("GET", "/auth/v2/ping", nil); err == nil {
+		t.Fatal("Expected the proxy to refuse the connection")
+	}
+	if proxyAuth != proxyAuthorization("proxyuser", "proxypass") {
+		t.Errorf("Unexpected Proxy-Authorization header %q", proxyAuth)
+	}
+
+	duo = NewDuoApi("ABC", "123", host, "go-client",
+		SetInsecure(),
+		SetProxy(http.ProxyURL(proxyURL)),
+		SetProxyBypass("127.0.0.1"))
+
+	_, body, err := duo.Call("GET", "/auth/v2/ping", nil)
+	if err != nil {
+		t.Fatal("Expected bypassed host to be contacted directly: " + err.Error())
+	}
+	if string(body) != `{"stat": "OK"}` {
+		t.Errorf("Unexpected body: %s", body)
+	}
+}
+
+type fakeClock struct {
+	now        time.Time
+	jitter     time.Duration
+	sleepCalls []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(duration time.Duration) {
+	c.sleepCalls = append(c.sleepCalls, duration)
+	c.now = c.now.Add(duration)
+}
+
+func (c *fakeClock) Jitter(max time.Duration) time.Duration {
+	return c.jitter
+}
+
+func TestSignedCallUsesClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2012, 8, 21, 17, 29, 18, 0, time.UTC)}
+	duo, mockHttp, _ := getMockClients([]http.Response{okResp})
+	duo.clock = clock
+
+	values := url.Values{}
+	values.Set("realname", "First Last")
+	values.Set("username", "root")
+	duo.SignedCall("POST", "/accounts/v1/account/list", values)
+
+	req := mockHttp.actualRequests[0]
+	if date := req.Header.Get("Date"); date != "Tue, 21 Aug 2012 17:29:18 +0000" {
+		t.Errorf("Unexpected Date header %q", date)
+	}
+	expected := sign(duo.ikey, duo.skey, "POST", duo.host, "/accounts/v1/account/list",
+		"Tue, 21 Aug 2012 17:29:18 +0000", values)
+	if auth := req.Header.Get("Authorization"); auth != expected {
+		t.Errorf("Unexpected Authorization header %q", auth)
+	}
+}
+
+func TestJSONSignedCall(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2012, 8, 21, 17, 29, 18, 0, time.UTC)}
+	duo, mockHttp, _ := getMockClients([]http.Response{rateLimitResp, okResp})
+	duo.clock = clock
+
+	body := map[string]string{"policy_name": "Test"}
+	duo.JSONSignedCall("post", "/admin/v2/policies", nil, body)
+
+	if len(mockHttp.actualRequests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(mockHttp.actualRequests))
+	}
+	// The retry must resend the whole body.
+	req := mockHttp.actualRequests[1]
+	sent, _ := ioutil.ReadAll(req.Body)
+	if string(sent) != `{"policy_name":"Test"}` || req.ContentLength != int64(len(sent)) {
+		t.Errorf("Unexpected body %q", sent)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Unexpected Content-Type header %q", ct)
+	}
+	// The signed date travels in X-Duo-Date, not Date.
+	if date := req.Header.Get("X-Duo-Date"); date != "Tue, 21 Aug 2012 17:29:18 +0000" {
+		t.Errorf("Unexpected X-Duo-Date header %q", date)
+	}
+	if date := req.Header.Get("Date"); date != "" {
+		t.Errorf("Unexpected Date header %q", date)
+	}
+	bodyHash := sha512.Sum512(sent)
+	canon := "Tue, 21 Aug 2012 17:29:18 +0000\nPOST\n" + strings.ToLower(duo.host) + "\n/admin/v2/policies\n\n" +
+		hex.EncodeToString(bodyHash[:]) + "\n" + sha512Empty
+	expected := authorizationV5(duo.ikey, duo.skey, canon)
+	if auth := req.Header.Get("Authorization"); auth != expected {
+		t.Errorf("Unexpected Authorization header %q", auth)
+	}
+}
+
+func TestJSONSignedCallQuery(t *testing.T) {
+	duo, mockHttp, _ := getMockClients([]http.Response{okResp})
+
+	values := url.Values{}
+	values.Set("user_id", "DU123")
+	duo.JSONSignedCall("GET", "/admin/v2/policies/calculate", values, nil)
+
+	req := mockHttp.actualRequests[0]
+	if req.URL.RawQuery != "user_id=DU123" || req.Body != nil {
+		t.Errorf("Unexpected request %s with body %v", req.URL, req.Body)
+	}
+	expected := signV5(duo.ikey, duo.skey, "GET", duo.host, "/admin/v2/policies/calculate",
+		req.Header.Get("X-Duo-Date"), values, nil)
+	if auth := req.Header.Get("Authorization"); auth != expected {
+		t.Errorf("Unexpected Authorization header %q", auth)
+	}
+}
+
+func TestRateLimitBackoffUsesClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1346172816, 0), jitter: 250 * time.Millisecond}
+	responses := []http.Response{rateLimitResp, rateLimitResp, rateLimitResp, okResp}
+	duo, mockHttp, _ := getMockClients(responses)
+	duo.clock = clock
+	duo.sleepSvc = timeSleepService{clock: clock}
+
+	resp, _, _ := duo.Call("GET", "/v9/hello/world", url.Values{})
+	if resp.StatusCode != 200 {
+		t.Fatalf("Expected final status 200, got %d", resp.StatusCode)
+	}
+	if len(mockHttp.actualRequests) != 4 {
+		t.Fatalf("Expected 4 requests, got %d", len(mockHttp.actualRequests))
+	}
+	expected := []time.Duration{1250 * time.Millisecond, 2250 * time.Millisecond, 4250 * time.Millisecond}
+	if len(clock.sleepCalls) != len(expected) {
+		t.Fatalf("Expected %d sleeps, got %d", len(expected), len(clock.sleepCalls))
+	}
+	for i := range expected {
+		if clock.sleepCalls[i] != expected[i] {
+			t.Errorf("Sleep %d lasted %v instead of %v", i, clock.sleepCalls[i], expected[i])
+		}
+	}
+	if elapsed := clock.now.Sub(time.Unix(1346172816, 0)); elapsed != 7750*time.Millisecond {
+		t.Errorf("Expected 7.75s to elapse, got %v", elapsed)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	clock := &fakeClock{}
+	duo := NewDuoApi("ABC", "123", "api-XXXXXXX.duosecurity.com", "go-client", SetClock(clock))
+	if duo.Clock() != clock {
+		t.Fatal("SetClock failed to configure the Duo API clock")
+	}
+	if _, ok := (&DuoApi{}).Clock().(systemClock); !ok {
+		t.Fatal("Expected the system clock by default")
+	}
+
+	duo = NewDuoApi("ABC", "123", "api-XXXXXXX.duosecurity.com", "go-client", SetClock(nil))
+	if _, ok := duo.clock.(systemClock); !ok {
+		t.Fatal("Expected SetClock(nil) to keep the system clock")
+	}
+	if _, ok := duo.sleepSvc.(timeSleepService).clock.(systemClock); !ok {
+		t.Fatal("Expected SetClock(nil) to keep the system clock for retries")
+	}
+}
@@ -1,13 +1,15 @@
 package duoapi
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha512"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
-	"io"
+	"encoding/json"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
@@ -69,6 +71,72 @@ func sign(ikey string,
 	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
 }
 
+// Sign returns the value of the Authorization header for a request signed
+// with the integration key ikey and secret key skey.  The method, host, uri,
+// date and params are canonicalized as Duo's servers expect, so Sign may also
+// be used to verify the signature of a request.
+func Sign(ikey string,
+	skey string,
+	method string,
+	host string,
+	uri string,
+	date string,
+	params url.Values) string {
+	return sign(ikey, skey, method, host, uri, date, params)
+}
+
+// canonicalizeV5 builds the string to sign for Duo's version 5 signatures,
+// which also cover the request body and any X-Duo headers.  No X-Duo headers
+// are signed here, so their hash is that of the empty string.
+func canonicalizeV5(method string,
+	host string,
+	uri string,
+	params url.Values,
+	body []byte,
+	date string) string {
+	bodyHash := sha512.Sum512(body)
+	headersHash := sha512.Sum512(nil)
+	var canon [7]string
+	canon[0] = date
+	canon[1] = strings.ToUpper(method)
+	canon[2] = strings.ToLower(host)
+	canon[3] = uri
+	canon[4] = canonParams(params)
+	canon[5] = hex.EncodeToString(bodyHash[:])
+	canon[6] = hex.EncodeToString(headersHash[:])
+	return strings.Join(canon[:], "\n")
+}
+
+func signV5(ikey string,
+	skey string,
+	method string,
+	host string,
+	uri string,
+	date string,
+	params url.Values,
+	body []byte) string {
+	canon := canonicalizeV5(method, host, uri, params, body, date)
+	mac := hmac.New(sha512.New, []byte(skey))
+	mac.Write([]byte(canon))
+	sig := hex.EncodeToString(mac.Sum(nil))
+	auth := ikey + ":" + sig
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+}
+
+// SignV5 is Sign for requests with JSON bodies, such as those made by
+// JSONSignedCall.  params holds the query parameters, and body the exact
+// bytes sent.
+func SignV5(ikey string,
+	skey string,
+	method string,
+	host string,
+	uri string,
+	date string,
+	params url.Values,
+	body []byte) string {
+	return signV5(ikey, skey, method, host, uri, date, params, body)
+}
+
 type DuoApi struct {
 	ikey       string
 	skey       string
@@ -77,6 +145,7 @@ type DuoApi struct {
 	apiClient  httpClient
 	authClient httpClient
 	sleepSvc   sleepService
+	clock      Clock
 }
 
 type httpClient interface {
@@ -85,17 +154,60 @@ type httpClient interface {
 type sleepService interface {
 	Sleep(duration time.Duration)
 }
-type timeSleepService struct{}
+type timeSleepService struct {
+	clock Clock
+}
 
 func (svc timeSleepService) Sleep(duration time.Duration) {
-	time.Sleep(duration + (time.Duration(rand.Intn(1000)) * time.Millisecond))
+	svc.clock.Sleep(duration + svc.clock.Jitter(time.Second))
+}
+
+// Clock is the source of time and randomness used by DuoApi.  It provides the
+// Date header of signed requests, the sleeps between rate limited retries and
+// the random jitter added to those sleeps.  Supply an implementation with
+// SetClock() to control these, for example in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Sleep pauses the current goroutine for at least duration.
+	Sleep(duration time.Duration)
+	// Jitter returns a random duration in the range [0, max).
+	Jitter(max time.Duration) time.Duration
+}
+
+// SystemClock returns the Clock DuoApi uses unless SetClock() is given: the
+// real time, time.Sleep and math/rand jitter.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+func (systemClock) Jitter(max time.Duration) time.Duration {
+	ms := int(max / time.Millisecond)
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Intn(ms)) * time.Millisecond
 }
 
 type apiOptions struct {
-	timeout   time.Duration
-	insecure  bool
-	proxy     func(*http.Request) (*url.URL, error)
-	transport func(*http.Transport)
+	timeout     time.Duration
+	insecure    bool
+	proxy       func(*http.Request) (*url.URL, error)
+	proxyAuth   *url.Userinfo
+	proxyBypass proxyBypass
+	socks5      *socks5Dialer
+	transport   func(*http.Transport)
+	clock       Clock
 }
 
 // Optional parameter for NewDuoApi, used to configure timeouts on API calls.
@@ -121,6 +233,46 @@ func SetProxy(proxy func(*http.Request) (*url.URL, error)) func(*apiOptions) {
 	}
 }
 
+// Optional parameter for NewDuoApi, used to supply credentials to the HTTP
+// Connect proxy server configured with SetProxy.  The credentials are sent
+// in the Proxy-Authorization header of each CONNECT request.
+func SetProxyAuth(username string, password string) func(*apiOptions) {
+	return func(opts *apiOptions) {
+		opts.proxyAuth = url.UserPassword(username, password)
+	}
+}
+
+// Optional parameter for NewDuoApi, used to route all outbound communications
+// through a SOCKS5 proxy server at address (host:port).  If username is not
+// empty, the username and password are used to authenticate to the proxy.
+// A SOCKS5 proxy takes precedence over any HTTP Connect proxy.
+func SetSOCKS5Proxy(address string, username string, password string) func(*apiOptions) {
+	return func(opts *apiOptions) {
+		opts.socks5 = newSOCKS5Dialer(address, username, password, nil)
+	}
+}
+
+// Optional parameter for NewDuoApi, used to list hosts which should be
+// contacted directly rather than through the configured proxy.  An entry of
+// "*" matches every host, an entry beginning with "." or "*." matches a domain
+// and all of its subdomains, and any other entry must match the host exactly.
+func SetProxyBypass(hosts ...string) func(*apiOptions) {
+	return func(opts *apiOptions) {
+		opts.proxyBypass = append(opts.proxyBypass, hosts...)
+	}
+}
+
+// Optional parameter for NewDuoApi, used to replace the system clock used to
+// date signed requests and to time and jitter retries.  A nil clock is
+// ignored.
+func SetClock(clock Clock) func(*apiOptions) {
+	return func(opts *apiOptions) {
+		if clock != nil {
+			opts.clock = clock
+		}
+	}
+}
+
 // SetTransport enables additional control over the HTTP transport used to connect to the Duo API.
 func SetTransport(transport func(*http.Transport)) func(*apiOptions) {
 	return func(opts *apiOptions) {
@@ -136,7 +288,9 @@ func SetTransport(transport func(*http.Transport)) func(*apiOptions) {
 //           the web request to Duo.  Information about the client will be
 //           appended to the userAgent.
 // options are optional parameters.  Use SetTimeout() to specify a timeout value
-//         for Rest API calls.  Use SetProxy() to specify proxy settings for Duo API calls.
+//         for Rest API calls.  Use SetProxy() to specify proxy settings for Duo API calls,
+//         SetProxyAuth() to authenticate to that proxy, SetSOCKS5Proxy() to use a SOCKS5
+//         proxy instead and SetProxyBypass() to exempt hosts from proxying.
 //
 // Example: duoapi.NewDuoApi(ikey,skey,host,userAgent,duoapi.SetTimeout(10*time.Second))
 func NewDuoApi(ikey string,
@@ -144,7 +298,7 @@ func NewDuoApi(ikey string,
 	host string,
 	userAgent string,
 	options ...func(*apiOptions)) *DuoApi {
-	opts := apiOptions{proxy: http.ProxyFromEnvironment}
+	opts := apiOptions{proxy: http.ProxyFromEnvironment, clock: SystemClock()}
 	for _, o := range options {
 		o(&opts)
 	}
@@ -154,12 +308,25 @@ func NewDuoApi(ikey string,
 	certPool.AppendCertsFromPEM([]byte(duoPinnedCert))
 
 	tr := &http.Transport{
-		Proxy: opts.proxy,
+		Proxy: opts.proxyBypass.wrap(opts.proxy),
 		TLSClientConfig: &tls.Config{
 			RootCAs:            certPool,
 			InsecureSkipVerify: opts.insecure,
 		},
 	}
+	if opts.proxyAuth != nil {
+		password, _ := opts.proxyAuth.Password()
+		tr.ProxyConnectHeader = http.Header{
+			"Proxy-Authorization": []string{proxyAuthorization(opts.proxyAuth.Username(), password)},
+		}
+	}
+	if opts.socks5 != nil {
+		// The TLS handshake, and so certificate pinning, happens on top of
+		// the tunnelled connection.
+		opts.socks5.bypass = opts.proxyBypass
+		tr.Proxy = nil
+		tr.DialContext = opts.socks5.DialContext
+	}
 	if opts.transport != nil {
 		opts.transport(tr)
 	}
@@ -181,10 +348,19 @@ func NewDuoApi(ikey string,
 		authClient: &http.Client{
 			Transport: tr,
 		},
-		sleepSvc: timeSleepService{},
+		sleepSvc: timeSleepService{clock: opts.clock},
+		clock:    opts.clock,
 	}
 }
 
+// Clock returns the Clock used by this DuoApi.
+func (duoapi *DuoApi) Clock() Clock {
+	if duoapi.clock == nil {
+		return SystemClock()
+	}
+	return duoapi.clock
+}
+
 type requestOptions struct {
 	timeout bool
 }
@@ -261,7 +437,7 @@ func (duoapi *DuoApi) SignedCall(method string,
 	params url.Values,
 	options ...DuoApiOption) (*http.Response, []byte, error) {
 
-	now := time.Now().UTC().Format(time.RFC1123Z)
+	now := duoapi.Clock().Now().UTC().Format(time.RFC1123Z)
 	auth_sig := sign(duoapi.ikey, duoapi.skey, method, duoapi.host, uri, now, params)
 
 	url := url.URL{
@@ -279,10 +455,58 @@ func (duoapi *DuoApi) SignedCall(method string,
 	headers["User-Agent"] = duoapi.userAgent
 	headers["Authorization"] = auth_sig
 	headers["Date"] = now
-	var requestBody io.ReadCloser = nil
+	var requestBody []byte
 	if method == "POST" || method == "PUT" {
 		headers["Content-Type"] = "application/x-www-form-urlencoded"
-		requestBody = ioutil.NopCloser(strings.NewReader(params.Encode()))
+		requestBody = []byte(params.Encode())
+	}
+
+	return duoapi.makeRetryableHttpCall(method, url, headers, requestBody, options...)
+}
+
+// Make a signed Duo Rest API call with a JSON body, as newer endpoints such
+// as Admin API v2 policies expect.  The request is signed with Duo's version
+// 5 signatures.
+// method is GET, POST, PUT or DELETE
+// uri is the URI of the Duo Rest call
+// params HTTP query parameters to include in the call.
+// body is marshalled to JSON and sent as the body of POST and PUT calls.
+// options Optional parameters.  Use UseTimeout to toggle whether the
+//         Duo Rest API call should timeout or not.
+//
+// Example: duo.JSONSignedCall("PUT", "/admin/v2/policies/"+key, nil, policy, duoapi.UseTimeout)
+func (duoapi *DuoApi) JSONSignedCall(method string,
+	uri string,
+	params url.Values,
+	body interface{},
+	options ...DuoApiOption) (*http.Response, []byte, error) {
+
+	method = strings.ToUpper(method)
+	var requestBody []byte
+	if body != nil && (method == "POST" || method == "PUT") {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		requestBody = encoded
+	}
+
+	now := duoapi.Clock().Now().UTC().Format(time.RFC1123Z)
+	auth_sig := signV5(duoapi.ikey, duoapi.skey, method, duoapi.host, uri, now, params, requestBody)
+
+	url := url.URL{
+		Scheme:   "https",
+		Host:     duoapi.host,
+		Path:     uri,
+		RawQuery: params.Encode(),
+	}
+
+	headers := make(map[string]string)
+	headers["User-Agent"] = duoapi.userAgent
+	headers["Authorization"] = auth_sig
+	headers["X-Duo-Date"] = now
+	if requestBody != nil {
+		headers["Content-Type"] = "application/json"
 	}
 
 	return duoapi.makeRetryableHttpCall(method, url, headers, requestBody, options...)
@@ -292,7 +516,7 @@ func (duoapi *DuoApi) makeRetryableHttpCall(
 	method string,
 	url url.URL,
 	headers map[string]string,
-	body io.ReadCloser,
+	body []byte,
 	options ...DuoApiOption) (*http.Response, []byte, error) {
 
 	opts := duoapi.buildOptions(options...)
@@ -315,19 +539,21 @@ func (duoapi *DuoApi) makeRetryableHttpCall(
 			}
 		}
 		if body != nil {
-			request.Body = body
+			// A fresh reader per attempt, so retries resend the body.
+			request.Body = ioutil.NopCloser(bytes.NewReader(body))
+			request.ContentLength = int64(len(body))
 		}
 
 		resp, err := client.Do(request)
-		var body []byte
+		var respBody []byte
 		if err != nil {
-			return resp, body, err
+			return resp, respBody, err
 		}
 
 		if backoffMs > maxBackoffMS || resp.StatusCode != rateLimitHttpCode {
-			body, err = ioutil.ReadAll(resp.Body)
+			respBody, err = ioutil.ReadAll(resp.Body)
 			resp.Body.Close()
-			return resp, body, err
+			return resp, respBody, err
 		}
 
 		resp.Body.Close()
@@ -0,0 +1,247 @@
+package duotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// Page size limits of the Admin API list endpoints.
+const (
+	defaultPageSize   = 100
+	maxPageSize       = 500
+	maxUsersPageSize  = 300
+	maxGroupsPageSize = 100
+	maxAdminsPageSize = 300
+)
+
+// Limits on the number of items in a single request.
+const (
+	maxListParamSize   = 100
+	maxBulkCreateUsers = 100
+)
+
+// AdminServer is a fake Duo Admin API server which keeps users, groups,
+// phones, hardware tokens, U2F tokens, WebAuthn credentials, bypass codes,
+// administrators, administrative units, integrations and policies in
+// memory.  It is safe for concurrent use.
+type AdminServer struct {
+	*httptest.Server
+
+	// IKey and SKey are the integration and secret keys requests must be
+	// signed with.  Requests may also be signed with the keys of an
+	// "adminapi" integration stored by the server.
+	IKey string
+	SKey string
+
+	mu         sync.Mutex
+	ids        idGenerator
+	codes      uint64
+	users      []*admin.User
+	groups     []*admin.Group
+	phones     []*admin.Phone
+	tokens     []*admin.Token
+	u2fTokens  []*u2fToken
+	webAuthn   []*webAuthnCredential
+	bypass     []*BypassCode
+	enrolls    []Enrollment
+	pushes     []*verificationPush
+	admins     []*admin.Administrator
+	adminLinks map[string]*admin.AdminActivationLink
+	adminActs  []*admin.AdminActivation
+	adminUnits []*admin.AdminUnit
+	integs     []*admin.Integration
+	policies   []*storedPolicy
+	userGroups map[string][]string
+	userPhones map[string][]string
+	userTokens map[string][]string
+}
+
+// NewAdminServer starts a fake Admin API server which accepts requests signed
+// with ikey and skey.  Call Close when finished with it.
+func NewAdminServer(ikey, skey string) *AdminServer {
+	s := &AdminServer{
+		IKey:       ikey,
+		SKey:       skey,
+		userGroups: map[string][]string{},
+		userPhones: map[string][]string{},
+		userTokens: map[string][]string{},
+		adminLinks: map[string]*admin.AdminActivationLink{},
+	}
+	s.policies = []*storedPolicy{{
+		policy: admin.Policy{
+			PolicyKey:      s.ids.id("PO"),
+			PolicyName:     "Global Policy",
+			IsGlobalPolicy: true,
+			Sections:       map[string]admin.PolicySection{},
+		},
+		groups: map[string][]string{},
+	}}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Host returns the host and port to pass to duoapi.NewDuoApi.
+func (s *AdminServer) Host() string {
+	return strings.TrimPrefix(s.URL, "https://")
+}
+
+// Client returns an admin API client which talks to the fake server.  The
+// server's self-signed certificate is not verified.
+func (s *AdminServer) Client() *admin.Client {
+	base := duoapi.NewDuoApi(s.IKey, s.SKey, s.Host(), "duotest", duoapi.SetInsecure())
+	return admin.New(*base)
+}
+
+func addID(ids []string, id string) []string {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+func removeID(ids []string, id string) ([]string, bool) {
+	for i, existing := range ids {
+		if existing == id {
+			return append(ids[:i:i], ids[i+1:]...), true
+		}
+	}
+	return ids, false
+}
+
+// JSON rendering, in the format used by Duo's servers
+
+func nullableString(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func nullableUint(n *uint64) interface{} {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+// HTTP handling
+
+// adminHandler handles a request given the remaining path segments after the
+// API version, returning a FAIL response on error.
+type adminHandler func(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError
+
+func (s *AdminServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	params, err := requestParams(r)
+	if err != nil {
+		writeError(w, invalidParam(err.Error()))
+		return
+	}
+	ikey, skey := s.IKey, s.SKey
+	if user, _, ok := r.BasicAuth(); ok && user != s.IKey {
+		s.mu.Lock()
+		if i := s.findIntegration(user); i != nil && i.Type == "adminapi" {
+			ikey, skey = i.IntegrationKey, i.SecretKey
+		}
+		s.mu.Unlock()
+	}
+	if apiErr := verifySignature(r, ikey, skey, params); apiErr != nil {
+		writeError(w, apiErr)
+		return
+	}
+
+	path := splitPath(r.URL.Path)
+	if len(path) < 3 || path[0] != "admin" {
+		writeError(w, notFound())
+		return
+	}
+
+	var handler adminHandler
+	switch path[1] + "/" + path[2] {
+	case "v1/users":
+		handler = s.handleUsers
+	case "v1/groups":
+		handler = s.handleGroups
+	case "v2/groups":
+		handler = s.handleGroupsV2
+	case "v1/phones":
+		handler = s.handlePhones
+	case "v1/tokens":
+		handler = s.handleTokens
+	case "v1/u2ftokens":
+		handler = s.handleU2FTokens
+	case "v1/webauthncredentials":
+		handler = s.handleWebAuthnCredentials
+	case "v1/bypass_codes":
+		handler = s.handleBypassCodes
+	case "v1/admins":
+		handler = s.handleAdmins
+	case "v1/administrative_units":
+		handler = s.handleAdminUnits
+	case "v1/integrations":
+		handler = s.handleIntegrations
+	case "v2/policies":
+		handler = s.handlePolicies
+	default:
+		writeError(w, notFound())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if apiErr := handler(w, r, params, path[3:]); apiErr != nil {
+		writeError(w, apiErr)
+	}
+}
+
+// writePage writes the page of items selected by params.
+func writePage(w http.ResponseWriter, params url.Values, items []interface{}, maxLimit int) *apiError {
+	start, end, meta, err := page(params, len(items), defaultPageSize, maxLimit)
+	if err != nil {
+		return err
+	}
+	response := items[start:end]
+	if response == nil {
+		response = []interface{}{}
+	}
+	writeList(w, response, meta)
+	return nil
+}
+
+// listParam parses a JSON list parameter such as username_list.  It returns
+// nil if the parameter is absent.
+func listParam(params url.Values, name string) ([]string, *apiError) {
+	value := params.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err != nil || len(list) > maxListParamSize {
+		return nil, invalidParam(name)
+	}
+	if list == nil {
+		list = []string{}
+	}
+	return list, nil
+}
+
+// matchesAny reports whether any of values equals one of list, ignoring
+// case.
+func matchesAny(list []string, values ...string) bool {
+	for _, v := range values {
+		for _, item := range list {
+			if strings.EqualFold(item, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
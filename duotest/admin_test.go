@@ -0,0 +1,22 @@
+package duotest
+
+import (
+	"testing"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerVerifiesSignatures(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+
+	base := duoapi.NewDuoApi("eyekey", "wrongkey", srv.Host(), "duotest", duoapi.SetInsecure())
+	result, err := admin.New(*base).GetUsers()
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUsers call %v", err)
+	}
+	if result.Stat != "FAIL" || *result.Code != CodeInvalidSignature {
+		t.Errorf("Expected an invalid signature failure, got %+v", result.StatResult)
+	}
+}
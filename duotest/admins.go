@@ -0,0 +1,388 @@
+package duotest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// Administrator activation link lifetimes, in days.
+const (
+	defaultAdminActivationDays = 7
+	maxAdminActivationDays     = 31
+)
+
+// AddAdmin stores a copy of administrator, assigning it an admin ID if it
+// has none, and returns the stored administrator.  Its AdminUnits are
+// ignored; assign it to units through the API instead.
+func (s *AdminServer) AddAdmin(administrator admin.Administrator) admin.Administrator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	administrator.AdminUnits = nil
+	if administrator.AdminID == "" {
+		administrator.AdminID = s.ids.id("DE")
+	}
+	if administrator.Role == "" {
+		administrator.Role = admin.AdminRoleOwner
+	}
+	if administrator.Status == "" {
+		administrator.Status = admin.AdminStatusActive
+	}
+	if administrator.Created == 0 {
+		administrator.Created = uint64(time.Now().Unix())
+	}
+	s.admins = append(s.admins, &administrator)
+	return administrator
+}
+
+// Admins returns copies of the stored administrators, in creation order.
+func (s *AdminServer) Admins() []admin.Administrator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	admins := make([]admin.Administrator, 0, len(s.admins))
+	for _, a := range s.admins {
+		admins = append(admins, *a)
+	}
+	return admins
+}
+
+func (s *AdminServer) findAdmin(adminID string) *admin.Administrator {
+	for _, a := range s.admins {
+		if a.AdminID == adminID {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *AdminServer) adminJSON(a *admin.Administrator) map[string]interface{} {
+	units := []string{}
+	for _, u := range s.adminUnits {
+		if matchesAny(u.Admins, a.AdminID) {
+			units = append(units, u.AdminUnitID)
+		}
+	}
+	var hardToken interface{}
+	if a.HardToken != nil {
+		hardToken = tokenJSON(a.HardToken, nil)
+	}
+	return map[string]interface{}{
+		"admin_id":                  a.AdminID,
+		"admin_units":               units,
+		"created":                   a.Created,
+		"email":                     a.Email,
+		"hardtoken":                 hardToken,
+		"last_login":                nullableUint(a.LastLogin),
+		"name":                      a.Name,
+		"password_change_required":  a.PasswordChangeRequired,
+		"phone":                     a.Phone,
+		"restricted_by_admin_units": a.RestrictedByAdminUnits,
+		"role":                      a.Role,
+		"status":                    a.Status,
+		"webauthncredentials":       []interface{}{},
+	}
+}
+
+func adminActivationLinkJSON(l *admin.AdminActivationLink) map[string]interface{} {
+	return map[string]interface{}{
+		"code":       l.Code,
+		"email":      l.Email,
+		"link":       l.Link,
+		"valid_days": l.ValidDays,
+	}
+}
+
+func (s *AdminServer) handleAdmins(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var admins []interface{}
+			for _, a := range s.admins {
+				admins = append(admins, s.adminJSON(a))
+			}
+			return writePage(w, params, admins, maxAdminsPageSize)
+		case http.MethodPost:
+			return s.createAdmin(w, params)
+		}
+		return methodNotAllowed()
+	}
+
+	if path[0] == "activations" {
+		return s.handleAdminActivations(w, r, params, path[1:])
+	}
+
+	administrator := s.findAdmin(path[0])
+	if administrator == nil {
+		return notFound()
+	}
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, s.adminJSON(administrator))
+			return nil
+		case http.MethodPost:
+			return s.modifyAdmin(w, administrator, params)
+		case http.MethodDelete:
+			for i, a := range s.admins {
+				if a == administrator {
+					s.admins = append(s.admins[:i:i], s.admins[i+1:]...)
+					break
+				}
+			}
+			delete(s.adminLinks, administrator.AdminID)
+			for _, u := range s.adminUnits {
+				u.Admins, _ = removeID(u.Admins, administrator.AdminID)
+			}
+			writeJSON(w, "")
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	switch strings.Join(path[1:], "/") {
+	case "reset":
+		if r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		writeJSON(w, "")
+		return nil
+	case "clear_inactivity":
+		if r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		if administrator.Status == admin.AdminStatusExpired {
+			administrator.Status = admin.AdminStatusActive
+		}
+		writeJSON(w, "")
+		return nil
+	case "activation_link":
+		switch r.Method {
+		case http.MethodPost:
+			if administrator.Status != admin.AdminStatusPendingActivation {
+				return invalidParam("admin_id")
+			}
+			link := s.createAdminActivationLink(administrator, defaultAdminActivationDays)
+			writeJSON(w, adminActivationLinkJSON(link))
+			return nil
+		case http.MethodDelete:
+			if s.adminLinks[administrator.AdminID] == nil {
+				return notFound()
+			}
+			delete(s.adminLinks, administrator.AdminID)
+			writeJSON(w, "")
+			return nil
+		}
+		return methodNotAllowed()
+	case "activation_link/email":
+		if r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		link := s.adminLinks[administrator.AdminID]
+		if link == nil {
+			return notFound()
+		}
+		writeJSON(w, adminActivationLinkJSON(link))
+		return nil
+	}
+	return notFound()
+}
+
+func adminActivationJSON(a *admin.AdminActivation) map[string]interface{} {
+	return map[string]interface{}{
+		"admin_activation_id": a.AdminActivationID,
+		"email":               a.Email,
+		"expiration":          a.Expiration,
+		"link":                a.Link,
+		"role":                a.Role,
+		"valid_days":          a.ValidDays,
+	}
+}
+
+func (s *AdminServer) handleAdminActivations(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var activations []interface{}
+			for _, a := range s.adminActs {
+				activations = append(activations, adminActivationJSON(a))
+			}
+			return writePage(w, params, activations, maxAdminsPageSize)
+		case http.MethodPost:
+			return s.createAdminActivation(w, params)
+		}
+		return methodNotAllowed()
+	}
+	if len(path) != 1 {
+		return notFound()
+	}
+	if r.Method != http.MethodDelete {
+		return methodNotAllowed()
+	}
+	for i, a := range s.adminActs {
+		if a.AdminActivationID == path[0] {
+			s.adminActs = append(s.adminActs[:i:i], s.adminActs[i+1:]...)
+			writeJSON(w, "")
+			return nil
+		}
+	}
+	return notFound()
+}
+
+func (s *AdminServer) createAdminActivation(w http.ResponseWriter, params url.Values) *apiError {
+	email := params.Get("email")
+	if email == "" {
+		return missingParam("email")
+	}
+	for _, a := range s.admins {
+		if strings.EqualFold(a.Email, email) {
+			return duplicate("email")
+		}
+	}
+	for _, a := range s.adminActs {
+		if strings.EqualFold(a.Email, email) {
+			return duplicate("email")
+		}
+	}
+	role := admin.AdminRoleOwner
+	if values, ok := params["admin_role"]; ok {
+		role = admin.AdminRole(values[0])
+		if !role.Valid() {
+			return invalidParam("admin_role")
+		}
+	}
+	validDays := defaultAdminActivationDays
+	if v := params.Get("valid_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > maxAdminActivationDays {
+			return invalidParam("valid_days")
+		}
+		validDays = days
+	}
+
+	code := s.ids.id("")
+	activation := &admin.AdminActivation{
+		AdminActivationID: s.ids.id("DE"),
+		Email:             email,
+		Expiration:        uint64(time.Now().AddDate(0, 0, validDays).Unix()),
+		Link:              s.URL + "/admins/activate?code=" + code,
+		Role:              role,
+		ValidDays:         validDays,
+	}
+	s.adminActs = append(s.adminActs, activation)
+	writeJSON(w, adminActivationJSON(activation))
+	return nil
+}
+
+func (s *AdminServer) createAdmin(w http.ResponseWriter, params url.Values) *apiError {
+	email, name := params.Get("email"), params.Get("name")
+	if email == "" {
+		return missingParam("email")
+	}
+	if name == "" {
+		return missingParam("name")
+	}
+	for _, a := range s.admins {
+		if strings.EqualFold(a.Email, email) {
+			return duplicate("email")
+		}
+	}
+	if _, ok := params["status"]; ok {
+		return invalidParam("status")
+	}
+	validDays := defaultAdminActivationDays
+	if v := params.Get("valid_days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > maxAdminActivationDays {
+			return invalidParam("valid_days")
+		}
+		validDays = days
+	}
+
+	administrator := &admin.Administrator{
+		AdminID: s.ids.id("DE"),
+		Created: uint64(time.Now().Unix()),
+		Email:   email,
+		Role:    admin.AdminRoleOwner,
+		Status:  admin.AdminStatusPendingActivation,
+	}
+	if err := applyAdminParams(administrator, params); err != nil {
+		return err
+	}
+	s.admins = append(s.admins, administrator)
+	if params.Get("send_email") == "1" {
+		s.createAdminActivationLink(administrator, validDays)
+	}
+	writeJSON(w, s.adminJSON(administrator))
+	return nil
+}
+
+func (s *AdminServer) modifyAdmin(w http.ResponseWriter, administrator *admin.Administrator, params url.Values) *apiError {
+	if _, ok := params["email"]; ok {
+		return invalidParam("email")
+	}
+	updated := *administrator
+	if err := applyAdminParams(&updated, params); err != nil {
+		return err
+	}
+	if values, ok := params["status"]; ok {
+		switch status := admin.AdminStatus(values[0]); status {
+		case admin.AdminStatusActive, admin.AdminStatusDisabled:
+			updated.Status = status
+		default:
+			return invalidParam("status")
+		}
+	}
+	*administrator = updated
+	writeJSON(w, s.adminJSON(administrator))
+	return nil
+}
+
+// applyAdminParams sets the fields of an administrator shared by create and
+// modify requests.
+func applyAdminParams(administrator *admin.Administrator, params url.Values) *apiError {
+	if values, ok := params["name"]; ok {
+		if values[0] == "" {
+			return invalidParam("name")
+		}
+		administrator.Name = values[0]
+	}
+	if values, ok := params["phone"]; ok {
+		administrator.Phone = values[0]
+	}
+	if values, ok := params["role"]; ok {
+		role := admin.AdminRole(values[0])
+		if !role.Valid() {
+			return invalidParam("role")
+		}
+		administrator.Role = role
+	}
+	for name, field := range map[string]*bool{
+		"restricted_by_admin_units": &administrator.RestrictedByAdminUnits,
+		"password_change_required":  &administrator.PasswordChangeRequired,
+	} {
+		if values, ok := params[name]; ok {
+			b, err := strconv.ParseBool(values[0])
+			if err != nil {
+				return invalidParam(name)
+			}
+			*field = b
+		}
+	}
+	return nil
+}
+
+func (s *AdminServer) createAdminActivationLink(administrator *admin.Administrator, validDays int) *admin.AdminActivationLink {
+	code := s.ids.id("")
+	link := &admin.AdminActivationLink{
+		Code:      code,
+		Email:     administrator.Email,
+		Link:      s.URL + "/admins/activate?code=" + code,
+		ValidDays: validDays,
+	}
+	s.adminLinks[administrator.AdminID] = link
+	return link
+}
@@ -0,0 +1,116 @@
+package duotest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerAdmins(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	owner := srv.AddAdmin(admin.Administrator{Email: "owner@example.com", Name: "Owner"})
+	expired := srv.AddAdmin(admin.Administrator{Email: "idle@example.com", Name: "Idle", Status: admin.AdminStatusExpired})
+	client := srv.Client()
+
+	created, err := client.CreateAdmin(admin.AdminUpdate{
+		Email:     admin.String("helpdesk@example.com"),
+		Name:      admin.String("Help Desk"),
+		Role:      admin.NewAdminRole(admin.AdminRoleHelpDesk),
+		SendEmail: true,
+	})
+	if err != nil || created.Stat != "OK" || created.Response.Status != admin.AdminStatusPendingActivation {
+		t.Fatalf("Unexpected CreateAdmin result %+v, %v", created, err)
+	}
+	helpDesk := created.Response
+	if dup, err := client.CreateAdmin(admin.AdminUpdate{Email: admin.String("OWNER@example.com"), Name: admin.String("Again")}); err != nil || *dup.Code != CodeDuplicate {
+		t.Errorf("Expected a duplicate error, got %+v, %v", dup, err)
+	}
+
+	sent, err := client.SendAdminActivationEmail(helpDesk.AdminID)
+	if err != nil || sent.Response.Email != "helpdesk@example.com" || sent.Response.ValidDays != 7 {
+		t.Errorf("Unexpected SendAdminActivationEmail result %+v, %v", sent, err)
+	}
+	if result, err := client.DeleteAdminActivationLink(helpDesk.AdminID); err != nil || result.Stat != "OK" {
+		t.Errorf("Unexpected DeleteAdminActivationLink result %+v, %v", result, err)
+	}
+	if missing, err := client.SendAdminActivationEmail(helpDesk.AdminID); err != nil || *missing.Code != CodeNotFound {
+		t.Errorf("Expected no activation link, got %+v, %v", missing, err)
+	}
+	link, err := client.CreateAdminActivationLink(helpDesk.AdminID)
+	if err != nil || !strings.Contains(link.Response.Link, link.Response.Code) {
+		t.Errorf("Unexpected CreateAdminActivationLink result %+v, %v", link, err)
+	}
+	if active, err := client.CreateAdminActivationLink(owner.AdminID); err != nil || *active.Code != CodeInvalidParams {
+		t.Errorf("Expected an active admin to have no activation link, got %+v, %v", active, err)
+	}
+
+	modified, err := client.ModifyAdmin(owner.AdminID, admin.AdminUpdate{Role: admin.NewAdminRole(admin.AdminRoleReadOnly), Status: admin.NewAdminStatus(admin.AdminStatusDisabled)})
+	if err != nil || modified.Response.Role != admin.AdminRoleReadOnly || modified.Response.Status != admin.AdminStatusDisabled {
+		t.Errorf("Unexpected ModifyAdmin result %+v, %v", modified, err)
+	}
+	if _, err := client.ClearAdminExpiration(expired.AdminID); err != nil {
+		t.Fatalf("Unexpected error from ClearAdminExpiration call %v", err)
+	}
+	if result, err := client.ResetAdminAuthAttempts(expired.AdminID); err != nil || result.Stat != "OK" {
+		t.Errorf("Unexpected ResetAdminAuthAttempts result %+v, %v", result, err)
+	}
+	fetched, err := client.GetAdmin(expired.AdminID)
+	if err != nil || fetched.Response.Status != admin.AdminStatusActive {
+		t.Errorf("Expected the expired admin to be active, got %+v, %v", fetched, err)
+	}
+
+	if result, err := client.DeleteAdmin(helpDesk.AdminID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteAdmin result %+v, %v", result, err)
+	}
+	admins, err := client.GetAdmins(admin.Limit(1))
+	if err != nil || len(admins.Response) != 1 {
+		t.Errorf("Unexpected GetAdmins result %+v, %v", admins, err)
+	}
+	if len(srv.Admins()) != 2 {
+		t.Errorf("Expected 2 admins, got %d", len(srv.Admins()))
+	}
+}
+
+func TestAdminServerAdminActivations(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	srv.AddAdmin(admin.Administrator{Email: "owner@example.com", Name: "Owner"})
+	client := srv.Client()
+
+	created, err := client.CreateAdminActivation(admin.AdminActivationCreate{
+		Email:     "helpdesk@example.com",
+		Role:      admin.NewAdminRole(admin.AdminRoleHelpDesk),
+		ValidDays: admin.Int(3),
+	})
+	if err != nil || created.Stat != "OK" || created.Response.Role != admin.AdminRoleHelpDesk || created.Response.ValidDays != 3 {
+		t.Fatalf("Unexpected CreateAdminActivation result %+v, %v", created, err)
+	}
+	if created.Response.Link == "" || created.Response.Expiration == 0 {
+		t.Errorf("Expected an activation link and expiration, got %+v", created.Response)
+	}
+	for _, email := range []string{"OWNER@example.com", "helpdesk@example.com"} {
+		if dup, err := client.CreateAdminActivation(admin.AdminActivationCreate{Email: email}); err != nil || *dup.Code != CodeDuplicate {
+			t.Errorf("Expected a duplicate error for %s, got %+v, %v", email, dup, err)
+		}
+	}
+	owner, err := client.CreateAdminActivation(admin.AdminActivationCreate{Email: "second@example.com"})
+	if err != nil || owner.Response.Role != admin.AdminRoleOwner || owner.Response.ValidDays != 7 {
+		t.Errorf("Unexpected default activation %+v, %v", owner, err)
+	}
+
+	activations, err := client.GetAdminActivations()
+	if err != nil || len(activations.Response) != 2 || activations.Response[0].Email != "helpdesk@example.com" {
+		t.Errorf("Unexpected GetAdminActivations result %+v, %v", activations, err)
+	}
+	if result, err := client.DeleteAdminActivation(created.Response.AdminActivationID); err != nil || result.Stat != "OK" {
+		t.Errorf("Unexpected DeleteAdminActivation result %+v, %v", result, err)
+	}
+	if missing, err := client.DeleteAdminActivation(created.Response.AdminActivationID); err != nil || *missing.Code != CodeNotFound {
+		t.Errorf("Expected a not found error, got %+v, %v", missing, err)
+	}
+	if activations, err = client.GetAdminActivations(); err != nil || len(activations.Response) != 1 {
+		t.Errorf("Unexpected GetAdminActivations result %+v, %v", activations, err)
+	}
+}
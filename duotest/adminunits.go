@@ -0,0 +1,193 @@
+package duotest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func adminUnitJSON(u *admin.AdminUnit) map[string]interface{} {
+	list := func(ids []string) []string {
+		if ids == nil {
+			return []string{}
+		}
+		return ids
+	}
+	return map[string]interface{}{
+		"admin_unit_id":            u.AdminUnitID,
+		"admins":                   list(u.Admins),
+		"description":              u.Description,
+		"groups":                   list(u.Groups),
+		"integrations":             list(u.Integrations),
+		"name":                     u.Name,
+		"restrict_by_groups":       u.RestrictByGroups,
+		"restrict_by_integrations": u.RestrictByIntegrations,
+	}
+}
+
+func (s *AdminServer) findAdminUnit(adminUnitID string) *admin.AdminUnit {
+	for _, u := range s.adminUnits {
+		if u.AdminUnitID == adminUnitID {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *AdminServer) handleAdminUnits(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var units []interface{}
+			for _, u := range s.adminUnits {
+				if id := params.Get("admin_id"); id != "" && !matchesAny(u.Admins, id) {
+					continue
+				}
+				if id := params.Get("group_id"); id != "" && !matchesAny(u.Groups, id) {
+					continue
+				}
+				if key := params.Get("integration_key"); key != "" && !matchesAny(u.Integrations, key) {
+					continue
+				}
+				units = append(units, adminUnitJSON(u))
+			}
+			return writePage(w, params, units, maxPageSize)
+		case http.MethodPost:
+			for _, name := range []string{"name", "description", "restrict_by_groups"} {
+				if params.Get(name) == "" {
+					return missingParam(name)
+				}
+			}
+			unit := &admin.AdminUnit{AdminUnitID: s.ids.id("DH")}
+			if err := s.applyAdminUnitParams(unit, params); err != nil {
+				return err
+			}
+			s.adminUnits = append(s.adminUnits, unit)
+			writeJSON(w, adminUnitJSON(unit))
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	unit := s.findAdminUnit(path[0])
+	if unit == nil {
+		return notFound()
+	}
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, adminUnitJSON(unit))
+			return nil
+		case http.MethodPost:
+			updated := *unit
+			if err := s.applyAdminUnitParams(&updated, params); err != nil {
+				return err
+			}
+			*unit = updated
+			writeJSON(w, adminUnitJSON(unit))
+			return nil
+		case http.MethodDelete:
+			for i, u := range s.adminUnits {
+				if u == unit {
+					s.adminUnits = append(s.adminUnits[:i:i], s.adminUnits[i+1:]...)
+					break
+				}
+			}
+			writeJSON(w, "")
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	if len(path) != 3 {
+		return notFound()
+	}
+	var ids *[]string
+	switch path[1] {
+	case "admin":
+		if s.findAdmin(path[2]) == nil {
+			return notFound()
+		}
+		ids = &unit.Admins
+	case "group":
+		if s.findGroup(path[2]) == nil {
+			return notFound()
+		}
+		ids = &unit.Groups
+	case "integration":
+		if s.findIntegration(path[2]) == nil {
+			return notFound()
+		}
+		ids = &unit.Integrations
+	default:
+		return notFound()
+	}
+	switch r.Method {
+	case http.MethodPost:
+		*ids = addID(*ids, path[2])
+	case http.MethodDelete:
+		var found bool
+		if *ids, found = removeID(*ids, path[2]); !found {
+			return notFound()
+		}
+	default:
+		return methodNotAllowed()
+	}
+	writeJSON(w, "")
+	return nil
+}
+
+// applyAdminUnitParams sets the fields of an administrative unit from a
+// create or modify request.  Listed admins, groups and integrations are
+// added to those already assigned.
+func (s *AdminServer) applyAdminUnitParams(unit *admin.AdminUnit, params url.Values) *apiError {
+	for _, name := range []string{"name", "description"} {
+		if values, ok := params[name]; ok && values[0] == "" {
+			return invalidParam(name)
+		}
+	}
+	if values, ok := params["name"]; ok {
+		unit.Name = values[0]
+	}
+	if values, ok := params["description"]; ok {
+		unit.Description = values[0]
+	}
+	for name, field := range map[string]*bool{
+		"restrict_by_groups":       &unit.RestrictByGroups,
+		"restrict_by_integrations": &unit.RestrictByIntegrations,
+	} {
+		if values, ok := params[name]; ok {
+			b, err := strconv.ParseBool(values[0])
+			if err != nil {
+				return invalidParam(name)
+			}
+			*field = b
+		}
+	}
+
+	admins := append([]string(nil), unit.Admins...)
+	for _, id := range params["admins"] {
+		if s.findAdmin(id) == nil {
+			return invalidParam("admins")
+		}
+		admins = addID(admins, id)
+	}
+	groups := append([]string(nil), unit.Groups...)
+	for _, id := range params["groups"] {
+		if s.findGroup(id) == nil {
+			return invalidParam("groups")
+		}
+		groups = addID(groups, id)
+	}
+	integrations := append([]string(nil), unit.Integrations...)
+	for _, key := range params["integrations"] {
+		if s.findIntegration(key) == nil {
+			return invalidParam("integrations")
+		}
+		integrations = addID(integrations, key)
+	}
+	unit.Admins, unit.Groups, unit.Integrations = admins, groups, integrations
+	return nil
+}
@@ -0,0 +1,89 @@
+package duotest
+
+import (
+	"reflect"
+	"testing"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerAdminUnits(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	lisa := srv.AddAdmin(admin.Administrator{Email: "ljones@example.com", Name: "Lisa Jones"})
+	rob := srv.AddAdmin(admin.Administrator{Email: "rsmith@example.com", Name: "Robert Smith"})
+	emeaGroup := srv.AddGroup(admin.Group{Name: "EMEA staff"})
+	vpn := srv.AddIntegration(admin.Integration{Name: "EMEA VPN", Type: "websdk"})
+	client := srv.Client()
+
+	created, err := client.CreateAdminUnit(admin.AdminUnitUpdate{
+		Name:             admin.String("EMEA"),
+		Description:      admin.String("EMEA administrators"),
+		RestrictByGroups: admin.Bool(true),
+		Admins:           []string{lisa.AdminID},
+	})
+	if err != nil || created.Stat != "OK" {
+		t.Fatalf("Unexpected CreateAdminUnit result %+v, %v", created, err)
+	}
+	unit := created.Response
+	if _, err := client.CreateAdminUnit(admin.AdminUnitUpdate{
+		Name:             admin.String("APAC"),
+		Description:      admin.String("APAC administrators"),
+		RestrictByGroups: admin.Bool(false),
+	}); err != nil {
+		t.Fatalf("Unexpected error from CreateAdminUnit call %v", err)
+	}
+
+	assignments := []struct {
+		call func(adminUnitID, id string) (*duoapi.StatResult, error)
+		id   string
+	}{
+		{client.AssignAdminToUnit, rob.AdminID},
+		{client.AssignGroupToUnit, emeaGroup.GroupID},
+		{client.AssignIntegrationToUnit, vpn.IntegrationKey},
+		{client.UnassignAdminFromUnit, lisa.AdminID},
+	}
+	for _, a := range assignments {
+		if result, err := a.call(unit.AdminUnitID, a.id); err != nil || result.Stat != "OK" {
+			t.Fatalf("Unexpected assignment result for %s %+v, %v", a.id, result, err)
+		}
+	}
+	if missing, err := client.AssignGroupToUnit(unit.AdminUnitID, "DGMISSING"); err != nil || *missing.Code != CodeNotFound {
+		t.Errorf("Expected a missing group to be rejected, got %+v, %v", missing, err)
+	}
+
+	fetched, err := client.GetAdminUnit(unit.AdminUnitID)
+	want := admin.AdminUnit{
+		AdminUnitID:      unit.AdminUnitID,
+		Name:             "EMEA",
+		Description:      "EMEA administrators",
+		RestrictByGroups: true,
+		Admins:           []string{rob.AdminID},
+		Groups:           []string{emeaGroup.GroupID},
+		Integrations:     []string{vpn.IntegrationKey},
+	}
+	if err != nil || !reflect.DeepEqual(fetched.Response, want) {
+		t.Errorf("Expected unit %+v, got %+v, %v", want, fetched.Response, err)
+	}
+	robAdmin, err := client.GetAdmin(rob.AdminID)
+	if err != nil || !reflect.DeepEqual(robAdmin.Response.AdminUnits, []string{unit.AdminUnitID}) {
+		t.Errorf("Expected the admin to list its unit, got %+v, %v", robAdmin, err)
+	}
+
+	filtered, err := client.GetAdminUnits(admin.AdminUnitsByGroup(emeaGroup.GroupID))
+	if err != nil || len(filtered.Response) != 1 || filtered.Response[0].AdminUnitID != unit.AdminUnitID {
+		t.Errorf("Unexpected filtered GetAdminUnits result %+v, %v", filtered, err)
+	}
+	modified, err := client.ModifyAdminUnit(unit.AdminUnitID, admin.AdminUnitUpdate{Name: admin.String("Europe")})
+	if err != nil || modified.Response.Name != "Europe" || len(modified.Response.Admins) != 1 {
+		t.Errorf("Unexpected ModifyAdminUnit result %+v, %v", modified, err)
+	}
+	if result, err := client.DeleteAdminUnit(unit.AdminUnitID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteAdminUnit result %+v, %v", result, err)
+	}
+	all, err := client.GetAdminUnits()
+	if err != nil || len(all.Response) != 1 {
+		t.Errorf("Unexpected GetAdminUnits result %+v, %v", all, err)
+	}
+}
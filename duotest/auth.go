@@ -0,0 +1,520 @@
+package duotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/authapi"
+)
+
+// Outcome is how a simulated user responds to authentication requests.
+type Outcome string
+
+const (
+	// OutcomeApprove approves pushes and phone calls.
+	OutcomeApprove Outcome = "approve"
+	// OutcomeDeny denies pushes and phone calls.
+	OutcomeDeny Outcome = "deny"
+	// OutcomeTimeout ignores pushes and phone calls until they time out.
+	OutcomeTimeout Outcome = "timeout"
+	// OutcomePasscode only has a passcode device, so pushes and phone calls
+	// are refused and the user must enter their passcode.
+	OutcomePasscode Outcome = "passcode"
+	// OutcomeFraud reports pushes and phone calls as fraudulent.
+	OutcomeFraud Outcome = "fraud"
+	// OutcomeNotEnrolled has not enrolled a device and must enroll.
+	OutcomeNotEnrolled Outcome = "not_enrolled"
+	// OutcomeBypass is in bypass mode and is allowed without a second factor.
+	OutcomeBypass Outcome = "bypass"
+)
+
+// Scenario configures how a simulated user behaves.
+type Scenario struct {
+	// Outcome is the user's response to pushes and phone calls.
+	Outcome Outcome
+	// After is how long the user takes to respond.
+	After time.Duration
+	// Passcode is the passcode the user enters when asked for one.  Passcode
+	// authentications succeed only if they match it.
+	Passcode string
+}
+
+// ParseScenario parses a scenario written as outcome[:after[:passcode]],
+// e.g. "approve:3s", "timeout:60s" or "passcode:0s:123456".
+func ParseScenario(s string) (Scenario, error) {
+	parts := strings.SplitN(s, ":", 3)
+	scenario := Scenario{Outcome: Outcome(parts[0])}
+	switch scenario.Outcome {
+	case OutcomeApprove, OutcomeDeny, OutcomeTimeout, OutcomePasscode,
+		OutcomeFraud, OutcomeNotEnrolled, OutcomeBypass:
+	default:
+		return Scenario{}, fmt.Errorf("duotest: unknown outcome %q", parts[0])
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		after, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return Scenario{}, fmt.Errorf("duotest: invalid delay %q: %v", parts[1], err)
+		}
+		scenario.After = after
+	}
+	if len(parts) > 2 {
+		scenario.Passcode = parts[2]
+	}
+	return scenario, nil
+}
+
+type authUser struct {
+	userID     string
+	username   string
+	scenario   Scenario
+	deviceID   string
+	activation string
+	expiration time.Time
+	enrolled   bool
+}
+
+type transaction struct {
+	ready     time.Time
+	result    string
+	status    string
+	statusMsg string
+}
+
+// AuthHandler is an http.Handler implementing a fake Duo Auth API whose users
+// respond to authentication requests according to their Scenario.  It is
+// safe for concurrent use.
+type AuthHandler struct {
+	// IKey and SKey are the integration and secret keys requests must be
+	// signed with.
+	IKey string
+	SKey string
+	// Clock, if set, replaces the system clock for timing user responses.
+	Clock duoapi.Clock
+	// DefaultScenario is used for users which have not been configured.
+	DefaultScenario Scenario
+	// Logo is served by /auth/v2/logo.  If it is nil, the logo is not found.
+	Logo []byte
+
+	mu           sync.Mutex
+	ids          idGenerator
+	users        []*authUser
+	transactions map[string]*transaction
+}
+
+// NewAuthHandler returns a fake Auth API handler which accepts requests
+// signed with ikey and skey.  Unknown users have not enrolled.
+func NewAuthHandler(ikey, skey string) *AuthHandler {
+	return &AuthHandler{
+		IKey:            ikey,
+		SKey:            skey,
+		DefaultScenario: Scenario{Outcome: OutcomeNotEnrolled},
+		transactions:    map[string]*transaction{},
+	}
+}
+
+// AuthServer is a fake Duo Auth API server.
+type AuthServer struct {
+	*httptest.Server
+	*AuthHandler
+}
+
+// NewAuthServer starts a fake Auth API server which accepts requests signed
+// with ikey and skey.  Call Close when finished with it.
+func NewAuthServer(ikey, skey string) *AuthServer {
+	h := NewAuthHandler(ikey, skey)
+	return &AuthServer{
+		Server:      httptest.NewTLSServer(h),
+		AuthHandler: h,
+	}
+}
+
+// Host returns the host and port to pass to duoapi.NewDuoApi.
+func (s *AuthServer) Host() string {
+	return strings.TrimPrefix(s.URL, "https://")
+}
+
+// Client returns an Auth API client which talks to the fake server.  The
+// server's self-signed certificate is not verified.
+func (s *AuthServer) Client() *authapi.AuthApi {
+	base := duoapi.NewDuoApi(s.IKey, s.SKey, s.Host(), "duotest", duoapi.SetInsecure())
+	return authapi.NewAuthApi(*base)
+}
+
+// SetUser configures the scenario of the user with username, creating the
+// user if necessary, and returns the user's ID.
+func (h *AuthHandler) SetUser(username string, scenario Scenario) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if user := h.findUser(url.Values{"username": {username}}); user != nil {
+		user.scenario = scenario
+		return user.userID
+	}
+	user := h.addUser(username)
+	user.scenario = scenario
+	return user.userID
+}
+
+// CompleteEnrollment simulates the user with userID activating the device
+// they enrolled with /auth/v2/enroll.  The user then approves pushes.
+func (h *AuthHandler) CompleteEnrollment(userID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	user := h.findUser(url.Values{"user_id": {userID}})
+	if user == nil || user.activation == "" {
+		return false
+	}
+	user.enrolled = true
+	user.scenario = Scenario{Outcome: OutcomeApprove}
+	return true
+}
+
+func (h *AuthHandler) now() time.Time {
+	if h.Clock != nil {
+		return h.Clock.Now()
+	}
+	return time.Now()
+}
+
+func (h *AuthHandler) sleep(duration time.Duration) {
+	if h.Clock != nil {
+		h.Clock.Sleep(duration)
+		return
+	}
+	time.Sleep(duration)
+}
+
+func (h *AuthHandler) addUser(username string) *authUser {
+	user := &authUser{
+		userID:   h.ids.id("DU"),
+		username: username,
+		deviceID: h.ids.id("DP"),
+		scenario: h.DefaultScenario,
+	}
+	h.users = append(h.users, user)
+	return user
+}
+
+// findUser returns the user identified by the user_id or username parameter.
+func (h *AuthHandler) findUser(params url.Values) *authUser {
+	userID := params.Get("user_id")
+	username := params.Get("username")
+	for _, u := range h.users {
+		if (userID != "" && u.userID == userID) || (userID == "" && strings.EqualFold(u.username, username)) {
+			return u
+		}
+	}
+	return nil
+}
+
+// lookupUser returns the user identified by params, creating unknown users
+// with the default scenario.
+func (h *AuthHandler) lookupUser(params url.Values) (*authUser, *apiError) {
+	if params.Get("user_id") == "" && params.Get("username") == "" {
+		return nil, missingParam("user_id or username")
+	}
+	if user := h.findUser(params); user != nil {
+		return user, nil
+	}
+	if params.Get("user_id") != "" {
+		return nil, invalidParam("user_id")
+	}
+	return h.addUser(params.Get("username")), nil
+}
+
+// ServeHTTP handles an Auth API request.
+func (h *AuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params, err := requestParams(r)
+	if err != nil {
+		writeError(w, invalidParam(err.Error()))
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path != "/auth/v2/ping" {
+		if apiErr := verifySignature(r, h.IKey, h.SKey, params); apiErr != nil {
+			writeError(w, apiErr)
+			return
+		}
+	}
+
+	var apiErr *apiError
+	switch path {
+	case "/auth/v2/ping", "/auth/v2/check":
+		if r.Method != http.MethodGet {
+			apiErr = methodNotAllowed()
+			break
+		}
+		writeJSON(w, map[string]interface{}{"time": h.now().Unix()})
+	case "/auth/v2/logo":
+		apiErr = h.logo(w, r)
+	case "/auth/v2/enroll":
+		apiErr = h.enroll(w, r, params)
+	case "/auth/v2/enroll_status":
+		apiErr = h.enrollStatus(w, r, params)
+	case "/auth/v2/preauth":
+		apiErr = h.preauth(w, r, params)
+	case "/auth/v2/auth":
+		apiErr = h.auth(w, r, params)
+	case "/auth/v2/auth_status":
+		apiErr = h.authStatus(w, r, params)
+	default:
+		apiErr = notFound()
+	}
+	if apiErr != nil {
+		writeError(w, apiErr)
+	}
+}
+
+func (h *AuthHandler) logo(w http.ResponseWriter, r *http.Request) *apiError {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed()
+	}
+	if h.Logo == nil {
+		return &apiError{http.StatusNotFound, CodeInvalidParams, "Logo not found", ""}
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(h.Logo)
+	return nil
+}
+
+func (h *AuthHandler) enroll(w http.ResponseWriter, r *http.Request, params url.Values) *apiError {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed()
+	}
+	validSecs := int64(86400)
+	if v := params.Get("valid_secs"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 {
+			return invalidParam("valid_secs")
+		}
+		validSecs = n
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	username := params.Get("username")
+	if username == "" {
+		username = strings.ToLower(h.ids.id("u"))
+	} else if h.findUser(url.Values{"username": {username}}) != nil {
+		return duplicate("username")
+	}
+
+	user := h.addUser(username)
+	user.scenario = Scenario{Outcome: OutcomeNotEnrolled}
+	user.activation = h.ids.id("ACT")
+	user.expiration = h.now().Add(time.Duration(validSecs) * time.Second)
+
+	writeJSON(w, map[string]interface{}{
+		"activation_barcode": "https://" + r.Host + "/frame/qr?value=" + user.activation,
+		"activation_code":    "duo://" + user.activation,
+		"expiration":         user.expiration.Unix(),
+		"user_id":            user.userID,
+		"username":           user.username,
+	})
+	return nil
+}
+
+func (h *AuthHandler) enrollStatus(w http.ResponseWriter, r *http.Request, params url.Values) *apiError {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed()
+	}
+	if params.Get("user_id") == "" {
+		return missingParam("user_id")
+	}
+	if params.Get("activation_code") == "" {
+		return missingParam("activation_code")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	user := h.findUser(url.Values{"user_id": {params.Get("user_id")}})
+	code := strings.TrimPrefix(params.Get("activation_code"), "duo://")
+	switch {
+	case user == nil || user.activation == "" || user.activation != code:
+		writeJSON(w, "invalid")
+	case user.enrolled:
+		writeJSON(w, "success")
+	case h.now().After(user.expiration):
+		writeJSON(w, "invalid")
+	default:
+		writeJSON(w, "waiting")
+	}
+	return nil
+}
+
+func (h *AuthHandler) preauth(w http.ResponseWriter, r *http.Request, params url.Values) *apiError {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	user, apiErr := h.lookupUser(params)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	switch user.scenario.Outcome {
+	case OutcomeNotEnrolled:
+		writeJSON(w, map[string]interface{}{
+			"result":            "enroll",
+			"status_msg":        "Enroll an authentication device to proceed",
+			"enroll_portal_url": "https://" + r.Host + "/portal?" + user.userID,
+		})
+	case OutcomeBypass:
+		writeJSON(w, map[string]interface{}{
+			"result":     "allow",
+			"status_msg": "Allowing unknown user",
+		})
+	default:
+		capabilities := []string{"auto", "push", "sms", "phone", "mobile_otp"}
+		if user.scenario.Outcome == OutcomePasscode {
+			capabilities = []string{"mobile_otp"}
+		}
+		writeJSON(w, map[string]interface{}{
+			"result":     "auth",
+			"status_msg": "Account is active",
+			"devices": []interface{}{map[string]interface{}{
+				"device":       user.deviceID,
+				"type":         "phone",
+				"name":         "",
+				"number":       "XXX-XXX-0100",
+				"capabilities": capabilities,
+			}},
+		})
+	}
+	return nil
+}
+
+// respond determines a user's response to an authentication attempt.
+func (h *AuthHandler) respond(user *authUser, factor string, params url.Values) (*transaction, *apiError) {
+	txn := &transaction{ready: h.now()}
+	scenario := user.scenario
+
+	switch scenario.Outcome {
+	case OutcomeNotEnrolled:
+		txn.result, txn.status, txn.statusMsg = "deny", "deny", "Enroll an authentication device first"
+		return txn, nil
+	case OutcomeBypass:
+		txn.result, txn.status, txn.statusMsg = "allow", "bypass", "Bypassing two-factor authentication"
+		return txn, nil
+	}
+
+	switch factor {
+	case "passcode":
+		if params.Get("passcode") == "" {
+			return nil, missingParam("passcode")
+		}
+		if scenario.Passcode != "" && params.Get("passcode") == scenario.Passcode {
+			txn.result, txn.status, txn.statusMsg = "allow", "allow", "Success. Logging you in..."
+		} else {
+			txn.result, txn.status, txn.statusMsg = "deny", "deny", "Incorrect passcode. Please try again."
+		}
+		return txn, nil
+	case "sms":
+		txn.result, txn.status, txn.statusMsg = "deny", "sent", "New SMS passcodes sent"
+		return txn, nil
+	case "auto", "push", "phone":
+	default:
+		return nil, invalidParam("factor")
+	}
+
+	if scenario.Outcome == OutcomePasscode {
+		txn.result, txn.status, txn.statusMsg = "deny", "deny", "This device requires a passcode"
+		return txn, nil
+	}
+
+	txn.ready = txn.ready.Add(scenario.After)
+	switch scenario.Outcome {
+	case OutcomeApprove:
+		txn.result, txn.status, txn.statusMsg = "allow", "allow", "Success. Logging you in..."
+	case OutcomeDeny:
+		txn.result, txn.status, txn.statusMsg = "deny", "deny", "Login request denied."
+	case OutcomeTimeout:
+		txn.result, txn.status, txn.statusMsg = "deny", "timeout", "Login timed out."
+	case OutcomeFraud:
+		txn.result, txn.status, txn.statusMsg = "deny", "fraud", "Login request reported as fraudulent."
+	}
+	return txn, nil
+}
+
+func (h *AuthHandler) auth(w http.ResponseWriter, r *http.Request, params url.Values) *apiError {
+	if r.Method != http.MethodPost {
+		return methodNotAllowed()
+	}
+	factor := params.Get("factor")
+	if factor == "" {
+		return missingParam("factor")
+	}
+
+	h.mu.Lock()
+	user, apiErr := h.lookupUser(params)
+	if apiErr != nil {
+		h.mu.Unlock()
+		return apiErr
+	}
+	txn, apiErr := h.respond(user, factor, params)
+	if apiErr != nil {
+		h.mu.Unlock()
+		return apiErr
+	}
+
+	if params.Get("async") == "1" {
+		txid := fmt.Sprintf("%08x-0000-4000-8000-%012x", h.ids.next, h.ids.next)
+		h.ids.next++
+		h.transactions[txid] = txn
+		h.mu.Unlock()
+		writeJSON(w, map[string]interface{}{"txid": txid})
+		return nil
+	}
+	h.mu.Unlock()
+
+	// Synchronous requests block until the user responds.
+	if wait := txn.ready.Sub(h.now()); wait > 0 {
+		h.sleep(wait)
+	}
+	writeJSON(w, map[string]interface{}{
+		"result":     txn.result,
+		"status":     txn.status,
+		"status_msg": txn.statusMsg,
+	})
+	return nil
+}
+
+func (h *AuthHandler) authStatus(w http.ResponseWriter, r *http.Request, params url.Values) *apiError {
+	if r.Method != http.MethodGet {
+		return methodNotAllowed()
+	}
+	txid := params.Get("txid")
+	if txid == "" {
+		return missingParam("txid")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	txn, ok := h.transactions[txid]
+	if !ok {
+		return invalidParam("txid")
+	}
+	if h.now().Before(txn.ready) {
+		writeJSON(w, map[string]interface{}{
+			"result":     "waiting",
+			"status":     "pushed",
+			"status_msg": "Pushed a login request to your device...",
+		})
+		return nil
+	}
+	writeJSON(w, map[string]interface{}{
+		"result":     txn.result,
+		"status":     txn.status,
+		"status_msg": txn.statusMsg,
+	})
+	return nil
+}
@@ -0,0 +1,188 @@
+package duotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/duosecurity/duo_api_golang/authapi"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time                     { return c.now }
+func (c *fakeClock) Sleep(d time.Duration)              { c.now = c.now.Add(d) }
+func (c *fakeClock) Jitter(time.Duration) time.Duration { return 0 }
+
+func TestParseScenario(t *testing.T) {
+	scenario, err := ParseScenario("passcode:2s:123456")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if scenario.Outcome != OutcomePasscode || scenario.After != 2*time.Second || scenario.Passcode != "123456" {
+		t.Errorf("Unexpected scenario %+v", scenario)
+	}
+	if _, err := ParseScenario("approve:soon"); err == nil {
+		t.Error("Expected an invalid delay to fail")
+	}
+	if _, err := ParseScenario("maybe"); err == nil {
+		t.Error("Expected an unknown outcome to fail")
+	}
+}
+
+func TestAuthServerSyncOutcomes(t *testing.T) {
+	srv := NewAuthServer("eyekey", "esskey")
+	defer srv.Close()
+	srv.Clock = &fakeClock{now: time.Unix(1357020061, 0)}
+	client := srv.Client()
+
+	cases := []struct {
+		scenario Scenario
+		result   string
+		status   string
+	}{
+		{Scenario{Outcome: OutcomeApprove, After: 5 * time.Second}, "allow", "allow"},
+		{Scenario{Outcome: OutcomeDeny}, "deny", "deny"},
+		{Scenario{Outcome: OutcomeTimeout, After: time.Minute}, "deny", "timeout"},
+		{Scenario{Outcome: OutcomeFraud}, "deny", "fraud"},
+		{Scenario{Outcome: OutcomeBypass}, "allow", "bypass"},
+		{Scenario{Outcome: OutcomePasscode}, "deny", "deny"},
+	}
+	for _, c := range cases {
+		srv.SetUser("jsmith", c.scenario)
+		result, err := client.Auth("push", authapi.AuthUsername("jsmith"))
+		if err != nil {
+			t.Fatalf("Unexpected error from Auth call %v", err)
+		}
+		if result.Response.Result != c.result || result.Response.Status != c.status {
+			t.Errorf("%s: expected %s/%s, got %s/%s", c.scenario.Outcome, c.result, c.status,
+				result.Response.Result, result.Response.Status)
+		}
+	}
+}
+
+func TestAuthServerPasscode(t *testing.T) {
+	srv := NewAuthServer("eyekey", "esskey")
+	defer srv.Close()
+	srv.SetUser("jsmith", Scenario{Outcome: OutcomePasscode, Passcode: "123456"})
+	client := srv.Client()
+
+	preauth, err := client.Preauth(authapi.PreauthUsername("jsmith"))
+	if err != nil {
+		t.Fatalf("Unexpected error from Preauth call %v", err)
+	}
+	if preauth.Response.Result != "auth" || preauth.Response.Devices[0].Capabilities[0] != "mobile_otp" {
+		t.Errorf("Unexpected preauth response %+v", preauth.Response)
+	}
+
+	result, err := client.Auth("passcode", authapi.AuthUsername("jsmith"), authapi.AuthPasscode("654321"))
+	if err != nil || result.Response.Result != "deny" {
+		t.Errorf("Expected a wrong passcode to be denied, got %+v, %v", result, err)
+	}
+	result, err = client.Auth("passcode", authapi.AuthUsername("jsmith"), authapi.AuthPasscode("123456"))
+	if err != nil || result.Response.Result != "allow" {
+		t.Errorf("Expected the right passcode to be allowed, got %+v, %v", result, err)
+	}
+}
+
+func TestAuthServerAsync(t *testing.T) {
+	srv := NewAuthServer("eyekey", "esskey")
+	defer srv.Close()
+	clock := &fakeClock{now: time.Unix(1357020061, 0)}
+	srv.Clock = clock
+	srv.SetUser("jsmith", Scenario{Outcome: OutcomeApprove, After: 10 * time.Second})
+	client := srv.Client()
+
+	result, err := client.Auth("push", authapi.AuthUsername("jsmith"), authapi.AuthAsync())
+	if err != nil {
+		t.Fatalf("Unexpected error from Auth call %v", err)
+	}
+	txid := result.Response.Txid
+	if txid == "" {
+		t.Fatalf("Expected a txid, got %+v", result)
+	}
+
+	status, err := client.AuthStatus(txid)
+	if err != nil {
+		t.Fatalf("Unexpected error from AuthStatus call %v", err)
+	}
+	if status.Response.Result != "waiting" || status.Response.Status != "pushed" {
+		t.Errorf("Expected the push to be waiting, got %+v", status.Response)
+	}
+
+	clock.now = clock.now.Add(10 * time.Second)
+	status, err = client.AuthStatus(txid)
+	if err != nil {
+		t.Fatalf("Unexpected error from AuthStatus call %v", err)
+	}
+	if status.Response.Result != "allow" {
+		t.Errorf("Expected the push to be approved, got %+v", status.Response)
+	}
+
+	unknown, err := client.AuthStatus("bogus")
+	if err != nil {
+		t.Fatalf("Unexpected error from AuthStatus call %v", err)
+	}
+	if unknown.Stat != "FAIL" || *unknown.Code != CodeInvalidParams {
+		t.Errorf("Expected an invalid txid failure, got %+v", unknown.StatResult)
+	}
+}
+
+func TestAuthServerEnrollment(t *testing.T) {
+	srv := NewAuthServer("eyekey", "esskey")
+	defer srv.Close()
+	client := srv.Client()
+
+	preauth, err := client.Preauth(authapi.PreauthUsername("newuser"))
+	if err != nil || preauth.Response.Result != "enroll" {
+		t.Fatalf("Expected an unknown user to need enrollment, got %+v, %v", preauth, err)
+	}
+
+	enroll, err := client.Enroll(authapi.EnrollUsername("jsmith"), authapi.EnrollValidSeconds(60))
+	if err != nil || enroll.Stat != "OK" {
+		t.Fatalf("Unexpected Enroll result %+v, %v", enroll, err)
+	}
+	userID := enroll.Response.User_Id
+	code := enroll.Response.Activation_Code
+
+	status, err := client.EnrollStatus(userID, code)
+	if err != nil || status.Response != "waiting" {
+		t.Fatalf("Expected enrollment to be waiting, got %+v, %v", status, err)
+	}
+	srv.CompleteEnrollment(userID)
+	status, err = client.EnrollStatus(userID, code)
+	if err != nil || status.Response != "success" {
+		t.Fatalf("Expected enrollment to succeed, got %+v, %v", status, err)
+	}
+
+	result, err := client.Auth("push", authapi.AuthUserId(userID))
+	if err != nil || result.Response.Result != "allow" {
+		t.Errorf("Expected the enrolled user to approve, got %+v, %v", result, err)
+	}
+}
+
+func TestAuthServerPingCheckLogo(t *testing.T) {
+	srv := NewAuthServer("eyekey", "esskey")
+	defer srv.Close()
+	srv.Clock = &fakeClock{now: time.Unix(1357020061, 0)}
+	client := srv.Client()
+
+	ping, err := client.Ping()
+	if err != nil || ping.Response.Time != 1357020061 {
+		t.Errorf("Unexpected Ping result %+v, %v", ping, err)
+	}
+	check, err := client.Check()
+	if err != nil || check.Response.Time != 1357020061 {
+		t.Errorf("Unexpected Check result %+v, %v", check, err)
+	}
+	logo, err := client.Logo()
+	if err != nil || logo.Stat != "FAIL" {
+		t.Errorf("Expected a missing logo to fail, got %+v, %v", logo, err)
+	}
+	srv.Logo = []byte("\x89PNG")
+	logo, err = client.Logo()
+	if err != nil || logo.Stat != "OK" {
+		t.Errorf("Unexpected Logo result %+v, %v", logo, err)
+	}
+}
@@ -0,0 +1,177 @@
+package duotest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// Bypass code limits.
+const (
+	maxBypassCodes     = 10
+	defaultBypassCodes = 10
+)
+
+// BypassCode is a bypass code stored by AdminServer.
+type BypassCode struct {
+	BypassCodeID string
+	UserID       string
+	Code         string
+	Created      int64
+	Expiration   *int64
+	ReuseCount   int
+}
+
+// BypassCodes returns copies of the stored bypass codes, in creation order.
+func (s *AdminServer) BypassCodes() []BypassCode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	codes := make([]BypassCode, 0, len(s.bypass))
+	for _, b := range s.bypass {
+		codes = append(codes, *b)
+	}
+	return codes
+}
+
+func (s *AdminServer) findBypassCode(bypassCodeID string) *BypassCode {
+	for _, b := range s.bypass {
+		if b.BypassCodeID == bypassCodeID {
+			return b
+		}
+	}
+	return nil
+}
+
+func (s *AdminServer) bypassCodeJSON(b *BypassCode) map[string]interface{} {
+	var expiration interface{}
+	if b.Expiration != nil {
+		expiration = *b.Expiration
+	}
+	code := map[string]interface{}{
+		"admin_email":    "",
+		"bypass_code_id": b.BypassCodeID,
+		"created":        b.Created,
+		"expiration":     expiration,
+		"reuse_count":    b.ReuseCount,
+	}
+	if u := s.findUser(b.UserID); u != nil {
+		code["user"] = userSummaryJSON(u)
+	}
+	return code
+}
+
+func (s *AdminServer) createBypassCodes(w http.ResponseWriter, user *admin.User, params url.Values) *apiError {
+	var codes []string
+	if list := params.Get("codes"); list != "" {
+		if params.Get("count") != "" {
+			return invalidParam("count")
+		}
+		codes = strings.Split(list, ",")
+		if len(codes) > maxBypassCodes {
+			return invalidParam("codes")
+		}
+		for _, code := range codes {
+			if _, err := strconv.ParseUint(code, 10, 64); err != nil || len(code) < 6 {
+				return invalidParam("codes")
+			}
+		}
+	} else {
+		count := defaultBypassCodes
+		if c := params.Get("count"); c != "" {
+			n, err := strconv.Atoi(c)
+			if err != nil || n < 1 || n > maxBypassCodes {
+				return invalidParam("count")
+			}
+			count = n
+		}
+		for i := 0; i < count; i++ {
+			codes = append(codes, s.bypassCode())
+		}
+	}
+
+	reuse := 1
+	if r := params.Get("reuse_count"); r != "" {
+		n, err := strconv.Atoi(r)
+		if err != nil || n < 0 {
+			return invalidParam("reuse_count")
+		}
+		reuse = n
+	}
+	var expiration *int64
+	if v := params.Get("valid_secs"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return invalidParam("valid_secs")
+		}
+		if n > 0 {
+			exp := time.Now().Unix() + n
+			expiration = &exp
+		}
+	}
+
+	// New codes replace any existing codes for the user.
+	var kept []*BypassCode
+	for _, b := range s.bypass {
+		if b.UserID != user.UserID {
+			kept = append(kept, b)
+		}
+	}
+	s.bypass = kept
+
+	now := time.Now().Unix()
+	for _, code := range codes {
+		s.bypass = append(s.bypass, &BypassCode{
+			BypassCodeID: s.ids.id("DB"),
+			UserID:       user.UserID,
+			Code:         code,
+			Created:      now,
+			Expiration:   expiration,
+			ReuseCount:   reuse,
+		})
+	}
+	writeJSON(w, codes)
+	return nil
+}
+
+// bypassCode generates a unique numeric bypass code.
+func (s *AdminServer) bypassCode() string {
+	s.codes++
+	return strconv.FormatUint(100000000+(s.codes*7919)%900000000, 10)
+}
+
+func (s *AdminServer) handleBypassCodes(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		var codes []interface{}
+		for _, b := range s.bypass {
+			codes = append(codes, s.bypassCodeJSON(b))
+		}
+		return writePage(w, params, codes, maxPageSize)
+	}
+
+	code := s.findBypassCode(path[0])
+	if code == nil || len(path) != 1 {
+		return notFound()
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, s.bypassCodeJSON(code))
+		return nil
+	case http.MethodDelete:
+		for i, b := range s.bypass {
+			if b == code {
+				s.bypass = append(s.bypass[:i:i], s.bypass[i+1:]...)
+				break
+			}
+		}
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
@@ -0,0 +1,52 @@
+package duotest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerBypassCodes(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	user := srv.AddUser(admin.User{Username: "jsmith"})
+	other := srv.AddUser(admin.User{Username: "jdoe"})
+	client := srv.Client()
+
+	codes, err := client.CreateUserBypassCodes(user.UserID, admin.BypassCodesCreate{
+		Codes:      []string{"123456", "234567"},
+		ReuseCount: admin.Int(3),
+		ValidSecs:  admin.Int(3600),
+	})
+	if err != nil || !reflect.DeepEqual(codes.Response, []string{"123456", "234567"}) {
+		t.Fatalf("Unexpected CreateUserBypassCodes result %+v, %v", codes, err)
+	}
+	if _, err = client.CreateUserBypassCodes(other.UserID, admin.BypassCodesCreate{Count: admin.Int(1)}); err != nil {
+		t.Fatalf("Unexpected error from CreateUserBypassCodes call %v", err)
+	}
+
+	all, err := client.ListBypassCodes()
+	if err != nil || len(all.Response) != 3 {
+		t.Fatalf("Unexpected ListBypassCodes result %+v, %v", all, err)
+	}
+	mine, err := client.ListUserBypassCodes(user.UserID)
+	if err != nil || len(mine.Response) != 2 {
+		t.Fatalf("Unexpected ListUserBypassCodes result %+v, %v", mine, err)
+	}
+	code := mine.Response[0]
+	if _, ok := code.Expires(); !ok || code.ReuseCount != 3 || code.User.UserID != user.UserID {
+		t.Errorf("Unexpected bypass code %+v", code)
+	}
+
+	fetched, err := client.GetBypassCode(code.BypassCodeID)
+	if err != nil || fetched.Response.BypassCodeID != code.BypassCodeID {
+		t.Errorf("Unexpected GetBypassCode result %+v, %v", fetched, err)
+	}
+	if result, err := client.DeleteBypassCode(code.BypassCodeID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteBypassCode result %+v, %v", result, err)
+	}
+	if len(srv.BypassCodes()) != 2 {
+		t.Errorf("Expected 2 bypass codes, got %d", len(srv.BypassCodes()))
+	}
+}
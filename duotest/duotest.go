@@ -0,0 +1,254 @@
+// Package duotest provides fake Duo API servers for testing code built on
+// this library without network access to Duo.
+//
+// AdminServer is a stateful, in-memory implementation of the Admin API:
+//
+//	srv := duotest.NewAdminServer("ikey", "skey")
+//	defer srv.Close()
+//	client := srv.Client()
+//	result, err := client.CreateUser(admin.UserUpdate{Username: admin.String("jsmith")})
+//
+// AuthServer is a scriptable Auth API whose users approve, deny or ignore
+// authentication requests according to a per-user Scenario:
+//
+//	srv := duotest.NewAuthServer("ikey", "skey")
+//	defer srv.Close()
+//	srv.SetUser("jsmith", duotest.Scenario{Outcome: duotest.OutcomeApprove, After: 3 * time.Second})
+//	result, err := srv.Client().Auth("push", authapi.AuthUsername("jsmith"))
+//
+// Requests must be signed with the server's integration and secret keys,
+// and responses carry the same envelope, pagination metadata and error
+// codes as Duo's servers.
+package duotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+)
+
+// Error codes returned in FAIL responses, as documented by Duo.
+const (
+	CodeInvalidParams    = 40002
+	CodeDuplicate        = 40003
+	CodeMissingAuth      = 40101
+	CodeInvalidSignature = 40103
+	CodeNotFound         = 40401
+	CodeMethodNotAllowed = 40501
+)
+
+// apiError is a FAIL response.
+type apiError struct {
+	status  int
+	code    int
+	message string
+	detail  string
+}
+
+func (e *apiError) Error() string {
+	if e.detail != "" {
+		return e.message + ": " + e.detail
+	}
+	return e.message
+}
+
+func invalidParam(name string) *apiError {
+	return &apiError{http.StatusBadRequest, CodeInvalidParams, "Invalid request parameters", name}
+}
+
+func missingParam(name string) *apiError {
+	return &apiError{http.StatusBadRequest, CodeInvalidParams, "Missing required request parameters", name}
+}
+
+func duplicate(detail string) *apiError {
+	return &apiError{http.StatusBadRequest, CodeDuplicate, "Duplicate resource", detail}
+}
+
+func notFound() *apiError {
+	return &apiError{http.StatusNotFound, CodeNotFound, "Resource not found", ""}
+}
+
+func methodNotAllowed() *apiError {
+	return &apiError{http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Method not allowed", ""}
+}
+
+// writeJSON writes an OK response wrapping response.
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	writeEnvelope(w, http.StatusOK, map[string]interface{}{
+		"stat":     "OK",
+		"response": response,
+	})
+}
+
+// writeList writes an OK response with pagination metadata.
+func writeList(w http.ResponseWriter, response interface{}, meta map[string]interface{}) {
+	writeEnvelope(w, http.StatusOK, map[string]interface{}{
+		"stat":     "OK",
+		"response": response,
+		"metadata": meta,
+	})
+}
+
+// writeError writes a FAIL response.
+func writeError(w http.ResponseWriter, err *apiError) {
+	body := map[string]interface{}{
+		"stat":    "FAIL",
+		"code":    err.code,
+		"message": err.message,
+	}
+	if err.detail != "" {
+		body["message_detail"] = err.detail
+	}
+	writeEnvelope(w, err.status, body)
+}
+
+func writeEnvelope(w http.ResponseWriter, status int, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// requestParams returns the parameters of a request, taken from the body of
+// POST and PUT requests and from the query string otherwise.
+func requestParams(r *http.Request) (url.Values, error) {
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return r.PostForm, nil
+	}
+	return r.URL.Query(), nil
+}
+
+// decodeJSON decodes the JSON body of a request into v.
+func decodeJSON(r *http.Request, v interface{}) *apiError {
+	if r.Header.Get("Content-Type") != "application/json" {
+		return invalidParam("Content-Type")
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return invalidParam("body")
+	}
+	return nil
+}
+
+// verifySignature checks that a request was signed with ikey and skey.
+// Requests with an X-Duo-Date header must carry a version 5 signature, which
+// covers the body; the body is left for the handler to read.
+func verifySignature(r *http.Request, ikey, skey string, params url.Values) *apiError {
+	auth := r.Header.Get("Authorization")
+	date := r.Header.Get("Date")
+	v5Date := r.Header.Get("X-Duo-Date")
+	if auth == "" || (date == "" && v5Date == "") {
+		return &apiError{http.StatusUnauthorized, CodeMissingAuth, "Missing request credentials", ""}
+	}
+
+	copied := url.Values{}
+	for k, v := range params {
+		copied[k] = append([]string(nil), v...)
+	}
+	var expected string
+	if v5Date != "" {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return invalidParam("body")
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		expected = duoapi.SignV5(ikey, skey, r.Method, r.Host, r.URL.Path, v5Date, copied, body)
+	} else {
+		expected = duoapi.Sign(ikey, skey, r.Method, r.Host, r.URL.Path, date, copied)
+	}
+	if auth != expected {
+		return &apiError{http.StatusUnauthorized, CodeInvalidSignature, "Invalid signature in request credentials", ""}
+	}
+	return nil
+}
+
+// splitPath splits a URL path into its non-empty segments.
+func splitPath(path string) []string {
+	var parts []string
+	for _, part := range strings.Split(path, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// page applies Duo's limit and offset parameters to a list of n items,
+// returning the range to respond with and its pagination metadata.
+func page(params url.Values, n, defaultLimit, maxLimit int) (start, end int, meta map[string]interface{}, err *apiError) {
+	limit := defaultLimit
+	if s := params.Get("limit"); s != "" {
+		l, convErr := strconv.Atoi(s)
+		if convErr != nil || l < 1 {
+			return 0, 0, nil, invalidParam("limit")
+		}
+		limit = l
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+	}
+	offset := 0
+	if s := params.Get("offset"); s != "" {
+		o, convErr := strconv.Atoi(s)
+		if convErr != nil || o < 0 {
+			return 0, 0, nil, invalidParam("offset")
+		}
+		offset = o
+	}
+
+	start = offset
+	if start > n {
+		start = n
+	}
+	end = start + limit
+	if end > n {
+		end = n
+	}
+
+	meta = map[string]interface{}{
+		"total_objects": n,
+		"next_offset":   nil,
+		"prev_offset":   nil,
+	}
+	if end < n {
+		meta["next_offset"] = end
+	}
+	if start > 0 {
+		prev := start - limit
+		if prev < 0 {
+			prev = 0
+		}
+		meta["prev_offset"] = prev
+	}
+	return start, end, meta, nil
+}
+
+// parseBool parses Duo's boolean parameter values.
+func parseBool(name, value string) (bool, *apiError) {
+	switch strings.ToLower(value) {
+	case "1", "true":
+		return true, nil
+	case "0", "false":
+		return false, nil
+	}
+	return false, invalidParam(name)
+}
+
+// idGenerator produces Duo style identifiers: a prefix, such as "DU", followed
+// by 18 upper case alphanumeric characters.
+type idGenerator struct {
+	next uint64
+}
+
+func (g *idGenerator) id(prefix string) string {
+	g.next++
+	s := strings.ToUpper(strconv.FormatUint(g.next, 36))
+	return prefix + strings.Repeat("0", 18-len(s)) + s
+}
@@ -0,0 +1,190 @@
+package duotest
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// AddGroup stores a copy of group, assigning it a group ID if it has none,
+// and returns the stored group.
+func (s *AdminServer) AddGroup(group admin.Group) admin.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if group.GroupID == "" {
+		group.GroupID = s.ids.id("DG")
+	}
+	if group.Status == "" {
+		group.Status = string(admin.GroupStatusActive)
+	}
+	s.groups = append(s.groups, &group)
+	return group
+}
+
+// Groups returns copies of the stored groups, in creation order.
+func (s *AdminServer) Groups() []admin.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups := make([]admin.Group, 0, len(s.groups))
+	for _, g := range s.groups {
+		groups = append(groups, *g)
+	}
+	return groups
+}
+
+func (s *AdminServer) findGroup(groupID string) *admin.Group {
+	for _, g := range s.groups {
+		if g.GroupID == groupID {
+			return g
+		}
+	}
+	return nil
+}
+
+func groupJSON(g *admin.Group) map[string]interface{} {
+	return map[string]interface{}{
+		"desc":               g.Desc,
+		"group_id":           g.GroupID,
+		"mobile_otp_enabled": g.MobileOTPEnabled,
+		"name":               g.Name,
+		"push_enabled":       g.PushEnabled,
+		"sms_enabled":        g.SMSEnabled,
+		"status":             g.Status,
+		"voice_enabled":      g.VoiceEnabled,
+	}
+}
+
+func (s *AdminServer) handleGroups(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var groups []interface{}
+			for _, g := range s.groups {
+				groups = append(groups, groupJSON(g))
+			}
+			return writePage(w, params, groups, maxGroupsPageSize)
+		case http.MethodPost:
+			name := params.Get("name")
+			if name == "" {
+				return missingParam("name")
+			}
+			for _, g := range s.groups {
+				if g.Name == name {
+					return duplicate("name")
+				}
+			}
+			group := &admin.Group{GroupID: s.ids.id("DG"), Status: string(admin.GroupStatusActive)}
+			if err := applyGroupParams(group, params); err != nil {
+				return err
+			}
+			s.groups = append(s.groups, group)
+			writeJSON(w, groupJSON(group))
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	group := s.findGroup(path[0])
+	if group == nil || len(path) != 1 {
+		return notFound()
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, groupJSON(group))
+		return nil
+	case http.MethodPost:
+		updated := *group
+		if err := applyGroupParams(&updated, params); err != nil {
+			return err
+		}
+		*group = updated
+		writeJSON(w, groupJSON(group))
+		return nil
+	case http.MethodDelete:
+		for i, g := range s.groups {
+			if g == group {
+				s.groups = append(s.groups[:i:i], s.groups[i+1:]...)
+				break
+			}
+		}
+		for userID, ids := range s.userGroups {
+			s.userGroups[userID], _ = removeID(ids, group.GroupID)
+		}
+		for _, u := range s.adminUnits {
+			u.Groups, _ = removeID(u.Groups, group.GroupID)
+		}
+		for _, p := range s.policies {
+			for ikey, ids := range p.groups {
+				p.groups[ikey], _ = removeID(ids, group.GroupID)
+			}
+		}
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
+
+func (s *AdminServer) handleGroupsV2(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		return notFound()
+	}
+	group := s.findGroup(path[0])
+	if group == nil {
+		return notFound()
+	}
+	if r.Method != http.MethodGet {
+		return methodNotAllowed()
+	}
+	switch {
+	case len(path) == 1:
+		writeJSON(w, groupJSON(group))
+		return nil
+	case len(path) == 2 && path[1] == "users":
+		var users []interface{}
+		for _, u := range s.usersOf(s.userGroups, group.GroupID) {
+			users = append(users, map[string]interface{}{
+				"user_id":  u.UserID,
+				"username": u.Username,
+			})
+		}
+		return writePage(w, params, users, maxPageSize)
+	}
+	return notFound()
+}
+
+// applyGroupParams sets the group attributes present in params.
+func applyGroupParams(group *admin.Group, params url.Values) *apiError {
+	if values, ok := params["name"]; ok {
+		if values[0] == "" {
+			return invalidParam("name")
+		}
+		group.Name = values[0]
+	}
+	if values, ok := params["desc"]; ok {
+		group.Desc = values[0]
+	}
+	if values, ok := params["status"]; ok {
+		status := admin.GroupStatus(values[0])
+		if !status.Valid() {
+			return invalidParam("status")
+		}
+		group.Status = string(status)
+	}
+	flags := map[string]*bool{
+		"push_enabled":       &group.PushEnabled,
+		"sms_enabled":        &group.SMSEnabled,
+		"voice_enabled":      &group.VoiceEnabled,
+		"mobile_otp_enabled": &group.MobileOTPEnabled,
+	}
+	for name, field := range flags {
+		if values, ok := params[name]; ok {
+			value, err := parseBool(name, values[0])
+			if err != nil {
+				return err
+			}
+			*field = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package duotest
+
+import (
+	"testing"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerGroupCRUD(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	user := srv.AddUser(admin.User{Username: "jsmith"})
+	client := srv.Client()
+
+	created, err := client.CreateGroup(admin.GroupUpdate{Name: admin.String("Staff"), SMSEnabled: admin.Bool(true)})
+	if err != nil || created.Stat != "OK" {
+		t.Fatalf("Unexpected CreateGroup result %+v, %v", created, err)
+	}
+	groupID := created.Response.GroupID
+	if !created.Response.SMSEnabled {
+		t.Errorf("Expected SMS to be enabled, got %+v", created.Response)
+	}
+
+	modified, err := client.ModifyGroup(groupID, admin.GroupUpdate{Status: admin.NewGroupStatus(admin.GroupStatusDisabled)})
+	if err != nil || modified.Response.Status != string(admin.GroupStatusDisabled) {
+		t.Errorf("Unexpected ModifyGroup result %+v, %v", modified, err)
+	}
+
+	srv.AssociateGroup(user.UserID, groupID)
+	users, err := client.GetGroupUsers(groupID)
+	if err != nil {
+		t.Fatalf("Unexpected error from GetGroupUsers call %v", err)
+	}
+	if len(users.Response) != 1 || users.Response[0].Username != "jsmith" {
+		t.Errorf("Unexpected group users %+v", users.Response)
+	}
+
+	if deleted, err := client.DeleteGroup(groupID); err != nil || deleted.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteGroup result %v, %v", deleted, err)
+	}
+	if len(srv.Groups()) != 0 || len(srv.Users()[0].Groups) != 0 {
+		t.Errorf("Expected the group and its memberships to be deleted")
+	}
+}
@@ -0,0 +1,220 @@
+package duotest
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// AddIntegration stores a copy of integration, assigning it an integration
+// key and a secret key if it has none, and returns the stored integration.
+func (s *AdminServer) AddIntegration(integration admin.Integration) admin.Integration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if integration.IntegrationKey == "" {
+		integration.IntegrationKey = s.ids.id("DI")
+	}
+	if integration.SecretKey == "" {
+		integration.SecretKey = s.secretKey()
+	}
+	s.integs = append(s.integs, &integration)
+	return integration
+}
+
+// Integrations returns copies of the stored integrations, in creation
+// order, with their secret keys.
+func (s *AdminServer) Integrations() []admin.Integration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	integrations := make([]admin.Integration, 0, len(s.integs))
+	for _, i := range s.integs {
+		integrations = append(integrations, *i)
+	}
+	return integrations
+}
+
+func (s *AdminServer) findIntegration(integrationKey string) *admin.Integration {
+	for _, i := range s.integs {
+		if i.IntegrationKey == integrationKey {
+			return i
+		}
+	}
+	return nil
+}
+
+// secretKey returns a new 40 character secret key.
+func (s *AdminServer) secretKey() string {
+	return strings.ToLower(s.ids.id("SK") + s.ids.id("SK"))
+}
+
+// integrationJSON renders an integration without its secret key, as Duo
+// does outside of create responses and the skey endpoint.
+func integrationJSON(i *admin.Integration) map[string]interface{} {
+	groups := i.GroupsAllowed
+	if groups == nil {
+		groups = []string{}
+	}
+	flag := func(f admin.Flag) int {
+		if f {
+			return 1
+		}
+		return 0
+	}
+	return map[string]interface{}{
+		"adminapi_admins":         flag(i.AdminAPIAdmins),
+		"adminapi_info":           flag(i.AdminAPIInfo),
+		"adminapi_integrations":   flag(i.AdminAPIIntegrations),
+		"adminapi_read_log":       flag(i.AdminAPIReadLog),
+		"adminapi_read_resource":  flag(i.AdminAPIReadResource),
+		"adminapi_settings":       flag(i.AdminAPISettings),
+		"adminapi_write_resource": flag(i.AdminAPIWriteResource),
+		"greeting":                i.Greeting,
+		"groups_allowed":          groups,
+		"integration_key":         i.IntegrationKey,
+		"name":                    i.Name,
+		"notes":                   i.Notes,
+		"self_service_allowed":    bool(i.SelfServiceAllowed),
+		"type":                    i.Type,
+	}
+}
+
+func (s *AdminServer) handleIntegrations(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var integrations []interface{}
+			for _, i := range s.integs {
+				integrations = append(integrations, integrationJSON(i))
+			}
+			return writePage(w, params, integrations, maxAdminsPageSize)
+		case http.MethodPost:
+			if params.Get("name") == "" {
+				return missingParam("name")
+			}
+			if params.Get("type") == "" {
+				return missingParam("type")
+			}
+			integration := &admin.Integration{
+				IntegrationKey: s.ids.id("DI"),
+				SecretKey:      s.secretKey(),
+				Type:           params.Get("type"),
+			}
+			if err := s.applyIntegrationParams(integration, params); err != nil {
+				return err
+			}
+			s.integs = append(s.integs, integration)
+			response := integrationJSON(integration)
+			response["secret_key"] = integration.SecretKey
+			writeJSON(w, response)
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	integration := s.findIntegration(path[0])
+	if integration == nil {
+		return notFound()
+	}
+	if len(path) == 2 && path[1] == "skey" {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		writeJSON(w, map[string]interface{}{"secret_key": integration.SecretKey})
+		return nil
+	}
+	if len(path) != 1 {
+		return notFound()
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, integrationJSON(integration))
+		return nil
+	case http.MethodPost:
+		if _, ok := params["type"]; ok {
+			return invalidParam("type")
+		}
+		updated := *integration
+		if err := s.applyIntegrationParams(&updated, params); err != nil {
+			return err
+		}
+		if values, ok := params["reset_secret_key"]; ok {
+			if values[0] != "1" {
+				return invalidParam("reset_secret_key")
+			}
+			updated.SecretKey = s.secretKey()
+		}
+		*integration = updated
+		writeJSON(w, integrationJSON(integration))
+		return nil
+	case http.MethodDelete:
+		for i, integ := range s.integs {
+			if integ == integration {
+				s.integs = append(s.integs[:i:i], s.integs[i+1:]...)
+				break
+			}
+		}
+		for _, u := range s.adminUnits {
+			u.Integrations, _ = removeID(u.Integrations, integration.IntegrationKey)
+		}
+		for _, p := range s.policies {
+			p.apps, _ = removeID(p.apps, integration.IntegrationKey)
+			delete(p.groups, integration.IntegrationKey)
+		}
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
+
+// applyIntegrationParams sets the fields of an integration from a create or
+// modify request.
+func (s *AdminServer) applyIntegrationParams(integration *admin.Integration, params url.Values) *apiError {
+	if values, ok := params["name"]; ok {
+		if values[0] == "" {
+			return invalidParam("name")
+		}
+		integration.Name = values[0]
+	}
+	if values, ok := params["notes"]; ok {
+		integration.Notes = values[0]
+	}
+	if values, ok := params["greeting"]; ok {
+		integration.Greeting = values[0]
+	}
+	if values, ok := params["groups_allowed"]; ok {
+		groups := []string{}
+		if values[0] != "" {
+			groups = strings.Split(values[0], ",")
+		}
+		for _, id := range groups {
+			if s.findGroup(id) == nil {
+				return invalidParam("groups_allowed")
+			}
+		}
+		integration.GroupsAllowed = groups
+	}
+	for name, field := range map[string]*admin.Flag{
+		"adminapi_admins":         &integration.AdminAPIAdmins,
+		"adminapi_info":           &integration.AdminAPIInfo,
+		"adminapi_integrations":   &integration.AdminAPIIntegrations,
+		"adminapi_read_log":       &integration.AdminAPIReadLog,
+		"adminapi_read_resource":  &integration.AdminAPIReadResource,
+		"adminapi_settings":       &integration.AdminAPISettings,
+		"adminapi_write_resource": &integration.AdminAPIWriteResource,
+		"self_service_allowed":    &integration.SelfServiceAllowed,
+	} {
+		if values, ok := params[name]; ok {
+			switch values[0] {
+			case "1":
+				*field = true
+			case "0":
+				*field = false
+			default:
+				return invalidParam(name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package duotest
+
+import (
+	"reflect"
+	"testing"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerIntegrations(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	staff := srv.AddGroup(admin.Group{Name: "Staff"})
+	client := srv.Client()
+
+	created, err := client.CreateIntegration(admin.IntegrationUpdate{
+		Name:                 admin.String("Provisioning"),
+		Type:                 "adminapi",
+		GroupsAllowed:        []string{staff.GroupID},
+		AdminAPIReadResource: admin.NewFlag(true),
+	})
+	if err != nil || created.Stat != "OK" {
+		t.Fatalf("Unexpected CreateIntegration result %+v, %v", created, err)
+	}
+	integration := created.Response
+	if integration.SecretKey == "" || !bool(integration.AdminAPIReadResource) || bool(integration.AdminAPIAdmins) ||
+		!reflect.DeepEqual(integration.GroupsAllowed, []string{staff.GroupID}) {
+		t.Errorf("Unexpected created integration %+v", integration)
+	}
+	if missing, err := client.CreateIntegration(admin.IntegrationUpdate{
+		Name:          admin.String("Nobody"),
+		Type:          "websdk",
+		GroupsAllowed: []string{"DGMISSING"},
+	}); err != nil || *missing.Code != CodeInvalidParams {
+		t.Errorf("Expected a missing group to be rejected, got %+v, %v", missing, err)
+	}
+
+	modified, err := client.ModifyIntegration(integration.IntegrationKey, admin.IntegrationUpdate{
+		Notes:         admin.String("Nightly sync"),
+		GroupsAllowed: []string{},
+	})
+	if err != nil || modified.Response.Notes != "Nightly sync" || len(modified.Response.GroupsAllowed) != 0 ||
+		modified.Response.SecretKey != "" {
+		t.Errorf("Unexpected ModifyIntegration result %+v, %v", modified, err)
+	}
+
+	old := admin.New(*duoapi.NewDuoApi(integration.IntegrationKey, integration.SecretKey, srv.Host(), "duotest", duoapi.SetInsecure()))
+	if listed, err := old.GetIntegrations(); err != nil || len(listed.Response) != 1 {
+		t.Fatalf("Expected the integration to sign requests, got %+v, %v", listed, err)
+	}
+	secret, err := client.RotateIntegrationSecretKey(integration.IntegrationKey)
+	if err != nil {
+		t.Fatalf("Unexpected error from RotateIntegrationSecretKey call %v", err)
+	}
+	if secret == integration.SecretKey || secret != srv.Integrations()[0].SecretKey {
+		t.Errorf("Unexpected rotated secret key %q", secret)
+	}
+	if stale, err := old.GetIntegrations(); err != nil || *stale.Code != CodeInvalidSignature {
+		t.Errorf("Expected the old secret key to be rejected, got %+v, %v", stale, err)
+	}
+	rotated := admin.New(*duoapi.NewDuoApi(integration.IntegrationKey, secret, srv.Host(), "duotest", duoapi.SetInsecure()))
+	if skey, err := rotated.GetIntegrationSecretKey(integration.IntegrationKey); err != nil || skey.Response.SecretKey != secret {
+		t.Errorf("Unexpected GetIntegrationSecretKey result %+v, %v", skey, err)
+	}
+
+	if result, err := client.DeleteIntegration(integration.IntegrationKey); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteIntegration result %+v, %v", result, err)
+	}
+	if gone, err := client.GetIntegration(integration.IntegrationKey); err != nil || *gone.Code != CodeNotFound {
+		t.Errorf("Expected a deleted integration to be missing, got %+v, %v", gone, err)
+	}
+}
@@ -0,0 +1,240 @@
+package duotest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// Activation code lifetimes, in seconds.
+const (
+	defaultActivationSecs = 86400
+	maxActivationSecs     = 604800
+)
+
+// AddPhone stores a copy of phone, assigning it a phone ID if it has none,
+// and returns the stored phone.  Its Users are ignored.
+func (s *AdminServer) AddPhone(phone admin.Phone) admin.Phone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if phone.PhoneID == "" {
+		phone.PhoneID = s.ids.id("DP")
+	}
+	phone.Users = nil
+	s.phones = append(s.phones, &phone)
+	return phone
+}
+
+// Phones returns copies of the stored phones, in creation order.
+func (s *AdminServer) Phones() []admin.Phone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	phones := make([]admin.Phone, 0, len(s.phones))
+	for _, p := range s.phones {
+		phones = append(phones, *p)
+	}
+	return phones
+}
+
+func (s *AdminServer) findPhone(phoneID string) *admin.Phone {
+	for _, p := range s.phones {
+		if p.PhoneID == phoneID {
+			return p
+		}
+	}
+	return nil
+}
+
+func phoneJSON(p *admin.Phone, users []*admin.User) map[string]interface{} {
+	capabilities := p.Capabilities
+	if capabilities == nil {
+		capabilities = []string{}
+	}
+	phone := map[string]interface{}{
+		"activated":          p.Activated,
+		"capabilities":       capabilities,
+		"encrypted":          p.Encrypted,
+		"extension":          p.Extension,
+		"fingerprint":        p.Fingerprint,
+		"last_seen":          p.LastSeen,
+		"model":              p.Model,
+		"name":               p.Name,
+		"number":             p.Number,
+		"phone_id":           p.PhoneID,
+		"platform":           p.Platform,
+		"postdelay":          p.Postdelay,
+		"predelay":           p.Predelay,
+		"screenlock":         p.Screenlock,
+		"sms_passcodes_sent": p.SMSPasscodesSent,
+		"type":               p.Type,
+	}
+	if users != nil {
+		list := []interface{}{}
+		for _, u := range users {
+			list = append(list, userSummaryJSON(u))
+		}
+		phone["users"] = list
+	}
+	return phone
+}
+
+func (s *AdminServer) handlePhones(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			number := params.Get("number")
+			extension := params.Get("extension")
+			var phones []interface{}
+			for _, p := range s.phones {
+				if number != "" && p.Number != number {
+					continue
+				}
+				if extension != "" && p.Extension != extension {
+					continue
+				}
+				phones = append(phones, phoneJSON(p, s.usersOf(s.userPhones, p.PhoneID)))
+			}
+			return writePage(w, params, phones, maxPageSize)
+		case http.MethodPost:
+			phone := &admin.Phone{PhoneID: s.ids.id("DP")}
+			applyPhoneParams(phone, params)
+			s.phones = append(s.phones, phone)
+			writeJSON(w, phoneJSON(phone, []*admin.User{}))
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	phone := s.findPhone(path[0])
+	if phone == nil || len(path) > 2 {
+		return notFound()
+	}
+	if len(path) == 2 {
+		if r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		return s.handlePhoneAction(w, phone, params, path[1])
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, phoneJSON(phone, s.usersOf(s.userPhones, phone.PhoneID)))
+		return nil
+	case http.MethodPost:
+		applyPhoneParams(phone, params)
+		writeJSON(w, phoneJSON(phone, s.usersOf(s.userPhones, phone.PhoneID)))
+		return nil
+	case http.MethodDelete:
+		for i, p := range s.phones {
+			if p == phone {
+				s.phones = append(s.phones[:i:i], s.phones[i+1:]...)
+				break
+			}
+		}
+		for userID, ids := range s.userPhones {
+			s.userPhones[userID], _ = removeID(ids, phone.PhoneID)
+		}
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
+
+// handlePhoneAction serves the activation and SMS passcode endpoints of a
+// phone.
+func (s *AdminServer) handlePhoneAction(w http.ResponseWriter, phone *admin.Phone, params url.Values, action string) *apiError {
+	switch action {
+	case "activation_url", "send_sms_activation":
+	case "send_sms_passcodes":
+		if phone.Number == "" {
+			return invalidParam("number")
+		}
+		phone.SMSPasscodesSent = true
+		writeJSON(w, "")
+		return nil
+	default:
+		return notFound()
+	}
+
+	if !strings.EqualFold(string(phone.Type), string(admin.PhoneTypeMobile)) {
+		return invalidParam("type")
+	}
+	validSecs := uint64(defaultActivationSecs)
+	if values, ok := params["valid_secs"]; ok {
+		secs, err := strconv.ParseUint(values[0], 10, 64)
+		if err != nil || secs > maxActivationSecs {
+			return invalidParam("valid_secs")
+		}
+		validSecs = secs
+	}
+	install := params.Get("install")
+	if install != "" && install != "0" && install != "1" {
+		return invalidParam("install")
+	}
+
+	code := s.ids.id("")
+	activationURL := fmt.Sprintf("https://%s/iphone/%s", s.Host(), code)
+	installationURL := fmt.Sprintf("https://%s/install/%s", s.Host(), code)
+	response := map[string]interface{}{
+		"activation_barcode": fmt.Sprintf("https://%s/frame/qr?value=%s", s.Host(), code),
+		"valid_secs":         validSecs,
+	}
+
+	if action == "activation_url" {
+		response["activation_url"] = activationURL
+		if install == "1" {
+			response["installation_url"] = installationURL
+		}
+		writeJSON(w, response)
+		return nil
+	}
+
+	if phone.Number == "" {
+		return invalidParam("number")
+	}
+	activationMsg := "To activate the app, tap and open this link: <acturl>"
+	if values, ok := params["activation_msg"]; ok {
+		if !strings.Contains(values[0], "<acturl>") {
+			return invalidParam("activation_msg")
+		}
+		activationMsg = values[0]
+	}
+	response["activation_msg"] = strings.Replace(activationMsg, "<acturl>", activationURL, -1)
+	if install == "1" {
+		installationMsg := "Welcome to Duo! Tap the link to install the app: <insturl>"
+		if values, ok := params["installation_msg"]; ok {
+			if !strings.Contains(values[0], "<insturl>") {
+				return invalidParam("installation_msg")
+			}
+			installationMsg = values[0]
+		}
+		response["installation_msg"] = strings.Replace(installationMsg, "<insturl>", installationURL, -1)
+	}
+	writeJSON(w, response)
+	return nil
+}
+
+// applyPhoneParams sets the phone attributes present in params.
+func applyPhoneParams(phone *admin.Phone, params url.Values) {
+	fields := map[string]*string{
+		"number":    &phone.Number,
+		"name":      &phone.Name,
+		"extension": &phone.Extension,
+		"predelay":  &phone.Predelay,
+		"postdelay": &phone.Postdelay,
+	}
+	for name, field := range fields {
+		if values, ok := params[name]; ok {
+			*field = values[0]
+		}
+	}
+	if values, ok := params["type"]; ok {
+		phone.Type = values[0]
+	}
+	if values, ok := params["platform"]; ok {
+		phone.Platform = values[0]
+	}
+}
@@ -0,0 +1,68 @@
+package duotest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerPhoneLifecycle(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	user := srv.AddUser(admin.User{Username: "jsmith"})
+	client := srv.Client()
+
+	created, err := client.CreatePhone(admin.PhoneUpdate{Number: admin.String("+15555550100"), Type: admin.NewPhoneType(admin.PhoneTypeLandline)})
+	if err != nil || created.Stat != "OK" {
+		t.Fatalf("Unexpected CreatePhone result %+v, %v", created, err)
+	}
+	phoneID := created.Response.PhoneID
+
+	if result, err := client.AssociatePhoneWithUser(user.UserID, phoneID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected AssociatePhoneWithUser result %v, %v", result, err)
+	}
+
+	landline, err := client.CreateActivationURL(phoneID)
+	if err != nil {
+		t.Fatalf("Unexpected error from CreateActivationURL call %v", err)
+	}
+	if landline.Stat != "FAIL" || *landline.Code != CodeInvalidParams {
+		t.Errorf("Expected a landline to be rejected, got %+v", landline.StatResult)
+	}
+
+	modified, err := client.ModifyPhone(phoneID, admin.PhoneUpdate{Type: admin.NewPhoneType(admin.PhoneTypeMobile), Platform: admin.NewPhonePlatform(admin.PhonePlatformGoogleAndroid)})
+	if err != nil || modified.Response.Type != string(admin.PhoneTypeMobile) {
+		t.Fatalf("Unexpected ModifyPhone result %+v, %v", modified, err)
+	}
+
+	activation, err := client.CreateActivationURL(phoneID, admin.ActivationValidSecs(3600), admin.ActivationInstall(true))
+	if err != nil {
+		t.Fatalf("Unexpected error from CreateActivationURL call %v", err)
+	}
+	if activation.Response.ValidSecs != 3600 || activation.Response.ActivationURL == "" || activation.Response.InstallationURL == "" {
+		t.Errorf("Unexpected activation %+v", activation.Response)
+	}
+
+	sms, err := client.SendSMSActivation(phoneID, admin.SMSActivationMsg("Activate: <acturl>"))
+	if err != nil {
+		t.Fatalf("Unexpected error from SendSMSActivation call %v", err)
+	}
+	if !strings.HasPrefix(sms.Response.ActivationMsg, "Activate: https://") || sms.Response.InstallationMsg != "" {
+		t.Errorf("Unexpected SMS activation %+v", sms.Response)
+	}
+
+	if result, err := client.SendSMSPasscodes(phoneID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected SendSMSPasscodes result %v, %v", result, err)
+	}
+	if !srv.Phones()[0].SMSPasscodesSent {
+		t.Error("Expected SMS passcodes to be recorded as sent")
+	}
+
+	if result, err := client.DisassociatePhoneFromUser(user.UserID, phoneID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DisassociatePhoneFromUser result %v, %v", result, err)
+	}
+	if len(srv.Users()[0].Phones) != 0 {
+		t.Errorf("Expected no phones, got %+v", srv.Users()[0].Phones)
+	}
+}
@@ -0,0 +1,321 @@
+package duotest
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// storedPolicy is a policy with the applications, and the groups of users
+// of applications, it is assigned to.
+type storedPolicy struct {
+	policy admin.Policy
+	apps   []string
+	groups map[string][]string
+}
+
+// Policies returns copies of the stored policies, the global policy first
+// and the rest in creation order.
+func (s *AdminServer) Policies() []admin.Policy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	policies := make([]admin.Policy, 0, len(s.policies))
+	for _, p := range s.policies {
+		policy := p.policy
+		policy.Sections = copySections(p.policy.Sections)
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+func (s *AdminServer) findPolicy(policyKey string) *storedPolicy {
+	for _, p := range s.policies {
+		if p.policy.PolicyKey == policyKey {
+			return p
+		}
+	}
+	return nil
+}
+
+// copySections copies a policy's sections, so that updating one policy
+// leaves others unchanged.
+func copySections(sections map[string]admin.PolicySection) map[string]admin.PolicySection {
+	copied := make(map[string]admin.PolicySection, len(sections))
+	for name, section := range sections {
+		settings := make(admin.PolicySection, len(section))
+		for k, v := range section {
+			settings[k] = v
+		}
+		copied[name] = settings
+	}
+	return copied
+}
+
+func policyJSON(p *storedPolicy) map[string]interface{} {
+	return map[string]interface{}{
+		"is_global_policy": p.policy.IsGlobalPolicy,
+		"policy_key":       p.policy.PolicyKey,
+		"policy_name":      p.policy.PolicyName,
+		"sections":         p.policy.Sections,
+	}
+}
+
+func (s *AdminServer) handlePolicies(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var policies []interface{}
+			for _, p := range s.policies {
+				policies = append(policies, policyJSON(p))
+			}
+			return writePage(w, params, policies, maxGroupsPageSize)
+		case http.MethodPost:
+			var update admin.PolicyUpdate
+			if err := decodeJSON(r, &update); err != nil {
+				return err
+			}
+			if update.PolicyName == nil {
+				return missingParam("policy_name")
+			}
+			if update.SectionsToDelete != nil {
+				return invalidParam("sections_to_delete")
+			}
+			policy := &storedPolicy{
+				policy: admin.Policy{PolicyKey: s.ids.id("PO"), Sections: map[string]admin.PolicySection{}},
+				groups: map[string][]string{},
+			}
+			if err := s.updatePolicy(policy, update); err != nil {
+				return err
+			}
+			s.policies = append(s.policies, policy)
+			writeJSON(w, policyJSON(policy))
+			return nil
+		}
+		return methodNotAllowed()
+	}
+	if len(path) != 1 {
+		return notFound()
+	}
+
+	switch path[0] {
+	case "global":
+		if r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		writeJSON(w, policyJSON(s.policies[0]))
+		return nil
+	case "copy":
+		if r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		return s.copyPolicy(w, r)
+	case "calculate":
+		if r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		return s.calculatePolicy(w, params)
+	}
+
+	policy := s.findPolicy(path[0])
+	if policy == nil {
+		return notFound()
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, policyJSON(policy))
+		return nil
+	case http.MethodPut:
+		var update admin.PolicyUpdate
+		if err := decodeJSON(r, &update); err != nil {
+			return err
+		}
+		if err := s.updatePolicy(policy, update); err != nil {
+			return err
+		}
+		writeJSON(w, policyJSON(policy))
+		return nil
+	case http.MethodDelete:
+		if policy.policy.IsGlobalPolicy {
+			return invalidParam("policy_key")
+		}
+		for i, p := range s.policies {
+			if p == policy {
+				s.policies = append(s.policies[:i:i], s.policies[i+1:]...)
+				break
+			}
+		}
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
+
+// updatePolicy checks a create or update request, then applies it to
+// policy.  An application, or a group in an application, has one policy,
+// so assigning policy moves it from any other.
+func (s *AdminServer) updatePolicy(policy *storedPolicy, update admin.PolicyUpdate) *apiError {
+	global := policy.policy.IsGlobalPolicy
+	if update.PolicyName != nil && (*update.PolicyName == "" || global) {
+		return invalidParam("policy_name")
+	}
+	if apps := update.ApplyToApps; apps != nil {
+		if global {
+			return invalidParam("apply_to_apps")
+		}
+		for _, list := range [][]string{apps.AffectedIntegrationKeys, apps.UnaffectedIntegrationKeys} {
+			for _, ikey := range list {
+				if s.findIntegration(ikey) == nil {
+					return invalidParam("apply_to_apps")
+				}
+			}
+		}
+	}
+	if groups := update.ApplyToGroupsInApps; groups != nil {
+		if global {
+			return invalidParam("apply_to_groups_in_apps")
+		}
+		for _, list := range [][]admin.PolicyGroupsInApp{groups.Apply, groups.Unapply} {
+			for _, g := range list {
+				if s.findIntegration(g.IntegrationKey) == nil {
+					return invalidParam("apply_to_groups_in_apps")
+				}
+				for _, id := range g.GroupIDs {
+					if s.findGroup(id) == nil {
+						return invalidParam("apply_to_groups_in_apps")
+					}
+				}
+			}
+		}
+	}
+	if update.SectionsToDelete != nil && global {
+		return invalidParam("sections_to_delete")
+	}
+
+	if update.PolicyName != nil {
+		policy.policy.PolicyName = *update.PolicyName
+	}
+	if apps := update.ApplyToApps; apps != nil {
+		for _, ikey := range apps.AffectedIntegrationKeys {
+			for _, p := range s.policies {
+				p.apps, _ = removeID(p.apps, ikey)
+			}
+			policy.apps = addID(policy.apps, ikey)
+		}
+		for _, ikey := range apps.UnaffectedIntegrationKeys {
+			policy.apps, _ = removeID(policy.apps, ikey)
+		}
+	}
+	if groups := update.ApplyToGroupsInApps; groups != nil {
+		for _, g := range groups.Apply {
+			for _, id := range g.GroupIDs {
+				for _, p := range s.policies {
+					p.groups[g.IntegrationKey], _ = removeID(p.groups[g.IntegrationKey], id)
+				}
+				policy.groups[g.IntegrationKey] = addID(policy.groups[g.IntegrationKey], id)
+			}
+		}
+		for _, g := range groups.Unapply {
+			for _, id := range g.GroupIDs {
+				policy.groups[g.IntegrationKey], _ = removeID(policy.groups[g.IntegrationKey], id)
+			}
+		}
+	}
+	for name, settings := range update.Sections {
+		section := policy.policy.Sections[name]
+		if section == nil {
+			section = admin.PolicySection{}
+			policy.policy.Sections[name] = section
+		}
+		for k, v := range settings {
+			section[k] = v
+		}
+	}
+	for _, name := range update.SectionsToDelete {
+		delete(policy.policy.Sections, name)
+	}
+	return nil
+}
+
+func (s *AdminServer) copyPolicy(w http.ResponseWriter, r *http.Request) *apiError {
+	var request struct {
+		PolicyKey string   `json:"policy_key"`
+		NewNames  []string `json:"new_policy_names_list"`
+	}
+	if err := decodeJSON(r, &request); err != nil {
+		return err
+	}
+	if request.PolicyKey == "" {
+		return missingParam("policy_key")
+	}
+	source := s.findPolicy(request.PolicyKey)
+	if source == nil {
+		return invalidParam("policy_key")
+	}
+	names := request.NewNames
+	if len(names) == 0 {
+		names = []string{"Copy of " + source.policy.PolicyName}
+	}
+	for _, name := range names {
+		if name == "" {
+			return invalidParam("new_policy_names_list")
+		}
+	}
+
+	copies := []interface{}{}
+	for _, name := range names {
+		copied := &storedPolicy{
+			policy: admin.Policy{
+				PolicyKey:  s.ids.id("PO"),
+				PolicyName: name,
+				Sections:   copySections(source.policy.Sections),
+			},
+			groups: map[string][]string{},
+		}
+		s.policies = append(s.policies, copied)
+		copies = append(copies, policyJSON(copied))
+	}
+	writeJSON(w, copies)
+	return nil
+}
+
+// calculatePolicy responds with the global policy's sections, replaced by
+// those of the application's policy and then of the policies of the user's
+// groups in the application.
+func (s *AdminServer) calculatePolicy(w http.ResponseWriter, params url.Values) *apiError {
+	ikey, userID := params.Get("integration_key"), params.Get("user_id")
+	if ikey == "" {
+		return missingParam("integration_key")
+	}
+	if userID == "" {
+		return missingParam("user_id")
+	}
+	if s.findIntegration(ikey) == nil {
+		return invalidParam("integration_key")
+	}
+	if s.findUser(userID) == nil {
+		return invalidParam("user_id")
+	}
+
+	sections := copySections(s.policies[0].policy.Sections)
+	override := func(p *storedPolicy) {
+		for name, section := range copySections(p.policy.Sections) {
+			sections[name] = section
+		}
+	}
+	for _, p := range s.policies {
+		if matchesAny(p.apps, ikey) {
+			override(p)
+		}
+	}
+	for _, p := range s.policies {
+		for _, id := range s.userGroups[userID] {
+			if matchesAny(p.groups[ikey], id) {
+				override(p)
+				break
+			}
+		}
+	}
+	writeJSON(w, map[string]interface{}{"sections": sections})
+	return nil
+}
@@ -0,0 +1,94 @@
+package duotest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerPolicies(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	jsmith := srv.AddUser(admin.User{Username: "jsmith"})
+	rjones := srv.AddUser(admin.User{Username: "rjones"})
+	contractors := srv.AddGroup(admin.Group{Name: "Contractors"})
+	srv.AssociateGroup(rjones.UserID, contractors.GroupID)
+	vpn := srv.AddIntegration(admin.Integration{Name: "VPN", Type: "websdk"})
+	client := srv.Client()
+
+	global, err := client.UpdatePolicy(srv.Policies()[0].PolicyKey, admin.PolicyUpdate{
+		Sections: map[string]admin.PolicySection{
+			"authentication_methods": {"allowed_auth_list": []string{"duo-push", "sms"}},
+			"new_user_policy":        {"new_user_behavior": "enroll"},
+		},
+	})
+	if err != nil || global.Stat != "OK" || !global.Response.IsGlobalPolicy {
+		t.Fatalf("Unexpected UpdatePolicy result %+v, %v", global, err)
+	}
+	if result, err := client.DeletePolicy(global.Response.PolicyKey); err != nil || *result.Code != CodeInvalidParams {
+		t.Errorf("Expected the global policy to be kept, got %+v, %v", result, err)
+	}
+
+	created, err := client.CreatePolicy(admin.PolicyUpdate{
+		PolicyName:  admin.String("VPN"),
+		ApplyToApps: &admin.PolicyApps{AffectedIntegrationKeys: []string{vpn.IntegrationKey}},
+		Sections: map[string]admin.PolicySection{
+			"new_user_policy": {"new_user_behavior": "deny"},
+		},
+	})
+	if err != nil || created.Stat != "OK" {
+		t.Fatalf("Unexpected CreatePolicy result %+v, %v", created, err)
+	}
+	copies, err := client.CopyPolicy(created.Response.PolicyKey, []string{"VPN contractors"})
+	if err != nil || len(copies.Response) != 1 || copies.Response[0].PolicyName != "VPN contractors" {
+		t.Fatalf("Unexpected CopyPolicy result %+v, %v", copies, err)
+	}
+	strict := copies.Response[0]
+	if _, err := client.UpdatePolicy(strict.PolicyKey, admin.PolicyUpdate{
+		ApplyToGroupsInApps: &admin.PolicyGroupsInApps{
+			Apply: []admin.PolicyGroupsInApp{{IntegrationKey: vpn.IntegrationKey, GroupIDs: []string{contractors.GroupID}}},
+		},
+		Sections: map[string]admin.PolicySection{
+			"authentication_methods": {"allowed_auth_list": []string{"webauthn-roaming"}},
+		},
+	}); err != nil {
+		t.Fatalf("Unexpected error from UpdatePolicy call %v", err)
+	}
+
+	policies, err := client.GetPolicies()
+	if err != nil || len(policies.Response) != 3 {
+		t.Fatalf("Unexpected GetPolicies result %+v, %v", policies, err)
+	}
+
+	calculated := map[string][]interface{}{}
+	for _, user := range []admin.User{jsmith, rjones} {
+		effective, err := client.CalculatePolicy(vpn.IntegrationKey, user.UserID)
+		if err != nil || effective.Stat != "OK" {
+			t.Fatalf("Unexpected CalculatePolicy result %+v, %v", effective, err)
+		}
+		sections := effective.Response.Sections
+		if sections["new_user_policy"]["new_user_behavior"] != "deny" {
+			t.Errorf("Expected the VPN policy for %s, got %+v", user.Username, sections)
+		}
+		calculated[user.Username], _ = sections["authentication_methods"]["allowed_auth_list"].([]interface{})
+	}
+	want := map[string][]interface{}{
+		"jsmith": {"duo-push", "sms"},
+		"rjones": {"webauthn-roaming"},
+	}
+	if !reflect.DeepEqual(calculated, want) {
+		t.Errorf("Expected allowed methods %v, got %v", want, calculated)
+	}
+
+	if result, err := client.DeletePolicy(strict.PolicyKey); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DeletePolicy result %+v, %v", result, err)
+	}
+	if gone, err := client.GetPolicy(strict.PolicyKey); err != nil || *gone.Code != CodeNotFound {
+		t.Errorf("Expected a deleted policy to be missing, got %+v, %v", gone, err)
+	}
+	fetched, err := client.GetGlobalPolicy()
+	if err != nil || fetched.Response.PolicyKey != global.Response.PolicyKey {
+		t.Errorf("Unexpected GetGlobalPolicy result %+v, %v", fetched, err)
+	}
+}
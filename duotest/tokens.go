@@ -0,0 +1,342 @@
+package duotest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+type u2fToken struct {
+	token  admin.U2FToken
+	userID string
+}
+
+type webAuthnCredential struct {
+	credential admin.WebAuthnCredential
+	userID     string
+}
+
+// AddToken stores a copy of token, assigning it a token ID if it has none,
+// and returns the stored token.  Its Users are ignored.
+func (s *AdminServer) AddToken(token admin.Token) admin.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token.TokenID == "" {
+		token.TokenID = s.ids.id("DH")
+	}
+	token.Users = nil
+	s.tokens = append(s.tokens, &token)
+	return token
+}
+
+// AddU2FToken registers a U2F token to the user with userID and returns it.
+func (s *AdminServer) AddU2FToken(userID string) (admin.U2FToken, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findUser(userID) == nil {
+		return admin.U2FToken{}, false
+	}
+	token := &u2fToken{
+		token: admin.U2FToken{
+			DateAdded:      uint64(time.Now().Unix()),
+			RegistrationID: s.ids.id("D2"),
+		},
+		userID: userID,
+	}
+	s.u2fTokens = append(s.u2fTokens, token)
+	return token.token, true
+}
+
+// AddWebAuthnCredential registers a WebAuthn credential with the given name
+// to the user with userID and returns it.
+func (s *AdminServer) AddWebAuthnCredential(userID, name string) (admin.WebAuthnCredential, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findUser(userID) == nil {
+		return admin.WebAuthnCredential{}, false
+	}
+	credential := &webAuthnCredential{
+		credential: admin.WebAuthnCredential{
+			CredentialName: name,
+			DateAdded:      uint64(time.Now().Unix()),
+			Label:          "Security Key",
+			WebAuthnKey:    s.ids.id("WA"),
+		},
+		userID: userID,
+	}
+	s.webAuthn = append(s.webAuthn, credential)
+	return credential.credential, true
+}
+
+// Tokens returns copies of the stored hardware tokens, in creation order.
+func (s *AdminServer) Tokens() []admin.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens := make([]admin.Token, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		tokens = append(tokens, *t)
+	}
+	return tokens
+}
+
+func (s *AdminServer) findToken(tokenID string) *admin.Token {
+	for _, t := range s.tokens {
+		if t.TokenID == tokenID {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *AdminServer) findU2FToken(registrationID string) *u2fToken {
+	for _, t := range s.u2fTokens {
+		if t.token.RegistrationID == registrationID {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *AdminServer) findWebAuthnCredential(webAuthnKey string) *webAuthnCredential {
+	for _, c := range s.webAuthn {
+		if c.credential.WebAuthnKey == webAuthnKey {
+			return c
+		}
+	}
+	return nil
+}
+
+func tokenJSON(t *admin.Token, users []*admin.User) map[string]interface{} {
+	token := map[string]interface{}{
+		"serial":   t.Serial,
+		"token_id": t.TokenID,
+		"type":     t.Type,
+	}
+	if t.TOTPStep != nil {
+		token["totp_step"] = *t.TOTPStep
+	} else {
+		token["totp_step"] = nil
+	}
+	if users != nil {
+		list := []interface{}{}
+		for _, u := range users {
+			list = append(list, userSummaryJSON(u))
+		}
+		token["users"] = list
+	}
+	return token
+}
+
+func (s *AdminServer) u2fTokenJSON(t *u2fToken) map[string]interface{} {
+	token := map[string]interface{}{
+		"date_added":      t.token.DateAdded,
+		"registration_id": t.token.RegistrationID,
+	}
+	if u := s.findUser(t.userID); u != nil {
+		token["user"] = userSummaryJSON(u)
+	}
+	return token
+}
+
+func webAuthnCredentialJSON(c *webAuthnCredential, user *admin.User) map[string]interface{} {
+	credential := map[string]interface{}{
+		"credential_name": c.credential.CredentialName,
+		"date_added":      c.credential.DateAdded,
+		"label":           c.credential.Label,
+		"webauthnkey":     c.credential.WebAuthnKey,
+	}
+	if user != nil {
+		credential["user"] = userSummaryJSON(user)
+	}
+	return credential
+}
+
+func (s *AdminServer) handleTokens(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			typ := admin.TokenType(params.Get("type"))
+			serial := params.Get("serial")
+			if (typ == "") != (serial == "") {
+				return missingParam("type and serial")
+			}
+			var tokens []interface{}
+			for _, t := range s.tokens {
+				if typ != "" && (admin.TokenType(t.Type) != typ || t.Serial != serial) {
+					continue
+				}
+				tokens = append(tokens, tokenJSON(t, s.usersOf(s.userTokens, t.TokenID)))
+			}
+			return writePage(w, params, tokens, maxPageSize)
+		case http.MethodPost:
+			typ := admin.TokenType(params.Get("type"))
+			serial := params.Get("serial")
+			if typ == "" {
+				return missingParam("type")
+			}
+			if !typ.Valid() {
+				return invalidParam("type")
+			}
+			if serial == "" {
+				return missingParam("serial")
+			}
+			switch typ {
+			case admin.TokenTypeHOTP6, admin.TokenTypeHOTP8, admin.TokenTypeTOTP6, admin.TokenTypeTOTP8:
+				if params.Get("secret") == "" {
+					return missingParam("secret")
+				}
+			case admin.TokenTypeYubiKey:
+				if params.Get("private_id") == "" || params.Get("aes_key") == "" {
+					return missingParam("private_id and aes_key")
+				}
+			default:
+				return invalidParam("type")
+			}
+			for _, t := range s.tokens {
+				if admin.TokenType(t.Type) == typ && t.Serial == serial {
+					return duplicate("serial")
+				}
+			}
+			token := &admin.Token{TokenID: s.ids.id("DH"), Type: string(typ), Serial: serial}
+			if step := params.Get("totp_step"); step != "" {
+				n, err := strconv.Atoi(step)
+				if err != nil {
+					return invalidParam("totp_step")
+				}
+				token.TOTPStep = &n
+			}
+			s.tokens = append(s.tokens, token)
+			writeJSON(w, tokenJSON(token, []*admin.User{}))
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	token := s.findToken(path[0])
+	if token == nil || len(path) > 2 || (len(path) == 2 && path[1] != "resync") {
+		return notFound()
+	}
+	if len(path) == 2 {
+		if r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		return resyncToken(w, token, params)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, tokenJSON(token, s.usersOf(s.userTokens, token.TokenID)))
+		return nil
+	case http.MethodDelete:
+		for i, t := range s.tokens {
+			if t == token {
+				s.tokens = append(s.tokens[:i:i], s.tokens[i+1:]...)
+				break
+			}
+		}
+		for userID, ids := range s.userTokens {
+			s.userTokens[userID], _ = removeID(ids, token.TokenID)
+		}
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
+
+// resyncToken accepts any three codes with as many digits as the token
+// generates, since the fake does not keep token secrets.
+func resyncToken(w http.ResponseWriter, token *admin.Token, params url.Values) *apiError {
+	digits := 0
+	switch admin.TokenType(token.Type) {
+	case admin.TokenTypeHOTP6, admin.TokenTypeTOTP6:
+		digits = 6
+	case admin.TokenTypeHOTP8, admin.TokenTypeTOTP8:
+		digits = 8
+	default:
+		return invalidParam("token_id")
+	}
+	for _, name := range []string{"code1", "code2", "code3"} {
+		code := params.Get(name)
+		if code == "" {
+			return missingParam(name)
+		}
+		if len(code) != digits || strings.Trim(code, "0123456789") != "" {
+			return invalidParam(name)
+		}
+	}
+	writeJSON(w, "")
+	return nil
+}
+
+// U2F tokens
+
+func (s *AdminServer) handleU2FTokens(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		var tokens []interface{}
+		for _, t := range s.u2fTokens {
+			tokens = append(tokens, s.u2fTokenJSON(t))
+		}
+		return writePage(w, params, tokens, maxPageSize)
+	}
+
+	token := s.findU2FToken(path[0])
+	if token == nil || len(path) != 1 {
+		return notFound()
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, s.u2fTokenJSON(token))
+		return nil
+	case http.MethodDelete:
+		for i, t := range s.u2fTokens {
+			if t == token {
+				s.u2fTokens = append(s.u2fTokens[:i:i], s.u2fTokens[i+1:]...)
+				break
+			}
+		}
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
+
+// WebAuthn credentials
+
+func (s *AdminServer) handleWebAuthnCredentials(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		var credentials []interface{}
+		for _, c := range s.webAuthn {
+			credentials = append(credentials, webAuthnCredentialJSON(c, s.findUser(c.userID)))
+		}
+		return writePage(w, params, credentials, maxPageSize)
+	}
+
+	credential := s.findWebAuthnCredential(path[0])
+	if credential == nil || len(path) != 1 {
+		return notFound()
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, webAuthnCredentialJSON(credential, s.findUser(credential.userID)))
+		return nil
+	case http.MethodDelete:
+		for i, c := range s.webAuthn {
+			if c == credential {
+				s.webAuthn = append(s.webAuthn[:i:i], s.webAuthn[i+1:]...)
+				break
+			}
+		}
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
@@ -0,0 +1,93 @@
+package duotest
+
+import (
+	"testing"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerTokenLifecycle(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	user := srv.AddUser(admin.User{Username: "jsmith"})
+	client := srv.Client()
+
+	created, err := client.CreateToken(admin.TokenCreate{Type: admin.TokenTypeTOTP6, Serial: "42", Secret: "abcd", TOTPStep: admin.Int(60)})
+	if err != nil || created.Stat != "OK" {
+		t.Fatalf("Unexpected CreateToken result %+v, %v", created, err)
+	}
+	tokenID := created.Response.TokenID
+	if *created.Response.TOTPStep != 60 {
+		t.Errorf("Expected a TOTP step of 60, got %d", *created.Response.TOTPStep)
+	}
+
+	if result, err := client.ResyncToken(tokenID, "123456", "234567", "345678"); err != nil || result.Stat != "OK" {
+		t.Errorf("Unexpected ResyncToken result %+v, %v", result, err)
+	}
+	short, err := client.ResyncToken(tokenID, "1234", "2345", "3456")
+	if err != nil {
+		t.Fatalf("Unexpected error from ResyncToken call %v", err)
+	}
+	if short.Stat != "FAIL" || *short.Code != CodeInvalidParams {
+		t.Errorf("Expected codes of the wrong length to be rejected, got %+v", short.StatResult)
+	}
+
+	if result, err := client.AssociateUserToken(user.UserID, tokenID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected AssociateUserToken result %+v, %v", result, err)
+	}
+	if result, err := client.DisassociateUserToken(user.UserID, tokenID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DisassociateUserToken result %+v, %v", result, err)
+	}
+	if len(srv.Users()[0].Tokens) != 0 {
+		t.Errorf("Expected no tokens, got %+v", srv.Users()[0].Tokens)
+	}
+
+	if result, err := client.DeleteToken(tokenID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteToken result %+v, %v", result, err)
+	}
+	if len(srv.Tokens()) != 0 {
+		t.Errorf("Expected the token to be deleted")
+	}
+}
+
+func TestAdminServerSecurityKeys(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	user := srv.AddUser(admin.User{Username: "jsmith"})
+	other := srv.AddUser(admin.User{Username: "jdoe"})
+	u2f, _ := srv.AddU2FToken(user.UserID)
+	key, _ := srv.AddWebAuthnCredential(user.UserID, "YubiKey")
+	srv.AddWebAuthnCredential(other.UserID, "Touch ID")
+	client := srv.Client()
+
+	all, err := client.GetWebAuthnCredentials()
+	if err != nil || len(all.Response) != 2 {
+		t.Fatalf("Unexpected GetWebAuthnCredentials result %+v, %v", all, err)
+	}
+	mine, err := client.GetUserWebAuthnCredentials(user.UserID)
+	if err != nil || len(mine.Response) != 1 || mine.Response[0].WebAuthnKey != key.WebAuthnKey {
+		t.Fatalf("Unexpected GetUserWebAuthnCredentials result %+v, %v", mine, err)
+	}
+	fetched, err := client.GetWebAuthnCredential(key.WebAuthnKey)
+	if err != nil || fetched.Response.CredentialName != "YubiKey" || fetched.Response.User.UserID != user.UserID {
+		t.Errorf("Unexpected GetWebAuthnCredential result %+v, %v", fetched, err)
+	}
+	if result, err := client.DeleteWebAuthnCredential(key.WebAuthnKey); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteWebAuthnCredential result %+v, %v", result, err)
+	}
+	if gone, err := client.GetWebAuthnCredential(key.WebAuthnKey); err != nil || gone.Stat != "FAIL" || *gone.Code != CodeNotFound {
+		t.Errorf("Expected the deleted credential to be gone, got %+v, %v", gone, err)
+	}
+
+	token, err := client.GetU2FToken(u2f.RegistrationID)
+	if err != nil || token.Response.RegistrationID != u2f.RegistrationID || token.Response.User.UserID != user.UserID {
+		t.Errorf("Unexpected GetU2FToken result %+v, %v", token, err)
+	}
+	if result, err := client.DeleteU2FToken(u2f.RegistrationID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteU2FToken result %+v, %v", result, err)
+	}
+	tokens, err := client.GetU2FTokens()
+	if err != nil || len(tokens.Response) != 0 {
+		t.Errorf("Unexpected GetU2FTokens result %+v, %v", tokens, err)
+	}
+}
@@ -0,0 +1,686 @@
+package duotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// Enrollment link lifetime, in seconds.
+const defaultEnrollmentSecs = 2592000
+
+// Enrollment is an email enrollment requested from AdminServer.
+type Enrollment struct {
+	Username   string
+	Email      string
+	Code       string
+	Expiration int64
+}
+
+type verificationPush struct {
+	pushID  string
+	userID  string
+	phoneID string
+	result  admin.VerificationPushStatus
+}
+
+// AddUser stores a copy of user, assigning it a user ID if it has none, and
+// returns the stored user.  Its Groups, Phones and Tokens are ignored; use
+// the association methods instead.
+func (s *AdminServer) AddUser(user admin.User) admin.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user.UserID == "" {
+		user.UserID = s.ids.id("DU")
+	}
+	if user.Status == "" {
+		user.Status = string(admin.UserStatusActive)
+	}
+	if user.Created == 0 {
+		user.Created = uint64(time.Now().Unix())
+	}
+	user.Groups, user.Phones, user.Tokens = nil, nil, nil
+	s.users = append(s.users, &user)
+	return user
+}
+
+// AssociateGroup adds the user with userID to the group with groupID.
+func (s *AdminServer) AssociateGroup(userID, groupID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findUser(userID) == nil || s.findGroup(groupID) == nil {
+		return false
+	}
+	s.userGroups[userID] = addID(s.userGroups[userID], groupID)
+	return true
+}
+
+// AssociatePhone attaches the phone with phoneID to the user with userID.
+func (s *AdminServer) AssociatePhone(userID, phoneID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findUser(userID) == nil || s.findPhone(phoneID) == nil {
+		return false
+	}
+	s.userPhones[userID] = addID(s.userPhones[userID], phoneID)
+	return true
+}
+
+// AssociateToken attaches the hardware token with tokenID to the user with
+// userID.
+func (s *AdminServer) AssociateToken(userID, tokenID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findUser(userID) == nil || s.findToken(tokenID) == nil {
+		return false
+	}
+	s.userTokens[userID] = addID(s.userTokens[userID], tokenID)
+	return true
+}
+
+// Users returns copies of the stored users, in creation order, with their
+// groups, phones and tokens filled in.
+func (s *AdminServer) Users() []admin.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	users := make([]admin.User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, s.expandUser(u))
+	}
+	return users
+}
+
+// Enrollments returns the email enrollments requested, in request order.
+func (s *AdminServer) Enrollments() []Enrollment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Enrollment(nil), s.enrolls...)
+}
+
+// AnswerVerificationPush records the user's answer to the verification push
+// with pushID, which is reported as waiting until then.
+func (s *AdminServer) AnswerVerificationPush(pushID string, result admin.VerificationPushStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.pushes {
+		if p.pushID == pushID {
+			p.result = result
+			return true
+		}
+	}
+	return false
+}
+
+func (s *AdminServer) findUser(userID string) *admin.User {
+	for _, u := range s.users {
+		if u.UserID == userID {
+			return u
+		}
+	}
+	return nil
+}
+
+// expandUser returns a copy of u with its associations filled in.
+func (s *AdminServer) expandUser(u *admin.User) admin.User {
+	user := *u
+	user.Groups, user.Phones, user.Tokens = nil, nil, nil
+	for _, id := range s.userGroups[u.UserID] {
+		user.Groups = append(user.Groups, *s.findGroup(id))
+	}
+	for _, id := range s.userPhones[u.UserID] {
+		user.Phones = append(user.Phones, *s.findPhone(id))
+	}
+	for _, id := range s.userTokens[u.UserID] {
+		user.Tokens = append(user.Tokens, *s.findToken(id))
+	}
+	return user
+}
+
+// usersOf returns the users associated with an object through assoc.
+func (s *AdminServer) usersOf(assoc map[string][]string, id string) []*admin.User {
+	var users []*admin.User
+	for _, u := range s.users {
+		for _, associated := range assoc[u.UserID] {
+			if associated == id {
+				users = append(users, u)
+				break
+			}
+		}
+	}
+	return users
+}
+
+func (s *AdminServer) userJSON(u *admin.User) map[string]interface{} {
+	groups := []interface{}{}
+	for _, id := range s.userGroups[u.UserID] {
+		groups = append(groups, groupJSON(s.findGroup(id)))
+	}
+	phones := []interface{}{}
+	for _, id := range s.userPhones[u.UserID] {
+		phones = append(phones, phoneJSON(s.findPhone(id), nil))
+	}
+	tokens := []interface{}{}
+	for _, id := range s.userTokens[u.UserID] {
+		tokens = append(tokens, tokenJSON(s.findToken(id), nil))
+	}
+	u2f := []interface{}{}
+	for _, t := range s.u2fTokens {
+		if t.userID == u.UserID {
+			u2f = append(u2f, map[string]interface{}{
+				"date_added":      t.token.DateAdded,
+				"registration_id": t.token.RegistrationID,
+			})
+		}
+	}
+	webAuthn := []interface{}{}
+	for _, c := range s.webAuthn {
+		if c.userID == u.UserID {
+			webAuthn = append(webAuthn, webAuthnCredentialJSON(c, nil))
+		}
+	}
+	return map[string]interface{}{
+		"alias1":              nullableString(u.Alias1),
+		"alias2":              nullableString(u.Alias2),
+		"alias3":              nullableString(u.Alias3),
+		"alias4":              nullableString(u.Alias4),
+		"created":             u.Created,
+		"email":               u.Email,
+		"firstname":           nullableString(u.FirstName),
+		"groups":              groups,
+		"last_directory_sync": nullableUint(u.LastDirectorySync),
+		"last_login":          nullableUint(u.LastLogin),
+		"lastname":            nullableString(u.LastName),
+		"notes":               u.Notes,
+		"phones":              phones,
+		"realname":            nullableString(u.RealName),
+		"status":              u.Status,
+		"tokens":              tokens,
+		"u2ftokens":           u2f,
+		"user_id":             u.UserID,
+		"username":            u.Username,
+		"webauthncredentials": webAuthn,
+	}
+}
+
+// userSummaryJSON renders the abbreviated user embedded in other objects.
+func userSummaryJSON(u *admin.User) map[string]interface{} {
+	return map[string]interface{}{
+		"alias1":    nullableString(u.Alias1),
+		"alias2":    nullableString(u.Alias2),
+		"alias3":    nullableString(u.Alias3),
+		"alias4":    nullableString(u.Alias4),
+		"email":     u.Email,
+		"realname":  nullableString(u.RealName),
+		"status":    u.Status,
+		"user_id":   u.UserID,
+		"username":  u.Username,
+		"firstname": nullableString(u.FirstName),
+		"lastname":  nullableString(u.LastName),
+	}
+}
+
+func (s *AdminServer) handleUsers(w http.ResponseWriter, r *http.Request, params url.Values, path []string) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listUsers(w, params)
+		case http.MethodPost:
+			return s.createUser(w, params)
+		}
+		return methodNotAllowed()
+	}
+
+	if len(path) == 1 && path[0] == "bulk_create" {
+		if r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		return s.bulkCreateUsers(w, params)
+	}
+	if len(path) == 1 && path[0] == "enroll" {
+		if r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		return s.enrollUser(w, params)
+	}
+
+	user := s.findUser(path[0])
+	if user == nil {
+		return notFound()
+	}
+	if len(path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, s.userJSON(user))
+			return nil
+		case http.MethodPost:
+			return s.modifyUser(w, user, params)
+		case http.MethodDelete:
+			s.deleteUser(user.UserID)
+			writeJSON(w, "")
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	switch path[1] {
+	case "groups":
+		return s.handleUserAssociation(w, r, params, path[2:], user, "group_id", s.userGroups, func(id string) interface{} {
+			if g := s.findGroup(id); g != nil {
+				return groupJSON(g)
+			}
+			return nil
+		}, maxPageSize)
+	case "phones":
+		return s.handleUserAssociation(w, r, params, path[2:], user, "phone_id", s.userPhones, func(id string) interface{} {
+			if p := s.findPhone(id); p != nil {
+				return phoneJSON(p, nil)
+			}
+			return nil
+		}, maxPageSize)
+	case "tokens":
+		return s.handleUserAssociation(w, r, params, path[2:], user, "token_id", s.userTokens, func(id string) interface{} {
+			if t := s.findToken(id); t != nil {
+				return tokenJSON(t, nil)
+			}
+			return nil
+		}, maxPageSize)
+	case "u2ftokens":
+		if len(path) != 2 || r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		var tokens []interface{}
+		for _, t := range s.u2fTokens {
+			if t.userID == user.UserID {
+				tokens = append(tokens, s.u2fTokenJSON(t))
+			}
+		}
+		return writePage(w, params, tokens, maxPageSize)
+	case "webauthncredentials":
+		if len(path) != 2 || r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		var credentials []interface{}
+		for _, c := range s.webAuthn {
+			if c.userID == user.UserID {
+				credentials = append(credentials, webAuthnCredentialJSON(c, user))
+			}
+		}
+		return writePage(w, params, credentials, maxPageSize)
+	case "send_verification_push":
+		if len(path) != 2 || r.Method != http.MethodPost {
+			return methodNotAllowed()
+		}
+		return s.sendVerificationPush(w, user, params)
+	case "verification_push_response":
+		if len(path) != 2 || r.Method != http.MethodGet {
+			return methodNotAllowed()
+		}
+		return s.verificationPushResponse(w, user, params)
+	case "bypass_codes":
+		if len(path) != 2 {
+			return notFound()
+		}
+		switch r.Method {
+		case http.MethodGet:
+			var codes []interface{}
+			for _, b := range s.bypass {
+				if b.UserID == user.UserID {
+					codes = append(codes, s.bypassCodeJSON(b))
+				}
+			}
+			return writePage(w, params, codes, maxPageSize)
+		case http.MethodPost:
+			return s.createBypassCodes(w, user, params)
+		}
+		return methodNotAllowed()
+	}
+	return notFound()
+}
+
+// handleUserAssociation lists, adds and removes objects associated with a
+// user, such as groups, phones and tokens.
+func (s *AdminServer) handleUserAssociation(
+	w http.ResponseWriter,
+	r *http.Request,
+	params url.Values,
+	path []string,
+	user *admin.User,
+	idParam string,
+	assoc map[string][]string,
+	render func(id string) interface{},
+	maxLimit int,
+) *apiError {
+	if len(path) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			var items []interface{}
+			for _, id := range assoc[user.UserID] {
+				items = append(items, render(id))
+			}
+			return writePage(w, params, items, maxLimit)
+		case http.MethodPost:
+			id := params.Get(idParam)
+			if id == "" {
+				return missingParam(idParam)
+			}
+			if render(id) == nil {
+				return invalidParam(idParam)
+			}
+			assoc[user.UserID] = addID(assoc[user.UserID], id)
+			writeJSON(w, "")
+			return nil
+		}
+		return methodNotAllowed()
+	}
+
+	if len(path) == 1 && r.Method == http.MethodDelete {
+		ids, ok := removeID(assoc[user.UserID], path[0])
+		if !ok {
+			return notFound()
+		}
+		assoc[user.UserID] = ids
+		writeJSON(w, "")
+		return nil
+	}
+	return methodNotAllowed()
+}
+
+func (s *AdminServer) listUsers(w http.ResponseWriter, params url.Values) *apiError {
+	username := params.Get("username")
+	email := params.Get("email")
+	usernames, err := listParam(params, "username_list")
+	if err != nil {
+		return err
+	}
+	userIDs, err := listParam(params, "user_id_list")
+	if err != nil {
+		return err
+	}
+
+	var users []interface{}
+	for _, u := range s.users {
+		if username != "" && !strings.EqualFold(u.Username, username) {
+			continue
+		}
+		if email != "" && !strings.EqualFold(u.Email, email) {
+			continue
+		}
+		if usernames != nil && !matchesAny(usernames, namesOf(u)...) {
+			continue
+		}
+		if userIDs != nil && !matchesAny(userIDs, u.UserID) {
+			continue
+		}
+		users = append(users, s.userJSON(u))
+	}
+	return writePage(w, params, users, maxUsersPageSize)
+}
+
+// namesOf returns a user's username and aliases.
+func namesOf(u *admin.User) []string {
+	names := []string{u.Username}
+	for _, alias := range []*string{u.Alias1, u.Alias2, u.Alias3, u.Alias4} {
+		if alias != nil {
+			names = append(names, *alias)
+		}
+	}
+	return names
+}
+
+func (s *AdminServer) findUsername(username string) *admin.User {
+	for _, u := range s.users {
+		if strings.EqualFold(u.Username, username) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *AdminServer) createUser(w http.ResponseWriter, params url.Values) *apiError {
+	username := params.Get("username")
+	if username == "" {
+		return missingParam("username")
+	}
+	if s.findUsername(username) != nil {
+		return duplicate("username")
+	}
+
+	user := &admin.User{
+		UserID:   s.ids.id("DU"),
+		Username: username,
+		Status:   string(admin.UserStatusActive),
+		Created:  uint64(time.Now().Unix()),
+	}
+	if err := applyUserParams(user, params); err != nil {
+		return err
+	}
+	s.users = append(s.users, user)
+	writeJSON(w, s.userJSON(user))
+	return nil
+}
+
+// bulkCreateUsers creates every user in the JSON users parameter, or none of
+// them if any would be rejected.
+func (s *AdminServer) bulkCreateUsers(w http.ResponseWriter, params url.Values) *apiError {
+	var specs []map[string]string
+	if err := json.Unmarshal([]byte(params.Get("users")), &specs); err != nil {
+		return invalidParam("users")
+	}
+	if len(specs) == 0 || len(specs) > maxBulkCreateUsers {
+		return invalidParam("users")
+	}
+
+	var users []*admin.User
+	for _, spec := range specs {
+		values := url.Values{}
+		for k, v := range spec {
+			values.Set(k, v)
+		}
+		username := values.Get("username")
+		if username == "" {
+			return missingParam("username")
+		}
+		if s.findUsername(username) != nil {
+			return duplicate("username")
+		}
+		for _, u := range users {
+			if strings.EqualFold(u.Username, username) {
+				return duplicate("username")
+			}
+		}
+
+		user := &admin.User{
+			UserID:   s.ids.id("DU"),
+			Username: username,
+			Status:   string(admin.UserStatusActive),
+			Created:  uint64(time.Now().Unix()),
+		}
+		if err := applyUserParams(user, values); err != nil {
+			return err
+		}
+		users = append(users, user)
+	}
+
+	s.users = append(s.users, users...)
+	response := make([]interface{}, len(users))
+	for i, u := range users {
+		response[i] = s.userJSON(u)
+	}
+	writeJSON(w, response)
+	return nil
+}
+
+func (s *AdminServer) enrollUser(w http.ResponseWriter, params url.Values) *apiError {
+	username, email := params.Get("username"), params.Get("email")
+	if username == "" {
+		return missingParam("username")
+	}
+	if email == "" {
+		return missingParam("email")
+	}
+	if s.findUsername(username) != nil {
+		return duplicate("username")
+	}
+	validSecs := int64(defaultEnrollmentSecs)
+	if v := params.Get("valid_secs"); v != "" {
+		secs, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || secs <= 0 {
+			return invalidParam("valid_secs")
+		}
+		validSecs = secs
+	}
+
+	enrollment := Enrollment{
+		Username:   username,
+		Email:      email,
+		Code:       s.bypassCode(),
+		Expiration: time.Now().Unix() + validSecs,
+	}
+	s.enrolls = append(s.enrolls, enrollment)
+	writeJSON(w, enrollment.Code)
+	return nil
+}
+
+func (s *AdminServer) modifyUser(w http.ResponseWriter, user *admin.User, params url.Values) *apiError {
+	if username, ok := params["username"]; ok {
+		if existing := s.findUsername(username[0]); existing != nil && existing != user {
+			return duplicate("username")
+		}
+	}
+
+	updated := *user
+	if err := applyUserParams(&updated, params); err != nil {
+		return err
+	}
+	*user = updated
+	writeJSON(w, s.userJSON(user))
+	return nil
+}
+
+// applyUserParams sets the user attributes present in params.
+func applyUserParams(user *admin.User, params url.Values) *apiError {
+	optional := map[string]**string{
+		"alias1":    &user.Alias1,
+		"alias2":    &user.Alias2,
+		"alias3":    &user.Alias3,
+		"alias4":    &user.Alias4,
+		"firstname": &user.FirstName,
+		"lastname":  &user.LastName,
+		"realname":  &user.RealName,
+	}
+	for name, field := range optional {
+		if values, ok := params[name]; ok {
+			if values[0] == "" {
+				*field = nil
+			} else {
+				value := values[0]
+				*field = &value
+			}
+		}
+	}
+	if values, ok := params["username"]; ok {
+		if values[0] == "" {
+			return invalidParam("username")
+		}
+		user.Username = values[0]
+	}
+	if values, ok := params["email"]; ok {
+		user.Email = values[0]
+	}
+	if values, ok := params["notes"]; ok {
+		user.Notes = values[0]
+	}
+	if values, ok := params["status"]; ok {
+		switch status := admin.UserStatus(values[0]); status {
+		case admin.UserStatusActive, admin.UserStatusBypass, admin.UserStatusDisabled:
+			user.Status = string(status)
+		default:
+			return invalidParam("status")
+		}
+	}
+	return nil
+}
+
+func (s *AdminServer) deleteUser(userID string) {
+	for i, u := range s.users {
+		if u.UserID == userID {
+			s.users = append(s.users[:i:i], s.users[i+1:]...)
+			break
+		}
+	}
+	delete(s.userGroups, userID)
+	delete(s.userPhones, userID)
+	delete(s.userTokens, userID)
+
+	var u2f []*u2fToken
+	for _, t := range s.u2fTokens {
+		if t.userID != userID {
+			u2f = append(u2f, t)
+		}
+	}
+	s.u2fTokens = u2f
+
+	var webAuthn []*webAuthnCredential
+	for _, c := range s.webAuthn {
+		if c.userID != userID {
+			webAuthn = append(webAuthn, c)
+		}
+	}
+	s.webAuthn = webAuthn
+
+	var codes []*BypassCode
+	for _, b := range s.bypass {
+		if b.UserID != userID {
+			codes = append(codes, b)
+		}
+	}
+	s.bypass = codes
+}
+
+// Verification pushes
+
+func (s *AdminServer) sendVerificationPush(w http.ResponseWriter, user *admin.User, params url.Values) *apiError {
+	phoneID := params.Get("phone_id")
+	if phoneID == "" {
+		return missingParam("phone_id")
+	}
+	phone := s.findPhone(phoneID)
+	if phone == nil || !matchesAny(s.userPhones[user.UserID], phoneID) || !matchesAny(phone.Capabilities, "push") {
+		return invalidParam("phone_id")
+	}
+
+	push := &verificationPush{
+		pushID:  s.ids.id("VP"),
+		userID:  user.UserID,
+		phoneID: phoneID,
+		result:  admin.VerificationPushWaiting,
+	}
+	s.pushes = append(s.pushes, push)
+	writeJSON(w, map[string]interface{}{
+		"confirmation_code": s.bypassCode()[:6],
+		"push_id":           push.pushID,
+	})
+	return nil
+}
+
+func (s *AdminServer) verificationPushResponse(w http.ResponseWriter, user *admin.User, params url.Values) *apiError {
+	pushID := params.Get("push_id")
+	if pushID == "" {
+		return missingParam("push_id")
+	}
+	for _, p := range s.pushes {
+		if p.pushID == pushID && p.userID == user.UserID {
+			writeJSON(w, map[string]interface{}{
+				"push_id": p.pushID,
+				"result":  p.result,
+			})
+			return nil
+		}
+	}
+	return notFound()
+}
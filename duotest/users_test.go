@@ -0,0 +1,308 @@
+package duotest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+func TestAdminServerUserCRUD(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	client := srv.Client()
+
+	created, err := client.CreateUser(admin.UserUpdate{Username: admin.String("jsmith"), Email: admin.String("jsmith@example.com")})
+	if err != nil {
+		t.Fatalf("Unexpected error from CreateUser call %v", err)
+	}
+	if created.Stat != "OK" || created.Response.UserID == "" {
+		t.Fatalf("Unexpected CreateUser result %+v", created)
+	}
+	userID := created.Response.UserID
+
+	duplicate, err := client.CreateUser(admin.UserUpdate{Username: admin.String("JSmith")})
+	if err != nil {
+		t.Fatalf("Unexpected error from CreateUser call %v", err)
+	}
+	if duplicate.Stat != "FAIL" || *duplicate.Code != CodeDuplicate {
+		t.Errorf("Expected a duplicate resource failure, got %+v", duplicate.StatResult)
+	}
+
+	modified, err := client.ModifyUser(userID, admin.UserUpdate{Status: admin.NewUserStatus(admin.UserStatusDisabled), RealName: admin.String("Joe Smith")})
+	if err != nil {
+		t.Fatalf("Unexpected error from ModifyUser call %v", err)
+	}
+	if modified.Response.Status != "disabled" || *modified.Response.RealName != "Joe Smith" {
+		t.Errorf("Unexpected ModifyUser result %+v", modified.Response)
+	}
+
+	// Bypass the client's own validation to check the server's.
+	_, body, err := client.SignedCall("POST", "/admin/v1/users/"+userID, url.Values{"status": {"disable"}})
+	if err != nil {
+		t.Fatalf("Unexpected error from modify user call %v", err)
+	}
+	invalid := &admin.GetUserResult{}
+	if err = json.Unmarshal(body, invalid); err != nil {
+		t.Fatal(err)
+	}
+	if invalid.Stat != "FAIL" || *invalid.Code != CodeInvalidParams || *invalid.Message_Detail != "status" {
+		t.Errorf("Expected an invalid parameter failure, got %+v", invalid.StatResult)
+	}
+
+	fetched, err := client.GetUser(userID)
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUser call %v", err)
+	}
+	if fetched.Response.Email != "jsmith@example.com" || fetched.Response.Status != "disabled" {
+		t.Errorf("Unexpected GetUser result %+v", fetched.Response)
+	}
+
+	deleted, err := client.DeleteUser(userID)
+	if err != nil || deleted.Stat != "OK" {
+		t.Fatalf("Unexpected DeleteUser result %v, %v", deleted, err)
+	}
+	missing, err := client.GetUser(userID)
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUser call %v", err)
+	}
+	if missing.Stat != "FAIL" || *missing.Code != CodeNotFound {
+		t.Errorf("Expected a not found failure, got %+v", missing.StatResult)
+	}
+}
+
+func TestAdminServerPagination(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	for i := 0; i < 250; i++ {
+		srv.AddUser(admin.User{Username: fmt.Sprintf("user%03d", i)})
+	}
+	client := srv.Client()
+
+	page, err := client.GetUsers(admin.Limit(100), admin.Offset(200))
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUsers call %v", err)
+	}
+	if len(page.Response) != 50 || page.Response[0].Username != "user200" {
+		t.Errorf("Unexpected page of %d users", len(page.Response))
+	}
+	if page.Metadata.TotalObjects != "250" || page.Metadata.NextOffset != "" || page.Metadata.PrevOffset != "100" {
+		t.Errorf("Unexpected metadata %+v", page.Metadata)
+	}
+
+	all, err := client.GetUsers()
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUsers call %v", err)
+	}
+	if len(all.Response) != 250 {
+		t.Errorf("Expected 250 users, got %d", len(all.Response))
+	}
+
+	filtered, err := client.GetUsers(admin.GetUsersUsername("user042"))
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUsers call %v", err)
+	}
+	if len(filtered.Response) != 1 || filtered.Response[0].Username != "user042" {
+		t.Errorf("Unexpected filtered users %+v", filtered.Response)
+	}
+
+	var usernames []string
+	for i := 0; i < 150; i++ {
+		usernames = append(usernames, fmt.Sprintf("user%03d", i))
+	}
+	lookup, err := client.GetUsersByUsernames(append(usernames, "nobody"))
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUsersByUsernames call %v", err)
+	}
+	if len(lookup.Users) != 150 || len(lookup.NotFound) != 1 || lookup.NotFound[0] != "nobody" {
+		t.Errorf("Unexpected lookup of %d users, not found %v", len(lookup.Users), lookup.NotFound)
+	}
+
+	tooMany, err := client.GetUsers(admin.GetUsersUsernameList(usernames...))
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUsers call %v", err)
+	}
+	if tooMany.Stat != "FAIL" || *tooMany.Code != CodeInvalidParams {
+		t.Errorf("Expected an oversized username_list to be rejected, got %+v", tooMany.StatResult)
+	}
+}
+
+func TestAdminServerAssociations(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	user := srv.AddUser(admin.User{Username: "jsmith"})
+	group := srv.AddGroup(admin.Group{Name: "token_users"})
+	phone := srv.AddPhone(admin.Phone{Number: "+15555550100", Type: "Mobile"})
+	srv.AssociatePhone(user.UserID, phone.PhoneID)
+	client := srv.Client()
+
+	if result, err := client.AssociateGroupWithUser(user.UserID, group.GroupID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected AssociateGroupWithUser result %v, %v", result, err)
+	}
+	groups, err := client.GetUserGroups(user.UserID)
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUserGroups call %v", err)
+	}
+	if len(groups.Response) != 1 || groups.Response[0].GroupID != group.GroupID {
+		t.Errorf("Unexpected user groups %+v", groups.Response)
+	}
+
+	phones, err := client.GetPhones(admin.GetPhonesNumber("+15555550100"))
+	if err != nil {
+		t.Fatalf("Unexpected error from GetPhones call %v", err)
+	}
+	if len(phones.Response) != 1 || len(phones.Response[0].Users) != 1 || phones.Response[0].Users[0].UserID != user.UserID {
+		t.Errorf("Unexpected phones %+v", phones.Response)
+	}
+
+	if result, err := client.DisassociateGroupFromUser(user.UserID, group.GroupID); err != nil || result.Stat != "OK" {
+		t.Fatalf("Unexpected DisassociateGroupFromUser result %v, %v", result, err)
+	}
+	if users := srv.Users(); len(users[0].Groups) != 0 || len(users[0].Phones) != 1 {
+		t.Errorf("Unexpected stored user %+v", users[0])
+	}
+
+	codes, err := client.GetUserBypassCodes(user.UserID, func(v *url.Values) { v.Set("count", "3") })
+	if err != nil {
+		t.Fatalf("Unexpected error from GetUserBypassCodes call %v", err)
+	}
+	if len(codes.Response) != 3 || len(srv.BypassCodes()) != 3 {
+		t.Errorf("Expected 3 bypass codes, got %v", codes.Response)
+	}
+}
+
+func TestAdminServerEnrollAndVerify(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	user := srv.AddUser(admin.User{Username: "jsmith"})
+	phone := srv.AddPhone(admin.Phone{Type: "Mobile", Capabilities: []string{"push", "sms"}})
+	landline := srv.AddPhone(admin.Phone{Type: "Landline"})
+	srv.AssociatePhone(user.UserID, phone.PhoneID)
+	srv.AssociatePhone(user.UserID, landline.PhoneID)
+	client := srv.Client()
+
+	enrolled, err := client.EnrollUser("rjones", "rjones@example.com", 3600)
+	if err != nil || enrolled.Stat != "OK" {
+		t.Fatalf("Unexpected EnrollUser result %+v, %v", enrolled, err)
+	}
+	if enrollments := srv.Enrollments(); len(enrollments) != 1 || enrollments[0].Code != enrolled.Response {
+		t.Errorf("Unexpected enrollments %+v", enrollments)
+	}
+	if dup, err := client.EnrollUser("jsmith", "jsmith@example.com", 0); err != nil || *dup.Code != CodeDuplicate {
+		t.Errorf("Expected a duplicate error, got %+v, %v", dup, err)
+	}
+
+	if bad, err := client.SendVerificationPush(user.UserID, landline.PhoneID); err != nil || *bad.Code != CodeInvalidParams {
+		t.Errorf("Expected a push to a landline to be rejected, got %+v, %v", bad, err)
+	}
+	push, err := client.SendVerificationPush(user.UserID, phone.PhoneID)
+	if err != nil || len(push.Response.ConfirmationCode) != 6 {
+		t.Fatalf("Unexpected SendVerificationPush result %+v, %v", push, err)
+	}
+	pending, err := client.GetVerificationPushResponse(user.UserID, push.Response.PushID)
+	if err != nil || pending.Response.Result != admin.VerificationPushWaiting {
+		t.Errorf("Unexpected GetVerificationPushResponse result %+v, %v", pending, err)
+	}
+
+	srv.AnswerVerificationPush(push.Response.PushID, admin.VerificationPushApprove)
+	answered, err := client.WaitForVerificationPush(user.UserID, push.Response.PushID, time.Second)
+	if err != nil || answered.Response.Result != admin.VerificationPushApprove {
+		t.Errorf("Unexpected WaitForVerificationPush result %+v, %v", answered, err)
+	}
+}
+
+func TestAdminServerBulkCreateUsers(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	srv.AddUser(admin.User{Username: "taken"})
+	client := srv.Client()
+
+	created, err := client.BulkCreateUsers([]admin.User{{Username: "alice"}, {Username: "bob", Email: "bob@example.com"}})
+	if err != nil {
+		t.Fatalf("Unexpected error from BulkCreateUsers call %v", err)
+	}
+	if len(created.Created()) != 2 || created.Results[1].User.Email != "bob@example.com" {
+		t.Errorf("Unexpected BulkCreateUsers result %+v", created.Results)
+	}
+
+	// The server rejects the whole batch, so each user is created alone.
+	mixed, err := client.BulkCreateUsers([]admin.User{{Username: "carol"}, {Username: "Taken"}, {Username: "dave"}})
+	if err != nil {
+		t.Fatalf("Unexpected error from BulkCreateUsers call %v", err)
+	}
+	failed := mixed.Failed()
+	if len(mixed.Created()) != 2 || len(failed) != 1 || failed[0].Username != "Taken" {
+		t.Errorf("Unexpected BulkCreateUsers result %+v", mixed.Results)
+	}
+	if serr, ok := failed[0].Err.(*admin.StatError); !ok || *serr.Code != CodeDuplicate {
+		t.Errorf("Expected a duplicate failure, got %v", failed[0].Err)
+	}
+	if len(srv.Users()) != 5 {
+		t.Errorf("Expected 5 users, got %d", len(srv.Users()))
+	}
+}
+
+func TestAdminServerEnsure(t *testing.T) {
+	srv := NewAdminServer("eyekey", "esskey")
+	defer srv.Close()
+	staff := srv.AddGroup(admin.Group{Name: "Staff"})
+	client := srv.Client()
+
+	contractors, err := client.EnsureGroup(admin.GroupUpdate{Name: admin.String("Contractors"), Desc: admin.String("External")})
+	if err != nil {
+		t.Fatalf("Unexpected error from EnsureGroup call %v", err)
+	}
+	if !contractors.Created || contractors.Group.Desc != "External" {
+		t.Errorf("Unexpected EnsureGroup result %+v", contractors)
+	}
+
+	desired := admin.DesiredUser{
+		UserUpdate: admin.UserUpdate{Username: admin.String("jsmith"), Email: admin.String("jsmith@example.com")},
+		Groups:     []string{"Contractors", staff.GroupID},
+	}
+	created, err := client.EnsureUser(desired)
+	if err != nil {
+		t.Fatalf("Unexpected error from EnsureUser call %v", err)
+	}
+	if !created.Created || len(created.Changes) != 2 {
+		t.Errorf("Unexpected EnsureUser result %+v", created)
+	}
+
+	again, err := client.EnsureUser(desired)
+	if err != nil {
+		t.Fatalf("Unexpected error from EnsureUser call %v", err)
+	}
+	if again.Changed() || again.User.UserID != created.User.UserID {
+		t.Errorf("Expected no changes, got %+v", again)
+	}
+
+	desired.Status = admin.NewUserStatus(admin.UserStatusDisabled)
+	desired.Groups = []string{"Staff"}
+	changed, err := client.EnsureUser(desired)
+	if err != nil {
+		t.Fatalf("Unexpected error from EnsureUser call %v", err)
+	}
+	if changed.Created || !reflect.DeepEqual(changed.Changes, []string{"status", "-group Contractors"}) {
+		t.Errorf("Unexpected changes %v", changed.Changes)
+	}
+	if changed.User.Status != string(admin.UserStatusDisabled) || len(srv.Users()[0].Groups) != 1 {
+		t.Errorf("Unexpected user %+v", srv.Users()[0])
+	}
+
+	desired.Groups = []string{"Nobody"}
+	if _, err = client.EnsureUser(desired); err == nil {
+		t.Error("Expected an unknown group to be rejected")
+	}
+
+	group, err := client.EnsureGroup(admin.GroupUpdate{Name: admin.String("Contractors"), NullFields: []string{"Desc"}})
+	if err != nil {
+		t.Fatalf("Unexpected error from EnsureGroup call %v", err)
+	}
+	if group.Created || !reflect.DeepEqual(group.Changes, []string{"desc"}) || group.Group.Desc != "" {
+		t.Errorf("Unexpected EnsureGroup result %+v", group)
+	}
+}
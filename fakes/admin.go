@@ -0,0 +1,963 @@
+package fakes
+
+import (
+	"net/url"
+	"time"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// Admin is a fake admin.API.  Script the result of each method by name,
+// e.g. fake.Enqueue("GetUsers", &admin.GetUsersResult{...}, nil).
+type Admin struct {
+	Script
+}
+
+var _ admin.API = (*Admin)(nil)
+
+// GetUsers returns the next scripted *admin.GetUsersResult.
+func (f *Admin) GetUsers(options ...func(*url.Values)) (*admin.GetUsersResult, error) {
+	res, err := f.invoke("GetUsers", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetUsersResult), err
+}
+
+// GetUser returns the next scripted *admin.GetUserResult.
+func (f *Admin) GetUser(userID string) (*admin.GetUserResult, error) {
+	res, err := f.invoke("GetUser", nil, userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetUserResult), err
+}
+
+// CreateUser returns the next scripted *admin.GetUserResult.
+func (f *Admin) CreateUser(user admin.UserUpdate) (*admin.GetUserResult, error) {
+	res, err := f.invoke("CreateUser", encodeValues(user))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetUserResult), err
+}
+
+// BulkCreateUsers returns the next scripted *admin.BulkCreateUsersResult.
+func (f *Admin) BulkCreateUsers(users []admin.User) (*admin.BulkCreateUsersResult, error) {
+	res, err := f.invoke("BulkCreateUsers", nil, users)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.BulkCreateUsersResult), err
+}
+
+// ModifyUser returns the next scripted *admin.GetUserResult.
+func (f *Admin) ModifyUser(userID string, user admin.UserUpdate) (*admin.GetUserResult, error) {
+	res, err := f.invoke("ModifyUser", encodeValues(user), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetUserResult), err
+}
+
+// DeleteUser returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteUser(userID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteUser", nil, userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetUserGroups returns the next scripted *admin.GetGroupsResult.
+func (f *Admin) GetUserGroups(userID string, options ...func(*url.Values)) (*admin.GetGroupsResult, error) {
+	res, err := f.invoke("GetUserGroups", applyOptions(options), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetGroupsResult), err
+}
+
+// AssociateGroupWithUser returns the next scripted *duoapi.StatResult.
+func (f *Admin) AssociateGroupWithUser(userID string, groupID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("AssociateGroupWithUser", nil, userID, groupID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// DisassociateGroupFromUser returns the next scripted *duoapi.StatResult.
+func (f *Admin) DisassociateGroupFromUser(userID string, groupID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DisassociateGroupFromUser", nil, userID, groupID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetUserPhones returns the next scripted *admin.GetPhonesResult.
+func (f *Admin) GetUserPhones(userID string, options ...func(*url.Values)) (*admin.GetPhonesResult, error) {
+	res, err := f.invoke("GetUserPhones", applyOptions(options), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPhonesResult), err
+}
+
+// AssociatePhoneWithUser returns the next scripted *duoapi.StatResult.
+func (f *Admin) AssociatePhoneWithUser(userID, phoneID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("AssociatePhoneWithUser", nil, userID, phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// DisassociatePhoneFromUser returns the next scripted *duoapi.StatResult.
+func (f *Admin) DisassociatePhoneFromUser(userID, phoneID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DisassociatePhoneFromUser", nil, userID, phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetUserTokens returns the next scripted *admin.GetTokensResult.
+func (f *Admin) GetUserTokens(userID string, options ...func(*url.Values)) (*admin.GetTokensResult, error) {
+	res, err := f.invoke("GetUserTokens", applyOptions(options), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetTokensResult), err
+}
+
+// AssociateUserToken returns the next scripted *admin.StringResult.
+func (f *Admin) AssociateUserToken(userID string, tokenID string) (*admin.StringResult, error) {
+	res, err := f.invoke("AssociateUserToken", nil, userID, tokenID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.StringResult), err
+}
+
+// DisassociateUserToken returns the next scripted *admin.StringResult.
+func (f *Admin) DisassociateUserToken(userID, tokenID string) (*admin.StringResult, error) {
+	res, err := f.invoke("DisassociateUserToken", nil, userID, tokenID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.StringResult), err
+}
+
+// GetUserU2FTokens returns the next scripted *admin.GetU2FTokensResult.
+func (f *Admin) GetUserU2FTokens(userID string, options ...func(*url.Values)) (*admin.GetU2FTokensResult, error) {
+	res, err := f.invoke("GetUserU2FTokens", applyOptions(options), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetU2FTokensResult), err
+}
+
+// GetUserWebAuthnCredentials returns the next scripted *admin.GetWebAuthnCredentialsResult.
+func (f *Admin) GetUserWebAuthnCredentials(userID string, options ...func(*url.Values)) (*admin.GetWebAuthnCredentialsResult, error) {
+	res, err := f.invoke("GetUserWebAuthnCredentials", applyOptions(options), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetWebAuthnCredentialsResult), err
+}
+
+// GetUserBypassCodes returns the next scripted *admin.StringArrayResult.
+func (f *Admin) GetUserBypassCodes(userID string, options ...func(*url.Values)) (*admin.StringArrayResult, error) {
+	res, err := f.invoke("GetUserBypassCodes", applyOptions(options), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.StringArrayResult), err
+}
+
+// CreateUserBypassCodes returns the next scripted *admin.StringArrayResult.
+func (f *Admin) CreateUserBypassCodes(userID string, create admin.BypassCodesCreate) (*admin.StringArrayResult, error) {
+	res, err := f.invoke("CreateUserBypassCodes", encodeValues(create), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.StringArrayResult), err
+}
+
+// ListUserBypassCodes returns the next scripted *admin.GetBypassCodesResult.
+func (f *Admin) ListUserBypassCodes(userID string, options ...func(*url.Values)) (*admin.GetBypassCodesResult, error) {
+	res, err := f.invoke("ListUserBypassCodes", applyOptions(options), userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetBypassCodesResult), err
+}
+
+// EnrollUser returns the next scripted *admin.StringResult.
+func (f *Admin) EnrollUser(username, email string, validSecs uint64) (*admin.StringResult, error) {
+	res, err := f.invoke("EnrollUser", nil, username, email, validSecs)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.StringResult), err
+}
+
+// SendVerificationPush returns the next scripted *admin.VerificationPushResult.
+func (f *Admin) SendVerificationPush(userID, phoneID string) (*admin.VerificationPushResult, error) {
+	res, err := f.invoke("SendVerificationPush", nil, userID, phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.VerificationPushResult), err
+}
+
+// GetVerificationPushResponse returns the next scripted *admin.VerificationPushResponseResult.
+func (f *Admin) GetVerificationPushResponse(userID, pushID string) (*admin.VerificationPushResponseResult, error) {
+	res, err := f.invoke("GetVerificationPushResponse", nil, userID, pushID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.VerificationPushResponseResult), err
+}
+
+// WaitForVerificationPush returns the next scripted *admin.VerificationPushResponseResult.
+func (f *Admin) WaitForVerificationPush(userID, pushID string, timeout time.Duration) (*admin.VerificationPushResponseResult, error) {
+	res, err := f.invoke("WaitForVerificationPush", nil, userID, pushID, timeout)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.VerificationPushResponseResult), err
+}
+
+// GetUsersByUsernames returns the next scripted *admin.UserLookupResult.
+func (f *Admin) GetUsersByUsernames(usernames []string) (*admin.UserLookupResult, error) {
+	res, err := f.invoke("GetUsersByUsernames", nil, usernames)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.UserLookupResult), err
+}
+
+// GetUsersByIDs returns the next scripted *admin.UserLookupResult.
+func (f *Admin) GetUsersByIDs(userIDs []string) (*admin.UserLookupResult, error) {
+	res, err := f.invoke("GetUsersByIDs", nil, userIDs)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.UserLookupResult), err
+}
+
+// GetUsersByEmails returns the next scripted *admin.UserLookupResult.
+func (f *Admin) GetUsersByEmails(emails []string) (*admin.UserLookupResult, error) {
+	res, err := f.invoke("GetUsersByEmails", nil, emails)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.UserLookupResult), err
+}
+
+// EnsureUser returns the next scripted *admin.EnsureUserResult.
+func (f *Admin) EnsureUser(desired admin.DesiredUser) (*admin.EnsureUserResult, error) {
+	res, err := f.invoke("EnsureUser", encodeValues(desired.UserUpdate), desired)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.EnsureUserResult), err
+}
+
+// Users returns an iterator over scripted *admin.GetUsersResult pages, one
+// per page fetched, e.g. fake.Enqueue("Users", &admin.GetUsersResult{...}, nil).  Each
+// page's parameters are recorded as a call to Users.
+func (f *Admin) Users(options ...func(*url.Values)) *admin.UserIterator {
+	return admin.NewUserIterator(func(params url.Values) (*admin.GetUsersResult, error) {
+		res, err := f.invoke("Users", copyValues(params))
+		if res == nil {
+			return nil, err
+		}
+		return res.(*admin.GetUsersResult), err
+	}, options...)
+}
+
+// GetGroups returns the next scripted *admin.GetGroupsResult.
+func (f *Admin) GetGroups(options ...func(*url.Values)) (*admin.GetGroupsResult, error) {
+	res, err := f.invoke("GetGroups", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetGroupsResult), err
+}
+
+// GetGroup returns the next scripted *admin.GetGroupResult.
+func (f *Admin) GetGroup(groupID string) (*admin.GetGroupResult, error) {
+	res, err := f.invoke("GetGroup", nil, groupID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetGroupResult), err
+}
+
+// CreateGroup returns the next scripted *admin.GetGroupResult.
+func (f *Admin) CreateGroup(group admin.GroupUpdate) (*admin.GetGroupResult, error) {
+	res, err := f.invoke("CreateGroup", encodeValues(group))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetGroupResult), err
+}
+
+// ModifyGroup returns the next scripted *admin.GetGroupResult.
+func (f *Admin) ModifyGroup(groupID string, group admin.GroupUpdate) (*admin.GetGroupResult, error) {
+	res, err := f.invoke("ModifyGroup", encodeValues(group), groupID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetGroupResult), err
+}
+
+// DeleteGroup returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteGroup(groupID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteGroup", nil, groupID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetGroupUsers returns the next scripted *admin.GetUsersResult.
+func (f *Admin) GetGroupUsers(groupID string, options ...func(*url.Values)) (*admin.GetUsersResult, error) {
+	res, err := f.invoke("GetGroupUsers", applyOptions(options), groupID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetUsersResult), err
+}
+
+// EnsureGroup returns the next scripted *admin.EnsureGroupResult.
+func (f *Admin) EnsureGroup(desired admin.GroupUpdate) (*admin.EnsureGroupResult, error) {
+	res, err := f.invoke("EnsureGroup", encodeValues(desired), desired)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.EnsureGroupResult), err
+}
+
+// Groups returns an iterator over scripted *admin.GetGroupsResult pages, like Users.
+func (f *Admin) Groups(options ...func(*url.Values)) *admin.GroupIterator {
+	return admin.NewGroupIterator(func(params url.Values) (*admin.GetGroupsResult, error) {
+		res, err := f.invoke("Groups", copyValues(params))
+		if res == nil {
+			return nil, err
+		}
+		return res.(*admin.GetGroupsResult), err
+	}, options...)
+}
+
+// GetPhones returns the next scripted *admin.GetPhonesResult.
+func (f *Admin) GetPhones(options ...func(*url.Values)) (*admin.GetPhonesResult, error) {
+	res, err := f.invoke("GetPhones", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPhonesResult), err
+}
+
+// GetPhone returns the next scripted *admin.GetPhoneResult.
+func (f *Admin) GetPhone(phoneID string) (*admin.GetPhoneResult, error) {
+	res, err := f.invoke("GetPhone", nil, phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPhoneResult), err
+}
+
+// CreatePhone returns the next scripted *admin.GetPhoneResult.
+func (f *Admin) CreatePhone(phone admin.PhoneUpdate) (*admin.GetPhoneResult, error) {
+	res, err := f.invoke("CreatePhone", encodeValues(phone))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPhoneResult), err
+}
+
+// ModifyPhone returns the next scripted *admin.GetPhoneResult.
+func (f *Admin) ModifyPhone(phoneID string, phone admin.PhoneUpdate) (*admin.GetPhoneResult, error) {
+	res, err := f.invoke("ModifyPhone", encodeValues(phone), phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPhoneResult), err
+}
+
+// DeletePhone returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeletePhone(phoneID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeletePhone", nil, phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// CreateActivationURL returns the next scripted *admin.ActivationURLResult.
+func (f *Admin) CreateActivationURL(phoneID string, options ...func(*url.Values)) (*admin.ActivationURLResult, error) {
+	res, err := f.invoke("CreateActivationURL", applyOptions(options), phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.ActivationURLResult), err
+}
+
+// SendSMSActivation returns the next scripted *admin.SMSActivationResult.
+func (f *Admin) SendSMSActivation(phoneID string, options ...func(*url.Values)) (*admin.SMSActivationResult, error) {
+	res, err := f.invoke("SendSMSActivation", applyOptions(options), phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.SMSActivationResult), err
+}
+
+// SendSMSPasscodes returns the next scripted *duoapi.StatResult.
+func (f *Admin) SendSMSPasscodes(phoneID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("SendSMSPasscodes", nil, phoneID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// Phones returns an iterator over scripted *admin.GetPhonesResult pages, like Users.
+func (f *Admin) Phones(options ...func(*url.Values)) *admin.PhoneIterator {
+	return admin.NewPhoneIterator(func(params url.Values) (*admin.GetPhonesResult, error) {
+		res, err := f.invoke("Phones", copyValues(params))
+		if res == nil {
+			return nil, err
+		}
+		return res.(*admin.GetPhonesResult), err
+	}, options...)
+}
+
+// GetTokens returns the next scripted *admin.GetTokensResult.
+func (f *Admin) GetTokens(options ...func(*url.Values)) (*admin.GetTokensResult, error) {
+	res, err := f.invoke("GetTokens", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetTokensResult), err
+}
+
+// GetToken returns the next scripted *admin.GetTokenResult.
+func (f *Admin) GetToken(tokenID string) (*admin.GetTokenResult, error) {
+	res, err := f.invoke("GetToken", nil, tokenID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetTokenResult), err
+}
+
+// CreateToken returns the next scripted *admin.GetTokenResult.
+func (f *Admin) CreateToken(token admin.TokenCreate) (*admin.GetTokenResult, error) {
+	res, err := f.invoke("CreateToken", encodeValues(token))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetTokenResult), err
+}
+
+// ResyncToken returns the next scripted *admin.StringResult.
+func (f *Admin) ResyncToken(tokenID, code1, code2, code3 string) (*admin.StringResult, error) {
+	res, err := f.invoke("ResyncToken", url.Values{"code1": {code1}, "code2": {code2}, "code3": {code3}}, tokenID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.StringResult), err
+}
+
+// DeleteToken returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteToken(tokenID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteToken", nil, tokenID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// Tokens returns an iterator over scripted *admin.GetTokensResult pages, like Users.
+func (f *Admin) Tokens(options ...func(*url.Values)) *admin.TokenIterator {
+	return admin.NewTokenIterator(func(params url.Values) (*admin.GetTokensResult, error) {
+		res, err := f.invoke("Tokens", copyValues(params))
+		if res == nil {
+			return nil, err
+		}
+		return res.(*admin.GetTokensResult), err
+	}, options...)
+}
+
+// ListBypassCodes returns the next scripted *admin.GetBypassCodesResult.
+func (f *Admin) ListBypassCodes(options ...func(*url.Values)) (*admin.GetBypassCodesResult, error) {
+	res, err := f.invoke("ListBypassCodes", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetBypassCodesResult), err
+}
+
+// GetBypassCode returns the next scripted *admin.GetBypassCodeResult.
+func (f *Admin) GetBypassCode(bypassCodeID string) (*admin.GetBypassCodeResult, error) {
+	res, err := f.invoke("GetBypassCode", nil, bypassCodeID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetBypassCodeResult), err
+}
+
+// DeleteBypassCode returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteBypassCode(bypassCodeID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteBypassCode", nil, bypassCodeID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetU2FTokens returns the next scripted *admin.GetU2FTokensResult.
+func (f *Admin) GetU2FTokens(options ...func(*url.Values)) (*admin.GetU2FTokensResult, error) {
+	res, err := f.invoke("GetU2FTokens", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetU2FTokensResult), err
+}
+
+// GetU2FToken returns the next scripted *admin.GetU2FTokenResult.
+func (f *Admin) GetU2FToken(registrationID string) (*admin.GetU2FTokenResult, error) {
+	res, err := f.invoke("GetU2FToken", nil, registrationID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetU2FTokenResult), err
+}
+
+// DeleteU2FToken returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteU2FToken(registrationID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteU2FToken", nil, registrationID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// U2FTokens returns an iterator over scripted *admin.GetU2FTokensResult pages, like Users.
+func (f *Admin) U2FTokens(options ...func(*url.Values)) *admin.U2FTokenIterator {
+	return admin.NewU2FTokenIterator(func(params url.Values) (*admin.GetU2FTokensResult, error) {
+		res, err := f.invoke("U2FTokens", copyValues(params))
+		if res == nil {
+			return nil, err
+		}
+		return res.(*admin.GetU2FTokensResult), err
+	}, options...)
+}
+
+// GetWebAuthnCredentials returns the next scripted *admin.GetWebAuthnCredentialsResult.
+func (f *Admin) GetWebAuthnCredentials(options ...func(*url.Values)) (*admin.GetWebAuthnCredentialsResult, error) {
+	res, err := f.invoke("GetWebAuthnCredentials", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetWebAuthnCredentialsResult), err
+}
+
+// GetWebAuthnCredential returns the next scripted *admin.GetWebAuthnCredentialResult.
+func (f *Admin) GetWebAuthnCredential(webAuthnKey string) (*admin.GetWebAuthnCredentialResult, error) {
+	res, err := f.invoke("GetWebAuthnCredential", nil, webAuthnKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetWebAuthnCredentialResult), err
+}
+
+// DeleteWebAuthnCredential returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteWebAuthnCredential(webAuthnKey string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteWebAuthnCredential", nil, webAuthnKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetAdmins returns the next scripted *admin.GetAdminsResult.
+func (f *Admin) GetAdmins(options ...func(*url.Values)) (*admin.GetAdminsResult, error) {
+	res, err := f.invoke("GetAdmins", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminsResult), err
+}
+
+// GetAdmin returns the next scripted *admin.GetAdminResult.
+func (f *Admin) GetAdmin(adminID string) (*admin.GetAdminResult, error) {
+	res, err := f.invoke("GetAdmin", nil, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminResult), err
+}
+
+// CreateAdmin returns the next scripted *admin.GetAdminResult.
+func (f *Admin) CreateAdmin(administrator admin.AdminUpdate) (*admin.GetAdminResult, error) {
+	res, err := f.invoke("CreateAdmin", encodeValues(administrator))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminResult), err
+}
+
+// ModifyAdmin returns the next scripted *admin.GetAdminResult.
+func (f *Admin) ModifyAdmin(adminID string, administrator admin.AdminUpdate) (*admin.GetAdminResult, error) {
+	res, err := f.invoke("ModifyAdmin", encodeValues(administrator), adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminResult), err
+}
+
+// DeleteAdmin returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteAdmin(adminID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteAdmin", nil, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// ResetAdminAuthAttempts returns the next scripted *duoapi.StatResult.
+func (f *Admin) ResetAdminAuthAttempts(adminID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("ResetAdminAuthAttempts", nil, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// ClearAdminExpiration returns the next scripted *duoapi.StatResult.
+func (f *Admin) ClearAdminExpiration(adminID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("ClearAdminExpiration", nil, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// CreateAdminActivationLink returns the next scripted *admin.AdminActivationLinkResult.
+func (f *Admin) CreateAdminActivationLink(adminID string) (*admin.AdminActivationLinkResult, error) {
+	res, err := f.invoke("CreateAdminActivationLink", nil, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.AdminActivationLinkResult), err
+}
+
+// SendAdminActivationEmail returns the next scripted *admin.AdminActivationLinkResult.
+func (f *Admin) SendAdminActivationEmail(adminID string) (*admin.AdminActivationLinkResult, error) {
+	res, err := f.invoke("SendAdminActivationEmail", nil, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.AdminActivationLinkResult), err
+}
+
+// DeleteAdminActivationLink returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteAdminActivationLink(adminID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteAdminActivationLink", nil, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetAdminActivations returns the next scripted *admin.GetAdminActivationsResult.
+func (f *Admin) GetAdminActivations(options ...func(*url.Values)) (*admin.GetAdminActivationsResult, error) {
+	res, err := f.invoke("GetAdminActivations", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminActivationsResult), err
+}
+
+// CreateAdminActivation returns the next scripted *admin.AdminActivationResult.
+func (f *Admin) CreateAdminActivation(activation admin.AdminActivationCreate) (*admin.AdminActivationResult, error) {
+	res, err := f.invoke("CreateAdminActivation", encodeValues(activation))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.AdminActivationResult), err
+}
+
+// DeleteAdminActivation returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteAdminActivation(activationID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteAdminActivation", nil, activationID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetAdminUnits returns the next scripted *admin.GetAdminUnitsResult.
+func (f *Admin) GetAdminUnits(options ...func(*url.Values)) (*admin.GetAdminUnitsResult, error) {
+	res, err := f.invoke("GetAdminUnits", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminUnitsResult), err
+}
+
+// GetAdminUnit returns the next scripted *admin.GetAdminUnitResult.
+func (f *Admin) GetAdminUnit(adminUnitID string) (*admin.GetAdminUnitResult, error) {
+	res, err := f.invoke("GetAdminUnit", nil, adminUnitID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminUnitResult), err
+}
+
+// CreateAdminUnit returns the next scripted *admin.GetAdminUnitResult.
+func (f *Admin) CreateAdminUnit(unit admin.AdminUnitUpdate) (*admin.GetAdminUnitResult, error) {
+	res, err := f.invoke("CreateAdminUnit", encodeValues(unit))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminUnitResult), err
+}
+
+// ModifyAdminUnit returns the next scripted *admin.GetAdminUnitResult.
+func (f *Admin) ModifyAdminUnit(adminUnitID string, unit admin.AdminUnitUpdate) (*admin.GetAdminUnitResult, error) {
+	res, err := f.invoke("ModifyAdminUnit", encodeValues(unit), adminUnitID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetAdminUnitResult), err
+}
+
+// DeleteAdminUnit returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteAdminUnit(adminUnitID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteAdminUnit", nil, adminUnitID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// AssignAdminToUnit returns the next scripted *duoapi.StatResult.
+func (f *Admin) AssignAdminToUnit(adminUnitID, adminID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("AssignAdminToUnit", nil, adminUnitID, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// UnassignAdminFromUnit returns the next scripted *duoapi.StatResult.
+func (f *Admin) UnassignAdminFromUnit(adminUnitID, adminID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("UnassignAdminFromUnit", nil, adminUnitID, adminID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// AssignGroupToUnit returns the next scripted *duoapi.StatResult.
+func (f *Admin) AssignGroupToUnit(adminUnitID, groupID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("AssignGroupToUnit", nil, adminUnitID, groupID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// UnassignGroupFromUnit returns the next scripted *duoapi.StatResult.
+func (f *Admin) UnassignGroupFromUnit(adminUnitID, groupID string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("UnassignGroupFromUnit", nil, adminUnitID, groupID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// AssignIntegrationToUnit returns the next scripted *duoapi.StatResult.
+func (f *Admin) AssignIntegrationToUnit(adminUnitID, integrationKey string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("AssignIntegrationToUnit", nil, adminUnitID, integrationKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// UnassignIntegrationFromUnit returns the next scripted *duoapi.StatResult.
+func (f *Admin) UnassignIntegrationFromUnit(adminUnitID, integrationKey string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("UnassignIntegrationFromUnit", nil, adminUnitID, integrationKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetIntegrations returns the next scripted *admin.GetIntegrationsResult.
+func (f *Admin) GetIntegrations(options ...func(*url.Values)) (*admin.GetIntegrationsResult, error) {
+	res, err := f.invoke("GetIntegrations", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetIntegrationsResult), err
+}
+
+// GetIntegration returns the next scripted *admin.GetIntegrationResult.
+func (f *Admin) GetIntegration(integrationKey string) (*admin.GetIntegrationResult, error) {
+	res, err := f.invoke("GetIntegration", nil, integrationKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetIntegrationResult), err
+}
+
+// CreateIntegration returns the next scripted *admin.GetIntegrationResult.
+func (f *Admin) CreateIntegration(integration admin.IntegrationUpdate) (*admin.GetIntegrationResult, error) {
+	res, err := f.invoke("CreateIntegration", encodeValues(integration))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetIntegrationResult), err
+}
+
+// ModifyIntegration returns the next scripted *admin.GetIntegrationResult.
+func (f *Admin) ModifyIntegration(integrationKey string, integration admin.IntegrationUpdate) (*admin.GetIntegrationResult, error) {
+	res, err := f.invoke("ModifyIntegration", encodeValues(integration), integrationKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetIntegrationResult), err
+}
+
+// DeleteIntegration returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeleteIntegration(integrationKey string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeleteIntegration", nil, integrationKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// GetIntegrationSecretKey returns the next scripted *admin.IntegrationSecretKeyResult.
+func (f *Admin) GetIntegrationSecretKey(integrationKey string) (*admin.IntegrationSecretKeyResult, error) {
+	res, err := f.invoke("GetIntegrationSecretKey", nil, integrationKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.IntegrationSecretKeyResult), err
+}
+
+// RotateIntegrationSecretKey returns the next scripted string.
+func (f *Admin) RotateIntegrationSecretKey(integrationKey string) (string, error) {
+	res, err := f.invoke("RotateIntegrationSecretKey", nil, integrationKey)
+	key, _ := res.(string)
+	return key, err
+}
+
+// GetPolicies returns the next scripted *admin.GetPoliciesResult.
+func (f *Admin) GetPolicies(options ...func(*url.Values)) (*admin.GetPoliciesResult, error) {
+	res, err := f.invoke("GetPolicies", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPoliciesResult), err
+}
+
+// GetPolicy returns the next scripted *admin.GetPolicyResult.
+func (f *Admin) GetPolicy(policyKey string) (*admin.GetPolicyResult, error) {
+	res, err := f.invoke("GetPolicy", nil, policyKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPolicyResult), err
+}
+
+// GetGlobalPolicy returns the next scripted *admin.GetPolicyResult.
+func (f *Admin) GetGlobalPolicy() (*admin.GetPolicyResult, error) {
+	res, err := f.invoke("GetGlobalPolicy", nil)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPolicyResult), err
+}
+
+// CreatePolicy returns the next scripted *admin.GetPolicyResult.
+func (f *Admin) CreatePolicy(policy admin.PolicyUpdate) (*admin.GetPolicyResult, error) {
+	res, err := f.invoke("CreatePolicy", nil, policy)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPolicyResult), err
+}
+
+// UpdatePolicy returns the next scripted *admin.GetPolicyResult.
+func (f *Admin) UpdatePolicy(policyKey string, policy admin.PolicyUpdate) (*admin.GetPolicyResult, error) {
+	res, err := f.invoke("UpdatePolicy", nil, policyKey, policy)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.GetPolicyResult), err
+}
+
+// CopyPolicy returns the next scripted *admin.CopyPolicyResult.
+func (f *Admin) CopyPolicy(policyKey string, newNames []string) (*admin.CopyPolicyResult, error) {
+	res, err := f.invoke("CopyPolicy", nil, policyKey, newNames)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.CopyPolicyResult), err
+}
+
+// DeletePolicy returns the next scripted *duoapi.StatResult.
+func (f *Admin) DeletePolicy(policyKey string) (*duoapi.StatResult, error) {
+	res, err := f.invoke("DeletePolicy", nil, policyKey)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*duoapi.StatResult), err
+}
+
+// CalculatePolicy returns the next scripted *admin.EffectivePolicyResult.
+func (f *Admin) CalculatePolicy(integrationKey, userID string) (*admin.EffectivePolicyResult, error) {
+	res, err := f.invoke("CalculatePolicy", nil, integrationKey, userID)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.EffectivePolicyResult), err
+}
+
+// GetAuthLogs returns the next scripted *admin.AuthLogResult.
+func (f *Admin) GetAuthLogs(mintime time.Time, window time.Duration, options ...func(*url.Values)) (*admin.AuthLogResult, error) {
+	res, err := f.invoke("GetAuthLogs", applyOptions(options), mintime, window)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.AuthLogResult), err
+}
+
+// GetAdminLogs returns the next scripted *admin.AdminLogResult.
+func (f *Admin) GetAdminLogs(mintime time.Time, options ...func(*url.Values)) (*admin.AdminLogResult, error) {
+	res, err := f.invoke("GetAdminLogs", applyOptions(options), mintime)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.AdminLogResult), err
+}
+
+// GetTelephonyLogs returns the next scripted *admin.TelephonyLogResult.
+func (f *Admin) GetTelephonyLogs(mintime time.Time, options ...func(*url.Values)) (*admin.TelephonyLogResult, error) {
+	res, err := f.invoke("GetTelephonyLogs", applyOptions(options), mintime)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*admin.TelephonyLogResult), err
+}
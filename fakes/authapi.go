@@ -0,0 +1,87 @@
+package fakes
+
+import (
+	"net/url"
+
+	"github.com/duosecurity/duo_api_golang/authapi"
+)
+
+// Authenticator is a fake authapi.Authenticator.  Script the result of each
+// method by name, e.g. fake.Enqueue("Auth", &authapi.AuthResult{...}, nil).
+type Authenticator struct {
+	Script
+}
+
+var _ authapi.Authenticator = (*Authenticator)(nil)
+
+// Ping returns the next scripted *authapi.PingResult.
+func (f *Authenticator) Ping() (*authapi.PingResult, error) {
+	res, err := f.invoke("Ping", nil)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*authapi.PingResult), err
+}
+
+// Check returns the next scripted *authapi.CheckResult.
+func (f *Authenticator) Check() (*authapi.CheckResult, error) {
+	res, err := f.invoke("Check", nil)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*authapi.CheckResult), err
+}
+
+// Logo returns the next scripted *authapi.LogoResult.
+func (f *Authenticator) Logo() (*authapi.LogoResult, error) {
+	res, err := f.invoke("Logo", nil)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*authapi.LogoResult), err
+}
+
+// Enroll returns the next scripted *authapi.EnrollResult.
+func (f *Authenticator) Enroll(options ...func(*url.Values)) (*authapi.EnrollResult, error) {
+	res, err := f.invoke("Enroll", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*authapi.EnrollResult), err
+}
+
+// EnrollStatus returns the next scripted *authapi.EnrollStatusResult.
+func (f *Authenticator) EnrollStatus(userid string, activationCode string) (*authapi.EnrollStatusResult, error) {
+	res, err := f.invoke("EnrollStatus", nil, userid, activationCode)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*authapi.EnrollStatusResult), err
+}
+
+// Preauth returns the next scripted *authapi.PreauthResult.
+func (f *Authenticator) Preauth(options ...func(*url.Values)) (*authapi.PreauthResult, error) {
+	res, err := f.invoke("Preauth", applyOptions(options))
+	if res == nil {
+		return nil, err
+	}
+	return res.(*authapi.PreauthResult), err
+}
+
+// Auth returns the next scripted *authapi.AuthResult.
+func (f *Authenticator) Auth(factor string, options ...func(*url.Values)) (*authapi.AuthResult, error) {
+	res, err := f.invoke("Auth", applyOptions(options), factor)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*authapi.AuthResult), err
+}
+
+// AuthStatus returns the next scripted *authapi.AuthStatusResult.
+func (f *Authenticator) AuthStatus(txid string) (*authapi.AuthStatusResult, error) {
+	res, err := f.invoke("AuthStatus", nil, txid)
+	if res == nil {
+		return nil, err
+	}
+	return res.(*authapi.AuthStatusResult), err
+}
@@ -0,0 +1,144 @@
+// Package fakes provides in-memory stand-ins for authapi.Authenticator and
+// admin.API.  The fakes return scripted responses and record every call made
+// to them, so code which depends on those interfaces can be unit tested
+// without an HTTP server.
+//
+// Example:
+//
+//	fake := &fakes.Admin{}
+//	fake.Enqueue("GetUser", &admin.GetUserResult{Response: admin.User{Username: "jsmith"}}, nil)
+//	result, err := codeUnderTest(fake)
+//	if len(fake.CallsTo("GetUser")) != 1 { ... }
+package fakes
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/duosecurity/duo_api_golang/admin"
+)
+
+// Call records a single method call made to a fake.
+type Call struct {
+	// Method is the name of the method called.
+	Method string
+	// Args holds the positional arguments, excluding any parameters.
+	Args []interface{}
+	// Params holds the url.Values passed to the method, produced by
+	// applying its functional options or encoded from its update struct.
+	// It is nil for other methods.
+	Params url.Values
+}
+
+type response struct {
+	result interface{}
+	err    error
+}
+
+// Script holds scripted responses and the calls made to a fake.  It is
+// embedded in each fake and is safe for concurrent use.
+type Script struct {
+	mu     sync.Mutex
+	queued map[string][]response
+	stubs  map[string]response
+	calls  []Call
+}
+
+// Enqueue adds a response to be returned by a single call to method.
+// Queued responses are returned in the order they were added, before any
+// response configured with Stub.
+func (s *Script) Enqueue(method string, result interface{}, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queued == nil {
+		s.queued = make(map[string][]response)
+	}
+	s.queued[method] = append(s.queued[method], response{result, err})
+}
+
+// Stub sets the response returned by every call to method once its queued
+// responses have been used up.
+func (s *Script) Stub(method string, result interface{}, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stubs == nil {
+		s.stubs = make(map[string]response)
+	}
+	s.stubs[method] = response{result, err}
+}
+
+// Calls returns every call made to the fake, in order.
+func (s *Script) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns the calls made to method, in order.
+func (s *Script) CallsTo(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []Call
+	for _, call := range s.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets all scripted responses and recorded calls.
+func (s *Script) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queued = nil
+	s.stubs = nil
+	s.calls = nil
+}
+
+// invoke records a call to method and returns its next scripted response.
+// Calls without a scripted response return an error.
+func (s *Script) invoke(method string, params url.Values, args ...interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, Call{Method: method, Args: args, Params: params})
+
+	if queue := s.queued[method]; len(queue) > 0 {
+		s.queued[method] = queue[1:]
+		return queue[0].result, queue[0].err
+	}
+	if stub, ok := s.stubs[method]; ok {
+		return stub.result, stub.err
+	}
+	return nil, fmt.Errorf("fakes: no response scripted for %s", method)
+}
+
+// applyOptions returns the parameters produced by a method's functional
+// options.
+func applyOptions(options []func(*url.Values)) url.Values {
+	params := url.Values{}
+	for _, o := range options {
+		o(&params)
+	}
+	return params
+}
+
+// copyValues returns a copy of params, for recording parameters which the
+// caller goes on to modify.
+func copyValues(params url.Values) url.Values {
+	c := make(url.Values, len(params))
+	for k, v := range params {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
+
+// encodeValues returns the parameters a typed update struct is sent as.
+func encodeValues(v interface{}) url.Values {
+	params, err := admin.EncodeValues(v)
+	if err != nil {
+		return url.Values{}
+	}
+	return params
+}
@@ -0,0 +1,139 @@
+package fakes
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	duoapi "github.com/duosecurity/duo_api_golang"
+	"github.com/duosecurity/duo_api_golang/admin"
+	"github.com/duosecurity/duo_api_golang/authapi"
+)
+
+func TestAdminScriptedResponses(t *testing.T) {
+	fake := &Admin{}
+	first := &admin.GetUserResult{Response: admin.User{UserID: "DU1"}}
+	stub := &admin.GetUserResult{Response: admin.User{UserID: "DU2"}}
+	fake.Enqueue("GetUser", first, nil)
+	fake.Stub("GetUser", stub, nil)
+
+	var api admin.API = fake
+	result, err := api.GetUser("DU1")
+	if err != nil || result != first {
+		t.Fatalf("Expected the queued result, got %v, %v", result, err)
+	}
+	for i := 0; i < 2; i++ {
+		result, err = api.GetUser("DU2")
+		if err != nil || result != stub {
+			t.Fatalf("Expected the stubbed result, got %v, %v", result, err)
+		}
+	}
+
+	calls := fake.CallsTo("GetUser")
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 recorded calls, got %d", len(calls))
+	}
+	if calls[0].Args[0] != "DU1" || calls[2].Args[0] != "DU2" {
+		t.Errorf("Unexpected recorded arguments %v", calls)
+	}
+}
+
+func TestAdminRecordsOptions(t *testing.T) {
+	fake := &Admin{}
+	fake.Enqueue("GetUsers", &admin.GetUsersResult{}, nil)
+
+	if _, err := fake.GetUsers(admin.GetUsersUsername("jsmith"), admin.Limit(10)); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	params := fake.Calls()[0].Params
+	if params.Get("username") != "jsmith" || params.Get("limit") != "10" {
+		t.Errorf("Unexpected recorded parameters %v", params)
+	}
+}
+
+func TestAdminUsersIterator(t *testing.T) {
+	fake := &Admin{}
+	fake.Enqueue("Users", &admin.GetUsersResult{
+		StatResult: duoapi.StatResult{Stat: "OK"},
+		ListResult: admin.ListResult{Metadata: admin.ListResultMetadata{NextOffset: "1"}},
+		Response:   []admin.User{{UserID: "DU1"}},
+	}, nil)
+	fake.Enqueue("Users", &admin.GetUsersResult{
+		StatResult: duoapi.StatResult{Stat: "OK"},
+		Response:   []admin.User{{UserID: "DU2"}},
+	}, nil)
+
+	var api admin.API = fake
+	it := api.Users(admin.Limit(1))
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Item().UserID)
+	}
+	if it.Err() != nil || strings.Join(ids, ",") != "DU1,DU2" {
+		t.Fatalf("Unexpected iteration %v, %v", ids, it.Err())
+	}
+
+	calls := fake.CallsTo("Users")
+	if len(calls) != 2 || calls[0].Params.Get("offset") != "0" || calls[1].Params.Get("offset") != "1" {
+		t.Errorf("Unexpected recorded pages %v", calls)
+	}
+
+	fake.Enqueue("Users", &admin.GetUsersResult{StatResult: duoapi.StatResult{Stat: "FAIL"}}, nil)
+	it = api.Users()
+	if it.Next() {
+		t.Fatal("Expected a FAIL page to stop the iteration")
+	}
+	if _, ok := it.Err().(*admin.StatError); !ok {
+		t.Errorf("Expected a *admin.StatError, got %v", it.Err())
+	}
+}
+
+func TestAdminUnscripted(t *testing.T) {
+	fake := &Admin{}
+	result, err := fake.DeleteUser("DU1")
+	if result != nil {
+		t.Errorf("Expected nil result, got %v", result)
+	}
+	if err == nil || !strings.Contains(err.Error(), "DeleteUser") {
+		t.Errorf("Expected an error naming DeleteUser, got %v", err)
+	}
+}
+
+func TestAuthenticatorScriptedError(t *testing.T) {
+	fake := &Authenticator{}
+	boom := errors.New("boom")
+	fake.Enqueue("Auth", nil, boom)
+
+	var api authapi.Authenticator = fake
+	if _, err := api.Auth("push", authapi.AuthUsername("jsmith")); err != boom {
+		t.Fatalf("Expected scripted error, got %v", err)
+	}
+	call := fake.CallsTo("Auth")[0]
+	if call.Args[0] != "push" || call.Params.Get("username") != "jsmith" {
+		t.Errorf("Unexpected recorded call %v", call)
+	}
+
+	fake.Reset()
+	if len(fake.Calls()) != 0 {
+		t.Errorf("Expected Reset to forget recorded calls")
+	}
+}
+
+func TestScriptConcurrentUse(t *testing.T) {
+	fake := &Authenticator{}
+	fake.Stub("Ping", &authapi.PingResult{}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fake.Ping()
+		}()
+	}
+	wg.Wait()
+	if len(fake.CallsTo("Ping")) != 10 {
+		t.Errorf("Expected 10 recorded calls, got %d", len(fake.CallsTo("Ping")))
+	}
+}
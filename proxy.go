@@ -0,0 +1,270 @@
+package duoapi
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	socks5Version          = 0x05
+	socks5AuthNone         = 0x00
+	socks5AuthPassword     = 0x02
+	socks5AuthNoAcceptable = 0xff
+	socks5PasswordVersion  = 0x01
+	socks5CmdConnect       = 0x01
+	socks5AddrIPv4         = 0x01
+	socks5AddrDomain       = 0x03
+	socks5AddrIPv6         = 0x04
+)
+
+var socks5ReplyMessages = map[byte]string{
+	0x01: "general SOCKS server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "TTL expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+// proxyBypass reports whether connections to a host should skip the proxy.
+// Entries are matched case insensitively against the host without its port.
+// An entry of "*" matches every host, an entry starting with "." or "*."
+// matches that domain and all of its subdomains, and any other entry must
+// match the host exactly.
+type proxyBypass []string
+
+func (b proxyBypass) matches(hostport string) bool {
+	host := hostport
+	if h, _, err := net.SplitHostPort(hostport); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	for _, entry := range b {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		case strings.HasPrefix(entry, "*."):
+			entry = entry[1:]
+			fallthrough
+		case strings.HasPrefix(entry, "."):
+			if host == entry[1:] || strings.HasSuffix(host, entry) {
+				return true
+			}
+		case host == entry:
+			return true
+		}
+	}
+	return false
+}
+
+// wrap returns a proxy function which defers to proxy unless the request's
+// host is on the bypass list.
+func (b proxyBypass) wrap(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	if proxy == nil || len(b) == 0 {
+		return proxy
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if b.matches(req.URL.Host) {
+			return nil, nil
+		}
+		return proxy(req)
+	}
+}
+
+// proxyAuthorization builds the value of a Proxy-Authorization header using
+// basic authentication.
+func proxyAuthorization(username, password string) string {
+	auth := username + ":" + password
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
+}
+
+// socks5Dialer opens connections through a SOCKS5 proxy server, as described
+// in RFC 1928, optionally authenticating with a username and password as
+// described in RFC 1929.
+type socks5Dialer struct {
+	address  string
+	username string
+	password string
+	bypass   proxyBypass
+	forward  *net.Dialer
+}
+
+func newSOCKS5Dialer(address, username, password string, bypass proxyBypass) *socks5Dialer {
+	return &socks5Dialer{
+		address:  address,
+		username: username,
+		password: password,
+		bypass:   bypass,
+		forward: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+	}
+}
+
+// DialContext connects to addr through the proxy server, or directly if addr
+// is on the bypass list.
+func (d *socks5Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.bypass.matches(addr) {
+		return d.forward.DialContext(ctx, network, addr)
+	}
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return nil, fmt.Errorf("socks5: unsupported network %q", network)
+	}
+
+	conn, err := d.forward.DialContext(ctx, "tcp", d.address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound the handshake by the context's deadline, if any.
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	if err = d.handshake(conn, addr); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (d *socks5Dialer) handshake(conn net.Conn, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 0xffff {
+		return fmt.Errorf("socks5: invalid port %q", portStr)
+	}
+
+	// Method negotiation
+	methods := []byte{socks5AuthNone}
+	if d.username != "" {
+		methods = append(methods, socks5AuthPassword)
+	}
+	greeting := append([]byte{socks5Version, byte(len(methods))}, methods...)
+	if _, err = conn.Write(greeting); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 2)
+	if _, err = io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != socks5Version {
+		return fmt.Errorf("socks5: unexpected protocol version %d", reply[0])
+	}
+
+	switch reply[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if d.username == "" {
+			return errors.New("socks5: server requested credentials but none were configured")
+		}
+		if err = d.authenticate(conn); err != nil {
+			return err
+		}
+	case socks5AuthNoAcceptable:
+		return errors.New("socks5: no acceptable authentication methods")
+	default:
+		return fmt.Errorf("socks5: unsupported authentication method %d", reply[1])
+	}
+
+	// Connect request
+	req := []byte{socks5Version, socks5CmdConnect, 0x00}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(req, socks5AddrIPv4)
+			req = append(req, ip4...)
+		} else {
+			req = append(req, socks5AddrIPv6)
+			req = append(req, ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return fmt.Errorf("socks5: host name too long: %s", host)
+		}
+		req = append(req, socks5AddrDomain, byte(len(host)))
+		req = append(req, host...)
+	}
+	req = append(req, byte(port>>8), byte(port))
+	if _, err = conn.Write(req); err != nil {
+		return err
+	}
+
+	header := make([]byte, 4)
+	if _, err = io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[0] != socks5Version {
+		return fmt.Errorf("socks5: unexpected protocol version %d", header[0])
+	}
+	if header[1] != 0x00 {
+		msg, ok := socks5ReplyMessages[header[1]]
+		if !ok {
+			msg = "unknown error " + strconv.Itoa(int(header[1]))
+		}
+		return fmt.Errorf("socks5: connect to %s failed: %s", addr, msg)
+	}
+
+	// Discard the bound address and port.
+	var bound int
+	switch header[3] {
+	case socks5AddrIPv4:
+		bound = net.IPv4len
+	case socks5AddrIPv6:
+		bound = net.IPv6len
+	case socks5AddrDomain:
+		length := make([]byte, 1)
+		if _, err = io.ReadFull(conn, length); err != nil {
+			return err
+		}
+		bound = int(length[0])
+	default:
+		return fmt.Errorf("socks5: unknown address type %d", header[3])
+	}
+	_, err = io.ReadFull(conn, make([]byte, bound+2))
+	return err
+}
+
+func (d *socks5Dialer) authenticate(conn net.Conn) error {
+	if len(d.username) > 255 || len(d.password) > 255 {
+		return errors.New("socks5: username and password must be at most 255 bytes")
+	}
+	req := []byte{socks5PasswordVersion, byte(len(d.username))}
+	req = append(req, d.username...)
+	req = append(req, byte(len(d.password)))
+	req = append(req, d.password...)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[1] != 0x00 {
+		return errors.New("socks5: username/password authentication failed")
+	}
+	return nil
+}